@@ -14,3 +14,7 @@ export const getUserById = async (userId) => {
   const { data } = await usersApi.get(`/api/users/${userId}`);
   return data;
 };
+
+export const deleteAccount = async () => {
+  await usersApi.delete('/api/users/me');
+};
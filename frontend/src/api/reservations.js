@@ -1,4 +1,4 @@
-import { reservationsApi } from './axios';
+import { reservationsApi, STORAGE_KEYS } from './axios';
 
 const BASE_PATH = '/api/reservations';
 
@@ -7,11 +7,11 @@ export const listReservations = async (params = {}) => {
   return data;
 };
 
-export const listUserReservations = async (userId) => {
+export const listUserReservations = async (userId, params = {}) => {
   if (!userId) {
     return [];
   }
-  const { data } = await reservationsApi.get(`${BASE_PATH}/user/${userId}`);
+  const { data } = await reservationsApi.get(`${BASE_PATH}/user/${userId}`, { params });
   return data;
 };
 
@@ -25,6 +25,11 @@ export const createReservation = async (payload) => {
   return data;
 };
 
+export const quoteReservation = async (payload) => {
+  const { data } = await reservationsApi.post(`${BASE_PATH}/quote`, payload);
+  return data;
+};
+
 export const updateReservation = async ({ reservationId, payload }) => {
   const { data } = await reservationsApi.put(`${BASE_PATH}/${reservationId}`, payload);
   return data;
@@ -39,9 +44,104 @@ export const confirmReservation = async ({ reservationId, payload }) => {
   return data;
 };
 
-export const getAvailableTables = async ({ date, mealType }) => {
+export const getAvailableTables = async ({ date, mealType, accessible }) => {
   const { data } = await reservationsApi.get('/api/tables/available', {
-    params: { date, meal_type: mealType },
+    params: { date, meal_type: mealType, accessible: accessible || undefined },
+  });
+  return data;
+};
+
+export const getAvailabilityCalendar = async ({ from, to, mealType, guests }) => {
+  const { data } = await reservationsApi.get('/api/tables/calendar', {
+    params: { from, to, meal_type: mealType, guests },
+  });
+  return data;
+};
+
+// Live status of each table of the meal today, for the host stand
+export const getFloorStatus = async (mealType) => {
+  const { data } = await reservationsApi.get('/api/tables/status', { params: { meal_type: mealType } });
+  return data;
+};
+
+export const seatWalkIn = async (payload) => {
+  const { data } = await reservationsApi.post('/api/walk-ins', payload);
+  return data;
+};
+
+// The guests have left: the table goes into cleaning
+export const completeReservation = async (reservationId) => {
+  const { data } = await reservationsApi.post(`${BASE_PATH}/${reservationId}/complete`);
+  return data;
+};
+
+// Keeps a table for the guest while they finish booking it; the token books it
+export const holdTable = async (payload) => {
+  const { data } = await reservationsApi.post('/api/holds', payload);
+  return data;
+};
+
+// Cancels or moves the reservations of a service; the job runs in the background
+export const startBulkJob = async (payload) => {
+  const { data } = await reservationsApi.post('/api/admin/bulk-jobs', payload);
+  return data;
+};
+
+export const getBulkJob = async (jobId) => {
+  const { data } = await reservationsApi.get(`/api/admin/bulk-jobs/${jobId}`);
+  return data;
+};
+
+export const getCheckInToken = async (reservationId) => {
+  const { data } = await reservationsApi.get(`${BASE_PATH}/${reservationId}/checkin`);
+  return data;
+};
+
+// Downloads the invoice PDF (issued on first download) and saves it through a temporary link
+export const downloadInvoice = async (reservationId) => {
+  const response = await reservationsApi.get(`${BASE_PATH}/${reservationId}/invoice.pdf`, { responseType: 'blob' });
+  const match = /filename="([^"]+)"/.exec(response.headers['content-disposition'] || '');
+  const url = URL.createObjectURL(response.data);
+  const link = document.createElement('a');
+  link.href = url;
+  link.download = match ? match[1] : `factura-${reservationId}.pdf`;
+  link.click();
+  URL.revokeObjectURL(url);
+};
+
+// Opens the day's service sheet (format: 'pdf' or 'html') in a new tab
+export const openServiceSheet = async ({ date, format = 'pdf' }) => {
+  const response = await reservationsApi.get('/api/service-sheet', {
+    params: { date: date || undefined, format },
+    responseType: 'blob',
   });
+  const url = URL.createObjectURL(response.data);
+  window.open(url, '_blank', 'noopener');
+  setTimeout(() => URL.revokeObjectURL(url), 60_000);
+};
+
+export const getMenu = async (mealType) => {
+  const { data } = await reservationsApi.get('/api/menu', { params: { meal_type: mealType } });
+  return data;
+};
+
+// Replaces the whole pre-order; an empty list clears it
+export const updatePreOrder = async (reservationId, items) => {
+  const { data } = await reservationsApi.put(`${BASE_PATH}/${reservationId}/pre-order`, { items });
   return data;
 };
+
+export const checkIn = async (token) => {
+  const { data } = await reservationsApi.post('/api/checkin', { token });
+  return data;
+};
+
+// URL of the live change stream; EventSource cannot send headers, so the token goes in the query
+export const getReservationStreamUrl = ({ date, mealType } = {}) => {
+  const url = new URL(`${BASE_PATH}/stream`, reservationsApi.defaults.baseURL);
+  const token = localStorage.getItem(STORAGE_KEYS.token);
+  if (token) url.searchParams.set('access_token', token);
+  if (date) url.searchParams.set('date', date);
+  if (mealType) url.searchParams.set('meal_type', mealType);
+  return url.toString();
+};
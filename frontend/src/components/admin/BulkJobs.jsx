@@ -0,0 +1,124 @@
+import { useState } from 'react';
+import { Layers } from 'lucide-react';
+
+import { useBulkJob, useStartBulkJob } from '../../hooks/useReservations';
+import { MEAL_TYPES } from '../../utils/constants';
+
+const RESULT_LABELS = { cancelled: 'Cancelada', moved: 'Movida', failed: 'Falló' };
+
+const initialForm = { action: 'cancel', date: '', meal_type: '', table_number: '', reason: '', to_date: '', to_table: '' };
+
+const toNumber = (value) => (value ? Number(value) : undefined);
+
+// Closes a service or moves its reservations, showing the outcome for each one
+export const BulkJobs = () => {
+  const [form, setForm] = useState(initialForm);
+  const [jobId, setJobId] = useState(null);
+  const startMutation = useStartBulkJob();
+  const { data: job } = useBulkJob(jobId);
+
+  const handleChange = (event) => {
+    const { name, value } = event.target;
+    setForm((prev) => ({ ...prev, [name]: value }));
+  };
+
+  const handleSubmit = async (event) => {
+    event.preventDefault();
+    const payload = {
+      action: form.action,
+      select: { date: form.date, meal_type: form.meal_type || undefined, table_number: toNumber(form.table_number) },
+    };
+    if (form.action === 'cancel') {
+      payload.reason = form.reason.trim();
+    } else {
+      payload.move_to = { date: form.to_date || undefined, table_number: toNumber(form.to_table) };
+    }
+
+    const started = await startMutation.mutateAsync(payload);
+    setJobId(started.id);
+  };
+
+  return (
+    <section className="mt-6 rounded-3xl border border-slate-100 bg-white p-6 shadow-soft">
+      <h2 className="flex items-center gap-2 font-display text-2xl font-semibold text-slate-900">
+        <Layers size={20} /> Operaciones masivas
+      </h2>
+      <p className="text-sm text-slate-500">
+        Cancelá o mové todas las reservas de un servicio. Cada cliente recibe el aviso de su reserva.
+      </p>
+
+      <form onSubmit={handleSubmit} className="mt-4 grid gap-3 sm:grid-cols-3">
+        <select name="action" value={form.action} onChange={handleChange} className="luxury-input">
+          <option value="cancel">Cancelar</option>
+          <option value="move">Mover</option>
+        </select>
+        <input type="date" name="date" required value={form.date} onChange={handleChange} className="luxury-input" />
+        <select name="meal_type" value={form.meal_type} onChange={handleChange} className="luxury-input">
+          <option value="">Todos los servicios</option>
+          {MEAL_TYPES.map((m) => (
+            <option key={m.value} value={m.value}>
+              {m.label}
+            </option>
+          ))}
+        </select>
+        <input
+          type="number"
+          name="table_number"
+          min={1}
+          value={form.table_number}
+          onChange={handleChange}
+          placeholder="Mesa (opcional)"
+          className="luxury-input"
+        />
+
+        {form.action === 'cancel' ? (
+          <input
+            type="text"
+            name="reason"
+            required
+            maxLength={500}
+            value={form.reason}
+            onChange={handleChange}
+            placeholder="Motivo, se informa al cliente"
+            className="luxury-input sm:col-span-2"
+          />
+        ) : (
+          <>
+            <input type="date" name="to_date" value={form.to_date} onChange={handleChange} className="luxury-input" aria-label="Nueva fecha" />
+            <input
+              type="number"
+              name="to_table"
+              min={1}
+              value={form.to_table}
+              onChange={handleChange}
+              placeholder="Nueva mesa (opcional)"
+              className="luxury-input"
+            />
+          </>
+        )}
+
+        <button type="submit" disabled={startMutation.isPending} className="luxury-button sm:col-span-3">
+          {form.action === 'cancel' ? 'Cancelar reservas' : 'Mover reservas'}
+        </button>
+      </form>
+
+      {job && (
+        <div className="mt-4">
+          <p className="text-sm font-semibold text-slate-700">
+            {job.status === 'completed' ? 'Terminado' : 'En curso'}: {job.succeeded} de {job.total} correctas
+            {job.failed > 0 && `, ${job.failed} con error`}
+          </p>
+          <ul className="mt-2 space-y-1 text-sm">
+            {job.results.map((result) => (
+              <li key={result.reservation_id} className={result.status === 'failed' ? 'text-rose-600' : 'text-slate-600'}>
+                {result.reservation_id.slice(-6)} · {RESULT_LABELS[result.status]}
+                {result.table_number && ` · mesa ${result.table_number}`}
+                {result.error && ` · ${result.error}`}
+              </li>
+            ))}
+          </ul>
+        </div>
+      )}
+    </section>
+  );
+};
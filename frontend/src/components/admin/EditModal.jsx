@@ -1,5 +1,5 @@
 import { useEffect, useState } from 'react';
-import { MEAL_TYPES, RESERVATION_STATUSES } from '../../utils/constants';
+import { EDITABLE_STATUSES, MEAL_TYPES, RESERVATION_STATUSES } from '../../utils/constants';
 
 const fieldDefinitions = [
   { name: 'table_number', label: 'Mesa', type: 'number' },
@@ -37,7 +37,8 @@ export const EditModal = ({ open, reservation, onClose, onSave, loading }) => {
         table_number: Number(formValues.table_number),
         guests: Number(formValues.guests),
         meal_type: formValues.meal_type,
-        status: formValues.status,
+        // Only a changed status is sent, so editing a seated reservation keeps it seated
+        status: formValues.status !== reservation.status ? formValues.status : undefined,
       },
     });
   };
@@ -57,11 +58,18 @@ export const EditModal = ({ open, reservation, onClose, onSave, loading }) => {
                   onChange={handleChange}
                   className="mt-1 w-full rounded-xl border border-slate-200 px-3 py-2 text-slate-700 focus:border-primary-300 focus:outline-none focus:ring-2 focus:ring-primary-100"
                 >
-                  {field.options.map((option) => (
-                    <option key={option.value} value={option.value}>
-                      {option.label}
-                    </option>
-                  ))}
+                  {field.options
+                    .filter(
+                      (option) =>
+                        field.name !== 'status' ||
+                        EDITABLE_STATUSES.includes(option.value) ||
+                        option.value === reservation.status,
+                    )
+                    .map((option) => (
+                      <option key={option.value} value={option.value}>
+                        {option.label}
+                      </option>
+                    ))}
                 </select>
               ) : (
                 <input
@@ -0,0 +1,148 @@
+import { useState } from 'react';
+import { Accessibility, DoorOpen, UserPlus } from 'lucide-react';
+
+import { useCompleteReservation, useFloorStatus, useSeatWalkIn } from '../../hooks/useReservations';
+import { MEAL_TYPES, TABLE_STATUSES, labelOf } from '../../utils/constants';
+
+const STATUS_STYLES = {
+  free: 'border-emerald-200 bg-emerald-50 text-emerald-900',
+  reserved_soon: 'border-amber-200 bg-amber-50 text-amber-900',
+  occupied: 'border-rose-200 bg-rose-50 text-rose-900',
+  cleaning: 'border-slate-200 bg-slate-100 text-slate-600',
+};
+
+const formatTime = (value) =>
+  value ? new Date(value).toLocaleTimeString('es-AR', { hour: '2-digit', minute: '2-digit' }) : '';
+
+// Form to seat guests without a reservation at a free table
+const WalkInForm = ({ table, mealType, onDone }) => {
+  const [guests, setGuests] = useState(Math.min(2, table.capacity));
+  const [guestName, setGuestName] = useState('');
+  const seatMutation = useSeatWalkIn();
+
+  const handleSubmit = async (event) => {
+    event.preventDefault();
+    await seatMutation.mutateAsync({
+      table_number: table.table_number,
+      meal_type: mealType,
+      guests: Number(guests),
+      guest_name: guestName.trim() || undefined,
+    });
+    onDone();
+  };
+
+  return (
+    <form onSubmit={handleSubmit} className="mt-3 space-y-2">
+      <input
+        type="number"
+        min={1}
+        max={table.capacity}
+        value={guests}
+        onChange={(e) => setGuests(e.target.value)}
+        className="luxury-input"
+        aria-label="Personas"
+      />
+      <input
+        type="text"
+        maxLength={100}
+        value={guestName}
+        onChange={(e) => setGuestName(e.target.value)}
+        placeholder="Nombre (opcional)"
+        className="luxury-input"
+      />
+      <div className="flex gap-2">
+        <button type="submit" disabled={seatMutation.isPending} className="luxury-button flex-1 text-sm">
+          Sentar
+        </button>
+        <button type="button" onClick={onDone} className="text-sm font-semibold underline">
+          Cancelar
+        </button>
+      </div>
+    </form>
+  );
+};
+
+// Live table map of the host stand: what each table is doing right now
+export const FloorStatus = () => {
+  const [mealType, setMealType] = useState('dinner');
+  const [seatingTable, setSeatingTable] = useState(null);
+  const floorQuery = useFloorStatus(mealType);
+  const completeMutation = useCompleteReservation();
+
+  const floor = floorQuery.data;
+
+  return (
+    <section className="mt-6 rounded-3xl border border-slate-100 bg-white p-6 shadow-soft">
+      <div className="flex flex-wrap items-center justify-between gap-3">
+        <div>
+          <h2 className="font-display text-2xl font-semibold text-slate-900">Salón en vivo</h2>
+          {floor && (
+            <p className="text-sm text-slate-500">
+              {TABLE_STATUSES.map((s) => `${floor.counts[s.value] ?? 0} ${s.label.toLowerCase()}`).join(' · ')}
+            </p>
+          )}
+        </div>
+        <select value={mealType} onChange={(e) => setMealType(e.target.value)} className="luxury-input w-auto">
+          {MEAL_TYPES.map((m) => (
+            <option key={m.value} value={m.value}>
+              {m.label}
+            </option>
+          ))}
+        </select>
+      </div>
+
+      {floorQuery.isError && <p className="mt-4 text-sm text-rose-600">No pudimos cargar el estado de las mesas.</p>}
+
+      <div className="mt-4 grid gap-3 sm:grid-cols-3 lg:grid-cols-5">
+        {floor?.tables.map((table) => (
+          <div key={table.table_number} className={`rounded-2xl border p-4 ${STATUS_STYLES[table.status]}`}>
+            <div className="flex items-center justify-between">
+              <p className="text-lg font-semibold">Mesa {table.table_number}</p>
+              {table.accessible && <Accessibility size={16} aria-label="Accesible" />}
+            </div>
+            <p className="text-xs">{table.capacity} personas</p>
+            <p className="mt-2 text-sm font-semibold">{labelOf(TABLE_STATUSES, table.status)}</p>
+
+            {table.status === 'occupied' && (
+              <p className="text-xs">
+                {table.walk_in ? 'Sin reserva' : 'Reserva'} · {table.guests} pers. desde {formatTime(table.since)}
+              </p>
+            )}
+            {table.status === 'cleaning' && <p className="text-xs">Lista a las {formatTime(table.until)}</p>}
+            {table.status === 'reserved_soon' && (
+              <p className="text-xs">
+                {table.guests} pers. a las {formatTime(table.since)}
+              </p>
+            )}
+            {table.status === 'free' && table.next_reservation && (
+              <p className="text-xs">Próxima reserva {formatTime(table.next_reservation)}</p>
+            )}
+
+            {table.status === 'occupied' && (
+              <button
+                type="button"
+                disabled={completeMutation.isPending}
+                onClick={() => completeMutation.mutate(table.reservation_id)}
+                className="mt-3 inline-flex items-center gap-1 text-sm font-semibold underline"
+              >
+                <DoorOpen size={14} /> Liberar mesa
+              </button>
+            )}
+            {table.status === 'free' &&
+              (seatingTable === table.table_number ? (
+                <WalkInForm table={table} mealType={mealType} onDone={() => setSeatingTable(null)} />
+              ) : (
+                <button
+                  type="button"
+                  onClick={() => setSeatingTable(table.table_number)}
+                  className="mt-3 inline-flex items-center gap-1 text-sm font-semibold underline"
+                >
+                  <UserPlus size={14} /> Sentar sin reserva
+                </button>
+              ))}
+          </div>
+        ))}
+      </div>
+    </section>
+  );
+};
@@ -24,7 +24,7 @@ export const ReservationTable = ({ reservations = [], onEdit, onDelete }) => {
           {reservations.map((reservation) => (
             <tr key={reservation.id} className="border-t border-slate-100">
               <td className="px-4 py-3 font-mono text-xs text-slate-400">{reservation.id}</td>
-              <td className="px-4 py-3">{reservation.owner_id}</td>
+              <td className="px-4 py-3">{reservation.walk_in ? reservation.guest_name || 'Sin reserva' : reservation.owner_id}</td>
               <td className="px-4 py-3">{formatDateTime(reservation.date_time)}</td>
               <td className="px-4 py-3">{reservation.guests}</td>
               <td className="px-4 py-3 font-semibold capitalize">{formatStatus(reservation.status)}</td>
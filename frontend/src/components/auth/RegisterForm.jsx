@@ -18,6 +18,7 @@ export const RegisterForm = () => {
       email: '',
       first_name: '',
       last_name: '',
+      phone: '',
       password: '',
     },
   });
@@ -71,6 +72,12 @@ export const RegisterForm = () => {
         {renderField('first_name', 'Nombre')}
         {renderField('last_name', 'Apellido')}
       </div>
+      {renderField('phone', 'Teléfono (opcional, para avisos del restaurante)', 'tel', {
+        pattern: {
+          value: /^\+?[0-9][0-9 ()-]{5,30}$/,
+          message: 'Ingresá un teléfono válido',
+        },
+      })}
       {renderField('password', 'Contraseña', 'password', {
         required: 'Campo obligatorio',
         minLength: { value: 8, message: 'Al menos 8 caracteres' },
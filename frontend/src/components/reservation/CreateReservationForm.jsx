@@ -1,8 +1,11 @@
 import { useState } from 'react';
 import { CalendarDays, Users, Utensils, Hash } from 'lucide-react';
-import { useQuery } from '@tanstack/react-query';
-import { MEAL_TYPES } from '../../utils/constants';
-import { getAvailableTables } from '../../api/reservations';
+import { useQuery, useQueryClient } from '@tanstack/react-query';
+import { ALLERGENS, DIETARY_TAGS, MEAL_TYPES, OCCASIONS } from '../../utils/constants';
+import { getAvailableTables, holdTable } from '../../api/reservations';
+
+// Reservations are booked at noon of the selected date
+const dateTimeFor = (date) => new Date(date + 'T12:00:00').toISOString();
 
 export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
   const [formData, setFormData] = useState({
@@ -12,14 +15,24 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
     meal_type: '',
     date: '',
     special_requests: '',
+    allergies: [],
+    dietary: [],
+    wheelchair: false,
+    high_chairs: 0,
+    occasion: '',
   });
 
   const [selectedTable, setSelectedTable] = useState(null);
+  // The selected table is held for us while the form is completed
+  const [hold, setHold] = useState(null);
+  const [holdError, setHoldError] = useState('');
+  const queryClient = useQueryClient();
 
   // Fetch available tables when date and meal_type are selected
   const { data: availableTables = [], isLoading: loadingTables } = useQuery({
-    queryKey: ['available-tables', formData.date, formData.meal_type],
-    queryFn: () => getAvailableTables({ date: formData.date, mealType: formData.meal_type }),
+    queryKey: ['available-tables', formData.date, formData.meal_type, formData.wheelchair],
+    queryFn: () =>
+      getAvailableTables({ date: formData.date, mealType: formData.meal_type, accessible: formData.wheelchair }),
     enabled: Boolean(formData.date && formData.meal_type),
     staleTime: 1000 * 30, // 30 seconds
   });
@@ -31,17 +44,57 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
     // Reset table selection when date or meal_type changes
     if (name === 'date' || name === 'meal_type') {
       setSelectedTable(null);
+      setHold(null);
       setFormData((prev) => ({ ...prev, table_number: '', guests: '' }));
     }
   };
 
-  const handleTableSelect = (table) => {
+  const toggleTag = (field, value) =>
+    setFormData((prev) => ({
+      ...prev,
+      [field]: prev[field].includes(value) ? prev[field].filter((v) => v !== value) : [...prev[field], value],
+    }));
+
+  // Only accessible tables are offered when a wheelchair is needed
+  const handleWheelchairChange = (e) => {
+    const { checked } = e.target;
+    setFormData((prev) => ({ ...prev, wheelchair: checked }));
+    if (checked && selectedTable && !selectedTable.accessible) {
+      setSelectedTable(null);
+      setHold(null);
+      setFormData((prev) => ({ ...prev, table_number: '', guests: '' }));
+    }
+  };
+
+  const handleTableSelect = async (table) => {
     setSelectedTable(table);
+    setHold(null);
+    setHoldError('');
     setFormData((prev) => ({
       ...prev,
       table_number: table.table_number,
       guests: table.capacity, // Auto-fill with table capacity
     }));
+
+    try {
+      setHold(
+        await holdTable({
+          table_number: table.table_number,
+          guests: table.capacity,
+          meal_type: formData.meal_type,
+          date_time: dateTimeFor(formData.date),
+        }),
+      );
+    } catch (error) {
+      if (error?.response?.status === 409) {
+        // Someone else is booking it: offer the tables still free
+        setSelectedTable(null);
+        setFormData((prev) => ({ ...prev, table_number: '', guests: '' }));
+        setHoldError('Otra persona está reservando esa mesa. Elegí otra.');
+        queryClient.invalidateQueries({ queryKey: ['available-tables'] });
+      }
+      // Otherwise the table is simply not held; the booking is checked on submit
+    }
   };
 
   const handleSubmit = (e) => {
@@ -52,16 +105,18 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
       return;
     }
 
-    // Create datetime at noon (12:00) for the selected date
-    const dateTime = new Date(formData.date + 'T12:00:00');
-
     const payload = {
       owner_id: formData.owner_id,
       table_number: formData.table_number,
       guests: formData.guests,
       meal_type: formData.meal_type,
-      date_time: dateTime.toISOString(),
+      date_time: dateTimeFor(formData.date),
       special_requests: formData.special_requests || undefined,
+      allergies: formData.allergies.length ? formData.allergies : undefined,
+      dietary: formData.dietary.length ? formData.dietary : undefined,
+      accessibility: { wheelchair: formData.wheelchair, high_chairs: Number(formData.high_chairs) || 0 },
+      occasion: formData.occasion || undefined,
+      hold_token: hold?.token,
     };
 
     onSubmit(payload);
@@ -113,6 +168,11 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
         </div>
       </div>
 
+      <label className="flex items-center gap-2 text-sm text-slate-700 dark:text-slate-300">
+        <input type="checkbox" checked={formData.wheelchair} onChange={handleWheelchairChange} />
+        Necesitamos una mesa accesible en silla de ruedas
+      </label>
+
       {/* Step 2: Show Available Tables */}
       {formData.date && formData.meal_type && (
         <div>
@@ -160,6 +220,14 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
               ))}
             </div>
           )}
+
+          {hold && (
+            <p className="mt-2 text-xs text-slate-500 dark:text-slate-400">
+              Mesa retenida hasta las{' '}
+              {new Date(hold.expires_at).toLocaleTimeString('es-AR', { hour: '2-digit', minute: '2-digit' })}
+            </p>
+          )}
+          {holdError && <p className="mt-2 text-sm text-red-600 dark:text-red-400">{holdError}</p>}
         </div>
       )}
 
@@ -188,7 +256,55 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
         </div>
       )}
 
-      {/* Step 4: Special Requests */}
+      {/* Step 4: Structured requests the kitchen and floor staff act on */}
+      {selectedTable && (
+        <div className="space-y-4">
+          <TagPicker
+            label="Alergias"
+            options={ALLERGENS}
+            selected={formData.allergies}
+            onToggle={(value) => toggleTag('allergies', value)}
+          />
+          <TagPicker
+            label="Preferencias alimentarias"
+            options={DIETARY_TAGS}
+            selected={formData.dietary}
+            onToggle={(value) => toggleTag('dietary', value)}
+          />
+          <div className="grid gap-6 sm:grid-cols-2">
+            <div>
+              <label htmlFor="occasion" className="mb-2 block text-sm font-medium text-slate-700 dark:text-slate-300">
+                Ocasión (opcional)
+              </label>
+              <select id="occasion" name="occasion" value={formData.occasion} onChange={handleChange} className="luxury-input">
+                <option value="">Ninguna</option>
+                {OCCASIONS.map((occasion) => (
+                  <option key={occasion.value} value={occasion.value}>
+                    {occasion.label}
+                  </option>
+                ))}
+              </select>
+            </div>
+            <div>
+              <label htmlFor="high_chairs" className="mb-2 block text-sm font-medium text-slate-700 dark:text-slate-300">
+                Sillas para bebé
+              </label>
+              <input
+                type="number"
+                id="high_chairs"
+                name="high_chairs"
+                min="0"
+                max={formData.guests || selectedTable.capacity}
+                value={formData.high_chairs}
+                onChange={handleChange}
+                className="luxury-input"
+              />
+            </div>
+          </div>
+        </div>
+      )}
+
+      {/* Step 5: Special Requests */}
       {selectedTable && (
         <div>
           <label htmlFor="special_requests" className="mb-2 block text-sm font-medium text-slate-700 dark:text-slate-300">
@@ -201,7 +317,7 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
             value={formData.special_requests}
             onChange={handleChange}
             className="luxury-input resize-none"
-            placeholder="Ej: Mesa cerca de la ventana, cumpleaños, alergias..."
+            placeholder="Ej: Mesa cerca de la ventana..."
           />
         </div>
       )}
@@ -216,3 +332,26 @@ export const CreateReservationForm = ({ onSubmit, loading, userId }) => {
     </form>
   );
 };
+
+const TagPicker = ({ label, options, selected, onToggle }) => (
+  <div>
+    <p className="mb-2 text-sm font-medium text-slate-700 dark:text-slate-300">{label}</p>
+    <div className="flex flex-wrap gap-2">
+      {options.map((option) => (
+        <button
+          key={option.value}
+          type="button"
+          aria-pressed={selected.includes(option.value)}
+          onClick={() => onToggle(option.value)}
+          className={`rounded-full border px-3 py-1 text-xs transition ${
+            selected.includes(option.value)
+              ? 'border-primary-500 bg-primary-50 text-primary-700 dark:border-primary-400 dark:bg-primary-950 dark:text-primary-300'
+              : 'border-slate-200 text-slate-600 hover:border-primary-300 dark:border-slate-700 dark:text-slate-400'
+          }`}
+        >
+          {option.label}
+        </button>
+      ))}
+    </div>
+  </div>
+);
@@ -0,0 +1,117 @@
+import { useEffect, useState } from 'react';
+import { ChefHat, Minus, Plus } from 'lucide-react';
+import { useMenu, useUpdatePreOrder } from '../../hooks/useReservations';
+import { formatCurrency } from '../../utils/formatters';
+import { ALLERGENS, labelOf } from '../../utils/constants';
+
+const EDITABLE_STATUSES = ['pending', 'confirmed'];
+
+const quantitiesOf = (preOrder = []) =>
+  Object.fromEntries(preOrder.map((line) => [line.menu_item_id, line.quantity]));
+
+// Lets the owner pick menu items ahead of the reservation. The server enforces the cutoff.
+export const PreOrderEditor = ({ reservation, editable }) => {
+  const mealType = reservation.meal_type || reservation.mealType;
+  const canEdit = editable && EDITABLE_STATUSES.includes(reservation.status);
+  const menuQuery = useMenu(mealType, { enabled: canEdit });
+  const mutation = useUpdatePreOrder();
+  const [quantities, setQuantities] = useState(() => quantitiesOf(reservation.pre_order));
+
+  useEffect(() => {
+    setQuantities(quantitiesOf(reservation.pre_order));
+  }, [reservation.pre_order]);
+
+  const preOrder = reservation.pre_order || [];
+  if (!canEdit) {
+    if (preOrder.length === 0) return null;
+    return (
+      <section className="mt-6 elegant-card p-4">
+        <h3 className="flex items-center gap-2 text-sm font-semibold text-slate-700 dark:text-slate-300">
+          <ChefHat size={16} /> Pedido anticipado
+        </h3>
+        <ul className="mt-3 space-y-1 text-sm text-slate-600 dark:text-slate-400">
+          {preOrder.map((line) => (
+            <li key={line.menu_item_id}>
+              {line.quantity} × {line.name}
+              {line.notes ? ` (${line.notes})` : ''}
+            </li>
+          ))}
+        </ul>
+      </section>
+    );
+  }
+
+  const setQuantity = (itemId, quantity) =>
+    setQuantities((current) => ({ ...current, [itemId]: Math.max(0, quantity) }));
+
+  const handleSave = () => {
+    const items = Object.entries(quantities)
+      .filter(([, quantity]) => quantity > 0)
+      .map(([menuItemId, quantity]) => ({ menu_item_id: menuItemId, quantity }));
+    mutation.mutate({ reservationId: reservation.id, items });
+  };
+
+  const menu = menuQuery.data || [];
+
+  return (
+    <section className="mt-6 elegant-card p-4">
+      <h3 className="flex items-center gap-2 text-sm font-semibold text-slate-700 dark:text-slate-300">
+        <ChefHat size={16} /> Pedido anticipado
+      </h3>
+      <p className="text-xs text-slate-500 dark:text-slate-400">
+        Elegí platos o menús para que la cocina los tenga listos. Podés cambiarlo hasta el día anterior.
+      </p>
+
+      {menuQuery.isLoading && <p className="mt-3 text-sm text-slate-500">Cargando menú…</p>}
+      {!menuQuery.isLoading && menu.length === 0 && (
+        <p className="mt-3 text-sm text-slate-500">No hay platos disponibles para este servicio.</p>
+      )}
+
+      <ul className="mt-3 divide-y divide-slate-100 dark:divide-slate-800">
+        {menu.map((item) => {
+          const quantity = quantities[item.id] || 0;
+          return (
+            <li key={item.id} className="flex items-center justify-between gap-4 py-2">
+              <div>
+                <p className="text-sm font-semibold text-slate-800 dark:text-slate-200">{item.name}</p>
+                {item.description && <p className="text-xs text-slate-500">{item.description}</p>}
+                {item.allergens?.length > 0 && (
+                  <p className="text-xs text-amber-600">
+                    Contiene: {item.allergens.map((a) => labelOf(ALLERGENS, a)).join(', ')}
+                  </p>
+                )}
+              </div>
+              <div className="flex items-center gap-3">
+                <span className="text-sm text-slate-600">{formatCurrency(item.price)}</span>
+                <button
+                  type="button"
+                  aria-label={`Quitar ${item.name}`}
+                  onClick={() => setQuantity(item.id, quantity - 1)}
+                  disabled={quantity === 0}
+                  className="rounded-full border border-slate-200 p-1 disabled:opacity-40"
+                >
+                  <Minus size={14} />
+                </button>
+                <span className="w-6 text-center text-sm font-semibold">{quantity}</span>
+                <button
+                  type="button"
+                  aria-label={`Agregar ${item.name}`}
+                  onClick={() => setQuantity(item.id, quantity + 1)}
+                  className="rounded-full border border-slate-200 p-1"
+                >
+                  <Plus size={14} />
+                </button>
+              </div>
+            </li>
+          );
+        })}
+      </ul>
+
+      <div className="mt-4 flex justify-end">
+        <button type="button" onClick={handleSave} disabled={mutation.isPending} className="luxury-button text-sm">
+          {mutation.isPending ? 'Guardando…' : 'Guardar pedido'}
+        </button>
+      </div>
+    </section>
+  );
+};
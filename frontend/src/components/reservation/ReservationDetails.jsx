@@ -1,9 +1,29 @@
-import { CalendarDays, Users, BadgeCheck, Utensils } from 'lucide-react';
+import { useState } from 'react';
+import { CalendarDays, Users, BadgeCheck, Utensils, FileText } from 'lucide-react';
+import { downloadInvoice } from '../../api/reservations';
 import { formatCurrency, formatDateTime, formatStatus } from '../../utils/formatters';
+import { ALLERGENS, DIETARY_TAGS, OCCASIONS, labelOf } from '../../utils/constants';
+
+const INVOICEABLE_STATUSES = ['confirmed', 'seated', 'completed'];
 
 export const ReservationDetails = ({ reservation, requesterName }) => {
+  const [invoiceError, setInvoiceError] = useState('');
+  const [downloading, setDownloading] = useState(false);
+
   if (!reservation) return null;
 
+  const handleDownloadInvoice = async () => {
+    setInvoiceError('');
+    setDownloading(true);
+    try {
+      await downloadInvoice(reservation.id);
+    } catch {
+      setInvoiceError('No pudimos generar la factura. Intentá nuevamente.');
+    } finally {
+      setDownloading(false);
+    }
+  };
+
   const fallbackRequester =
     reservation.owner_name || reservation.ownerName || reservation.owner_id || reservation.ownerId;
 
@@ -47,6 +67,59 @@ export const ReservationDetails = ({ reservation, requesterName }) => {
           <DetailTile label="Monto estimado" value={formatCurrency(reservation.total_price || reservation.totalPrice)} />
         </div>
 
+        {reservation.price && (
+          <div className="rounded-2xl border border-slate-100 bg-slate-50 p-4 text-sm text-slate-600">
+            <PriceLine label="Precio base" value={reservation.price.base} />
+            {(reservation.price.discounts || []).map((discount) => (
+              <PriceLine
+                key={`${discount.rule}-${discount.code}`}
+                label={`${discount.description || discount.code} (−${discount.percent}%)`}
+                value={discount.amount}
+                negative
+              />
+            ))}
+            {(reservation.price.items || []).map((item) => (
+              <PriceLine key={item.menu_item_id} label={`${item.quantity} × ${item.name}`} value={item.amount} />
+            ))}
+            {(reservation.price.charges || []).map((charge) => (
+              <PriceLine
+                key={charge.code}
+                label={`${charge.kind === 'tax' ? 'Impuesto' : 'Servicio'} ${charge.code.toUpperCase()} (${charge.percent}%)`}
+                value={charge.amount}
+              />
+            ))}
+            <PriceLine label="Total" value={reservation.price.total} bold />
+            {reservation.price.previous_total && (
+              <p className="mt-2 text-xs text-slate-400">
+                Recalculado al {PRICE_TRIGGERS[reservation.price.trigger] || 'modificar'} la reserva (antes{' '}
+                {formatCurrency(reservation.price.previous_total)})
+              </p>
+            )}
+            {reservation.price.calculated_at && (
+              <p className="mt-1 text-xs text-slate-400">
+                Calculado el {formatDateTime(reservation.price.calculated_at)} · reglas {reservation.price.rules_version}
+              </p>
+            )}
+          </div>
+        )}
+
+        {INVOICEABLE_STATUSES.includes(reservation.status) && (
+          <div>
+            <button
+              type="button"
+              onClick={handleDownloadInvoice}
+              disabled={downloading}
+              className="inline-flex items-center gap-2 rounded-full border border-slate-200 px-4 py-2 text-sm font-semibold text-slate-700 hover:bg-slate-50 disabled:opacity-60"
+            >
+              <FileText size={16} />
+              {downloading ? 'Generando factura…' : 'Descargar factura'}
+            </button>
+            {invoiceError && <p className="mt-2 text-sm text-red-600">{invoiceError}</p>}
+          </div>
+        )}
+
+        <GuestRequests reservation={reservation} />
+
         {reservation.special_requests && (
           <div>
             <p className="text-xs uppercase tracking-wide text-slate-400">Notas</p>
@@ -60,6 +133,49 @@ export const ReservationDetails = ({ reservation, requesterName }) => {
   );
 };
 
+const GuestRequests = ({ reservation }) => {
+  const { allergies = [], dietary = [], accessibility = {}, occasion } = reservation;
+  const lines = [
+    allergies.length > 0 && ['Alergias', allergies.map((a) => labelOf(ALLERGENS, a)).join(', ')],
+    dietary.length > 0 && ['Preferencias', dietary.map((d) => labelOf(DIETARY_TAGS, d)).join(', ')],
+    accessibility.wheelchair && ['Accesibilidad', 'Mesa accesible en silla de ruedas'],
+    accessibility.high_chairs > 0 && ['Sillas para bebé', accessibility.high_chairs],
+    occasion && ['Ocasión', labelOf(OCCASIONS, occasion)],
+  ].filter(Boolean);
+  if (lines.length === 0) return null;
+
+  return (
+    <div>
+      <p className="text-xs uppercase tracking-wide text-slate-400">Pedidos</p>
+      <dl className="mt-2 space-y-1 text-sm text-slate-600">
+        {lines.map(([label, value]) => (
+          <div key={label} className="flex gap-2">
+            <dt className="font-semibold text-slate-700">{label}:</dt>
+            <dd>{value}</dd>
+          </div>
+        ))}
+      </dl>
+    </div>
+  );
+};
+
+const PRICE_TRIGGERS = {
+  create: 'crear',
+  update: 'modificar',
+  confirm: 'confirmar',
+  pre_order: 'cambiar el pedido anticipado de',
+};
+
+const PriceLine = ({ label, value, negative, bold }) => (
+  <div className={`flex justify-between py-1 ${bold ? 'font-semibold text-slate-900' : ''}`}>
+    <span>{label}</span>
+    <span>
+      {negative ? '− ' : ''}
+      {formatCurrency(value)}
+    </span>
+  </div>
+);
+
 const DetailTile = ({ icon, label, value }) => (
   <div className="rounded-2xl border border-slate-100 bg-white p-4 shadow-sm">
     <p className="text-xs uppercase tracking-wide text-slate-400">{label}</p>
@@ -2,7 +2,7 @@ import { createContext, useCallback, useMemo, useState } from 'react';
 import PropTypes from 'prop-types';
 import toast from 'react-hot-toast';
 
-import { login as loginRequest, registerUser as registerRequest } from '../api/auth';
+import { deleteAccount as deleteAccountRequest, login as loginRequest, registerUser as registerRequest } from '../api/auth';
 import { STORAGE_KEYS } from '../api/axios';
 
 export const AuthContext = createContext(undefined);
@@ -96,6 +96,17 @@ export const AuthProvider = ({ children }) => {
     toast.success('Sesión cerrada');
   }, [clearSession]);
 
+  const handleDeleteAccount = useCallback(async () => {
+    try {
+      await deleteAccountRequest();
+      clearSession();
+      toast.success('Tu cuenta y tus datos fueron eliminados');
+    } catch (error) {
+      toast.error('No pudimos eliminar tu cuenta. Intentá nuevamente.');
+      throw error;
+    }
+  }, [clearSession]);
+
   const value = useMemo(
     () => ({
       token,
@@ -106,9 +117,10 @@ export const AuthProvider = ({ children }) => {
       login: handleLogin,
       register: handleRegister,
       logout: handleLogout,
+      deleteAccount: handleDeleteAccount,
       setUser,
     }),
-    [handleLogin, handleLogout, handleRegister, token, user, loading],
+    [handleDeleteAccount, handleLogin, handleLogout, handleRegister, token, user, loading],
   );
 
   return <AuthContext.Provider value={value}>{children}</AuthContext.Provider>;
@@ -1,13 +1,22 @@
+import { useEffect } from 'react';
 import { useMutation, useQuery, useQueryClient } from '@tanstack/react-query';
 import toast from 'react-hot-toast';
 
 import {
+  completeReservation,
   confirmReservation,
   createReservation,
   deleteReservation,
+  getBulkJob,
+  getFloorStatus,
+  getMenu,
   getReservationById,
+  getReservationStreamUrl,
   listReservations,
   listUserReservations,
+  seatWalkIn,
+  startBulkJob,
+  updatePreOrder,
   updateReservation,
 } from '../api/reservations';
 
@@ -27,10 +36,10 @@ export const useReservations = (params = {}, options = {}) =>
     ...options,
   });
 
-export const useUserReservations = (userId, options = {}) =>
+export const useUserReservations = (userId, params = {}, options = {}) =>
   useQuery({
-    queryKey: ['reservations', 'user', userId],
-    queryFn: () => listUserReservations(userId),
+    queryKey: ['reservations', 'user', userId, params],
+    queryFn: () => listUserReservations(userId, params),
     enabled: Boolean(userId) && (options?.enabled ?? true),
     ...options,
   });
@@ -94,3 +103,94 @@ export const useConfirmReservation = () => {
     onError: () => toast.error('No pudimos confirmar la reserva'),
   });
 };
+
+export const useMenu = (mealType, options = {}) =>
+  useQuery({
+    queryKey: ['menu', mealType],
+    queryFn: () => getMenu(mealType),
+    enabled: Boolean(mealType) && (options?.enabled ?? true),
+    staleTime: 1000 * 60 * 5,
+    ...options,
+  });
+
+export const useUpdatePreOrder = () => {
+  const queryClient = useQueryClient();
+  return useMutation({
+    mutationFn: ({ reservationId, items }) => updatePreOrder(reservationId, items),
+    onSuccess: () => {
+      invalidateReservationQueries(queryClient);
+      toast.success('Pedido anticipado guardado');
+    },
+    onError: (error) => toast.error(error?.response?.data?.error ?? 'No pudimos guardar el pedido anticipado'),
+  });
+};
+
+// Under the 'reservations' key, so every reservation change refreshes it; the minute
+// refetch moves tables out of cleaning and into reserved-soon on time
+export const useFloorStatus = (mealType, options = {}) =>
+  useQuery({
+    queryKey: ['reservations', 'floor', mealType],
+    queryFn: () => getFloorStatus(mealType),
+    enabled: Boolean(mealType) && (options?.enabled ?? true),
+    refetchInterval: 1000 * 60,
+    ...options,
+  });
+
+export const useStartBulkJob = () =>
+  useMutation({
+    mutationFn: startBulkJob,
+    onError: (error) => toast.error(error?.response?.data?.error ?? 'No pudimos iniciar la operación'),
+  });
+
+// Polls a bulk job until it completes; its reservations refresh through the stream
+export const useBulkJob = (jobId) =>
+  useQuery({
+    queryKey: ['bulk-job', jobId],
+    queryFn: () => getBulkJob(jobId),
+    enabled: Boolean(jobId),
+    refetchInterval: (query) => (query.state.data?.status === 'completed' ? false : 2000),
+  });
+
+export const useSeatWalkIn = () => {
+  const queryClient = useQueryClient();
+  return useMutation({
+    mutationFn: seatWalkIn,
+    onSuccess: () => {
+      invalidateReservationQueries(queryClient);
+      invalidateSearchQueries(queryClient);
+      toast.success('Mesa ocupada');
+    },
+    onError: (error) => toast.error(error?.response?.data?.error ?? 'No pudimos sentar a los clientes'),
+  });
+};
+
+export const useCompleteReservation = () => {
+  const queryClient = useQueryClient();
+  return useMutation({
+    mutationFn: completeReservation,
+    onSuccess: () => {
+      invalidateReservationQueries(queryClient);
+      invalidateSearchQueries(queryClient);
+      toast.success('Mesa liberada');
+    },
+    onError: (error) => toast.error(error?.response?.data?.error ?? 'No pudimos liberar la mesa'),
+  });
+};
+
+const STREAM_EVENTS = ['create', 'update', 'confirm', 'cancel', 'checkin', 'complete', 'reset'];
+
+// Keeps the reservation queries fresh from the server's live change stream.
+// EventSource reconnects on its own and resumes with Last-Event-ID.
+export const useReservationStream = ({ date, mealType, enabled = true } = {}) => {
+  const queryClient = useQueryClient();
+
+  useEffect(() => {
+    if (!enabled || typeof EventSource === 'undefined') return undefined;
+
+    const source = new EventSource(getReservationStreamUrl({ date, mealType }));
+    const refresh = () => invalidateReservationQueries(queryClient);
+    STREAM_EVENTS.forEach((event) => source.addEventListener(event, refresh));
+
+    return () => source.close();
+  }, [queryClient, date, mealType, enabled]);
+};
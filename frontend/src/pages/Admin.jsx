@@ -1,22 +1,28 @@
 import { Navigate } from 'react-router-dom';
 import { useState } from 'react';
-import { ShieldCheck } from 'lucide-react';
+import { Printer, ShieldCheck } from 'lucide-react';
 
 import { useAuth } from '../hooks/useAuth';
-import { useReservations, useUpdateReservation, useDeleteReservation } from '../hooks/useReservations';
+import { useReservations, useReservationStream, useUpdateReservation, useDeleteReservation } from '../hooks/useReservations';
 import { Loader } from '../components/common/Loader';
 import { ErrorMessage } from '../components/common/ErrorMessage';
 import { ReservationTable } from '../components/admin/ReservationTable';
 import { EditModal } from '../components/admin/EditModal';
+import { FloorStatus } from '../components/admin/FloorStatus';
+import { BulkJobs } from '../components/admin/BulkJobs';
+import { openServiceSheet } from '../api/reservations';
 
 const Admin = () => {
   const { isAuthenticated, isAdmin } = useAuth();
   const [modalOpen, setModalOpen] = useState(false);
   const [selectedReservation, setSelectedReservation] = useState(null);
+  const [sheetDate, setSheetDate] = useState(() => new Date().toLocaleDateString('en-CA'));
+  const [sheetError, setSheetError] = useState('');
 
   const reservationsQuery = useReservations();
   const updateMutation = useUpdateReservation();
   const deleteMutation = useDeleteReservation();
+  useReservationStream({ enabled: isAuthenticated && isAdmin });
 
   if (!isAuthenticated) {
     return <Navigate to="/login" replace />;
@@ -38,6 +44,15 @@ const Admin = () => {
     reservationsQuery.refetch();
   };
 
+  const handlePrintSheet = async (format) => {
+    setSheetError('');
+    try {
+      await openServiceSheet({ date: sheetDate, format });
+    } catch {
+      setSheetError('No pudimos generar la hoja de servicio.');
+    }
+  };
+
   const handleSave = async ({ reservationId, payload }) => {
     await updateMutation.mutateAsync({ reservationId, payload });
     setModalOpen(false);
@@ -63,8 +78,32 @@ const Admin = () => {
         </p>
         <h1 className="mt-2 font-display text-3xl font-semibold">Control total del salón</h1>
         <p className="mt-2 text-primary-900/70">Editá, confirmá o eliminá reservas. Todos los cambios se replican en MongoDB y Solr.</p>
+        <div className="mt-4 flex flex-wrap items-center gap-3">
+          <input
+            type="date"
+            value={sheetDate}
+            onChange={(e) => setSheetDate(e.target.value)}
+            className="luxury-input w-auto"
+            aria-label="Fecha de la hoja de servicio"
+          />
+          <button
+            type="button"
+            onClick={() => handlePrintSheet('html')}
+            className="inline-flex items-center gap-2 rounded-full border border-primary-200 bg-white px-4 py-2 text-sm font-semibold"
+          >
+            <Printer size={16} /> Hoja de servicio
+          </button>
+          <button type="button" onClick={() => handlePrintSheet('pdf')} className="text-sm font-semibold underline">
+            Descargar PDF
+          </button>
+          {sheetError && <p className="text-sm text-rose-600">{sheetError}</p>}
+        </div>
       </section>
 
+      <FloorStatus />
+
+      <BulkJobs />
+
       <div className="mt-6">
         <ReservationTable reservations={reservations} onEdit={handleEdit} onDelete={handleDelete} />
       </div>
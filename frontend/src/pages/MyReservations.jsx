@@ -1,3 +1,4 @@
+import { useState } from 'react';
 import { Navigate, Link } from 'react-router-dom';
 import { useAuth } from '../hooks/useAuth';
 import { useUserReservations } from '../hooks/useReservations';
@@ -6,8 +7,13 @@ import { ErrorMessage } from '../components/common/ErrorMessage';
 import { ReservationCard } from '../components/search/ReservationCard';
 
 const MyReservations = () => {
-  const { isAuthenticated, user } = useAuth();
-  const query = useUserReservations(user?.id, { enabled: isAuthenticated });
+  const { isAuthenticated, user, deleteAccount } = useAuth();
+  const [includeArchived, setIncludeArchived] = useState(false);
+  const query = useUserReservations(
+    user?.id,
+    includeArchived ? { include_archived: true } : {},
+    { enabled: isAuthenticated },
+  );
 
   if (!isAuthenticated) {
     return <Navigate to="/login" replace />;
@@ -23,12 +29,22 @@ const MyReservations = () => {
 
   const reservations = query.data ?? [];
 
+  const handleDeleteAccount = () => {
+    if (window.confirm('¿Eliminar tu cuenta? Se cancelarán tus próximas reservas y se anonimizará tu historial.')) {
+      deleteAccount().catch(() => {});
+    }
+  };
+
   return (
     <div className="mx-auto max-w-6xl px-4 py-8">
       <div className="flex flex-col gap-2">
         <p className="text-sm uppercase tracking-[0.3em] text-slate-400 dark:text-slate-500">Tus reservas</p>
         <h1 className="font-display text-3xl font-semibold text-slate-900 dark:text-slate-50">Hola, {user?.first_name || user?.username}</h1>
         <p className="text-slate-500 dark:text-slate-400">Gestioná tus reservas pendientes, confirmalas o revisá el detalle.</p>
+        <label className="mt-2 flex items-center gap-2 text-sm text-slate-500 dark:text-slate-400">
+          <input type="checkbox" checked={includeArchived} onChange={(event) => setIncludeArchived(event.target.checked)} />
+          Mostrar reservas archivadas
+        </label>
       </div>
 
       <div className="mt-6 grid gap-4 sm:grid-cols-2 lg:grid-cols-3">
@@ -44,6 +60,12 @@ const MyReservations = () => {
           </div>
         )}
       </div>
+
+      <div className="mt-10 text-right">
+        <button type="button" onClick={handleDeleteAccount} className="text-sm font-semibold text-red-600 hover:underline dark:text-red-400">
+          Eliminar mi cuenta
+        </button>
+      </div>
     </div>
   );
 };
@@ -7,6 +7,7 @@ import { getReservationDocument } from '../api/search';
 import { getUserById } from '../api/auth';
 import { ReservationDetails as Details } from '../components/reservation/ReservationDetails';
 import { ConfirmModal } from '../components/reservation/ConfirmModal';
+import { PreOrderEditor } from '../components/reservation/PreOrderEditor';
 import { Loader } from '../components/common/Loader';
 import { ErrorMessage } from '../components/common/ErrorMessage';
 import { useAuth } from '../hooks/useAuth';
@@ -91,6 +92,7 @@ const ReservationDetailsPage = () => {
   return (
     <div className="mx-auto max-w-5xl px-4 py-8">
       <Details reservation={activeQuery.data} requesterName={ownerName} />
+      {isMongoId && <PreOrderEditor reservation={activeQuery.data} editable={canConfirm} />}
       {canConfirm ? (
         <div className="mt-6 elegant-card p-4">
           <div className="flex flex-col gap-3 sm:flex-row sm:items-center sm:justify-between">
@@ -10,8 +10,60 @@ export const MEAL_TYPES = [
 export const RESERVATION_STATUSES = [
   { value: 'pending', label: 'Pendiente' },
   { value: 'confirmed', label: 'Confirmada' },
+  { value: 'seated', label: 'Sentada' },
   { value: 'cancelled', label: 'Cancelada' },
   { value: 'completed', label: 'Completada' },
+  { value: 'no_show', label: 'No vino' },
 ];
 
+// Statuses an admin can set by editing; seating goes through check-in and completing
+// through the live table map
+export const EDITABLE_STATUSES = ['pending', 'confirmed', 'cancelled', 'no_show'];
+
+export const TABLE_STATUSES = [
+  { value: 'free', label: 'Libre' },
+  { value: 'reserved_soon', label: 'Reservada pronto' },
+  { value: 'occupied', label: 'Ocupada' },
+  { value: 'cleaning', label: 'En limpieza' },
+];
+
+export const ALLERGENS = [
+  { value: 'gluten', label: 'gluten' },
+  { value: 'crustaceans', label: 'crustáceos' },
+  { value: 'eggs', label: 'huevo' },
+  { value: 'fish', label: 'pescado' },
+  { value: 'peanuts', label: 'maní' },
+  { value: 'soybeans', label: 'soja' },
+  { value: 'milk', label: 'lácteos' },
+  { value: 'nuts', label: 'frutos secos' },
+  { value: 'celery', label: 'apio' },
+  { value: 'mustard', label: 'mostaza' },
+  { value: 'sesame', label: 'sésamo' },
+  { value: 'sulphites', label: 'sulfitos' },
+  { value: 'lupin', label: 'altramuz' },
+  { value: 'molluscs', label: 'moluscos' },
+];
+
+export const DIETARY_TAGS = [
+  { value: 'vegetarian', label: 'vegetariano' },
+  { value: 'vegan', label: 'vegano' },
+  { value: 'pescatarian', label: 'pescetariano' },
+  { value: 'gluten_free', label: 'sin TACC' },
+  { value: 'lactose_free', label: 'sin lactosa' },
+  { value: 'halal', label: 'halal' },
+  { value: 'kosher', label: 'kosher' },
+  { value: 'low_sodium', label: 'bajo en sodio' },
+  { value: 'diabetic', label: 'apto diabéticos' },
+];
+
+export const OCCASIONS = [
+  { value: 'birthday', label: 'Cumpleaños' },
+  { value: 'anniversary', label: 'Aniversario' },
+  { value: 'business', label: 'Negocios' },
+  { value: 'date', label: 'Cita' },
+  { value: 'celebration', label: 'Celebración' },
+];
+
+export const labelOf = (options, value) => options.find((o) => o.value === value)?.label || value;
+
 export const DEFAULT_PAGE_SIZE = 6;
@@ -1,9 +1,17 @@
-export const formatCurrency = (value) =>
-  new Intl.NumberFormat('es-AR', {
+// Accepts plain numbers or the API's { amount, currency } money, whose amount is in minor units
+export const formatCurrency = (value) => {
+  if (value && typeof value === 'object') {
+    const currency = value.currency || 'ARS';
+    const formatter = new Intl.NumberFormat('es-AR', { style: 'currency', currency });
+    const digits = formatter.resolvedOptions().maximumFractionDigits;
+    return formatter.format((value.amount ?? 0) / 10 ** digits);
+  }
+  return new Intl.NumberFormat('es-AR', {
     style: 'currency',
     currency: 'ARS',
     minimumFractionDigits: 2,
   }).format(value ?? 0);
+};
 
 export const formatDateTime = (value) => {
   if (!value) return 'Sin fecha';
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"log"
+	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/client"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/config"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/db"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/rabbitmq"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/repository"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/sender"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/service"
+	httptransport "github.com/blassardoy/restaurant-reservas/notifications-api/internal/transport/http"
+	"github.com/joho/godotenv"
+)
+
+func main() {
+	// Load .env file
+	_ = godotenv.Load()
+
+	// Load configuration
+	cfg := config.FromEnv()
+
+	location, err := time.LoadLocation(cfg.Timezone)
+	if err != nil {
+		log.Fatalf("Invalid NOTIFY_TIMEZONE: %v", err)
+	}
+
+	// Connect to MongoDB (delivery log)
+	log.Println("Connecting to MongoDB...")
+	mongoClient, collection, err := db.Connect(db.Config{
+		URI:        cfg.MongoURI,
+		Database:   cfg.MongoDB,
+		Collection: cfg.MongoCollection,
+	})
+	if err != nil {
+		log.Fatalf("MongoDB connection error: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := mongoClient.Disconnect(ctx); err != nil {
+			log.Printf("Error disconnecting MongoDB: %v", err)
+		}
+	}()
+
+	repo := repository.NewMongoNotificationRepository(collection)
+	indexCtx, cancelIndexes := context.WithTimeout(context.Background(), 10*time.Second)
+	if err := repo.EnsureIndexes(indexCtx); err != nil {
+		log.Fatalf("MongoDB index error: %v", err)
+	}
+	cancelIndexes()
+
+	// Delivery channel
+	var s sender.Sender
+	switch cfg.Sender {
+	case "smtp":
+		s = sender.NewSMTPSender(sender.SMTPConfig{
+			Host:     cfg.SMTPHost,
+			Port:     cfg.SMTPPort,
+			Username: cfg.SMTPUsername,
+			Password: cfg.SMTPPassword,
+			From:     cfg.SMTPFrom,
+		})
+		log.Printf("Sending notifications through SMTP %s:%d", cfg.SMTPHost, cfg.SMTPPort)
+	case "log":
+		s = sender.NewLogSender()
+		log.Println("Logging notifications instead of sending them (NOTIFY_SENDER=log)")
+	default:
+		log.Fatalf("Unknown NOTIFY_SENDER %q (use log or smtp)", cfg.Sender)
+	}
+
+	notificationService := service.NewNotificationService(
+		repo,
+		client.NewReservationsClient(cfg.ReservationsAPIURL),
+		client.NewUsersClient(cfg.UsersAPIURL, cfg.UsersAPIServiceToken),
+		s.Channel(),
+		cfg.MaxAttempts,
+		cfg.ReminderLead,
+		location,
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// Consume reservation events and queue the matching notifications
+	go func() {
+		if err := rabbitmq.NewConsumer(cfg.RabbitMQURI, cfg.RabbitMQExchange, cfg.RabbitMQQueue, notificationService).Run(ctx); err != nil {
+			log.Printf("rabbitmq consumer stopped: %v", err)
+		}
+	}()
+
+	// Deliver due notifications (confirmations right away, reminders when due, retries)
+	go service.NewDispatcher(repo, s, cfg.DispatchInterval).Run(ctx)
+
+	r := httptransport.NewRouter(notificationService)
+
+	addr := ":" + cfg.Port
+	log.Printf("notifications-api listening on %s", addr)
+	if err := r.Run(addr); err != nil {
+		log.Fatalf("server error: %v", err)
+	}
+}
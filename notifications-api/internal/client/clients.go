@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/domain"
+)
+
+// ErrNotFound is returned when the upstream API answers 404
+var ErrNotFound = errors.New("not found")
+
+// ReservationsClient reads reservations from the Reservations API
+type ReservationsClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+func NewReservationsClient(baseURL string) *ReservationsClient {
+	return &ReservationsClient{baseURL: baseURL, httpClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// GetReservation retrieves a reservation by ID
+func (c *ReservationsClient) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	var reservation domain.Reservation
+	endpoint := fmt.Sprintf("%s/api/reservations/%s", c.baseURL, url.PathEscape(id))
+	if err := getJSON(ctx, c.httpClient, "reservations", endpoint, nil, &reservation); err != nil {
+		return nil, err
+	}
+	return &reservation, nil
+}
+
+// UsersClient reads users from the Users API; the service token lets it read
+// their email addresses
+type UsersClient struct {
+	baseURL      string
+	serviceToken string
+	httpClient   *http.Client
+}
+
+func NewUsersClient(baseURL, serviceToken string) *UsersClient {
+	return &UsersClient{baseURL: baseURL, serviceToken: serviceToken, httpClient: &http.Client{Timeout: 5 * time.Second}}
+}
+
+// GetUser retrieves a user by ID
+func (c *UsersClient) GetUser(ctx context.Context, id string) (*domain.User, error) {
+	var user domain.User
+	endpoint := fmt.Sprintf("%s/api/users/%s", c.baseURL, url.PathEscape(id))
+	header := http.Header{}
+	if c.serviceToken != "" {
+		header.Set("X-Service-Token", c.serviceToken)
+	}
+	if err := getJSON(ctx, c.httpClient, "users", endpoint, header, &user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func getJSON(ctx context.Context, httpClient *http.Client, api, endpoint string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call %s API: %w", api, err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrNotFound
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s API returned status %d", api, resp.StatusCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to decode %s API response: %w", api, err)
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Config holds MongoDB connection configuration
+type Config struct {
+	URI        string
+	Database   string
+	Collection string
+}
+
+// Connect creates a connection to MongoDB
+func Connect(cfg Config) (*mongo.Client, *mongo.Collection, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Create client options
+	clientOptions := options.Client().ApplyURI(cfg.URI)
+
+	// Connect to MongoDB
+	client, err := mongo.Connect(ctx, clientOptions)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to connect to MongoDB: %w", err)
+	}
+
+	// Ping the database to verify connection
+	if err := client.Ping(ctx, nil); err != nil {
+		return nil, nil, fmt.Errorf("failed to ping MongoDB: %w", err)
+	}
+
+	// Get collection
+	collection := client.Database(cfg.Database).Collection(cfg.Collection)
+
+	return client, collection, nil
+}
+
+// Close closes the MongoDB connection
+func Close(client *mongo.Client) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if err := client.Disconnect(ctx); err != nil {
+		return fmt.Errorf("failed to disconnect from MongoDB: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Notification kinds
+const (
+	KindConfirmation = "confirmation"
+	KindChange       = "change"
+	KindCancellation = "cancellation"
+	KindReminder     = "reminder"
+)
+
+// Delivery statuses
+const (
+	StatusPending   = "pending"   // waiting for SendAt / NextAttemptAt
+	StatusSending   = "sending"   // claimed by a dispatcher
+	StatusSent      = "sent"      // delivered to the sender
+	StatusFailed    = "failed"    // gave up after MaxAttempts
+	StatusCancelled = "cancelled" // no longer relevant (e.g. reminder of a cancelled reservation)
+)
+
+// Notification is a message to a customer together with its delivery state
+type Notification struct {
+	ID            primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	ReservationID string             `bson:"reservation_id" json:"reservation_id"`
+	UserID        string             `bson:"user_id" json:"user_id"`
+	Kind          string             `bson:"kind" json:"kind"`
+	// DedupKey makes redelivered events idempotent (unique index)
+	DedupKey      string     `bson:"dedup_key" json:"-"`
+	Channel       string     `bson:"channel" json:"channel"`
+	Recipient     string     `bson:"recipient" json:"recipient"`
+	Subject       string     `bson:"subject" json:"subject"`
+	Body          string     `bson:"body" json:"body"`
+	Status        string     `bson:"status" json:"status"`
+	Attempts      int        `bson:"attempts" json:"attempts"`
+	MaxAttempts   int        `bson:"max_attempts" json:"max_attempts"`
+	LastError     string     `bson:"last_error,omitempty" json:"last_error,omitempty"`
+	SendAt        time.Time  `bson:"send_at" json:"send_at"`
+	NextAttemptAt time.Time  `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   time.Time  `bson:"locked_until,omitempty" json:"-"`
+	SentAt        *time.Time `bson:"sent_at,omitempty" json:"sent_at,omitempty"`
+	CreatedAt     time.Time  `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time  `bson:"updated_at" json:"updated_at"`
+}
+
+// Reservation is the subset of a reservations-api reservation used in messages
+type Reservation struct {
+	ID                 string    `json:"id"`
+	OwnerID            string    `json:"owner_id"`
+	TableNumber        int       `json:"table_number"`
+	Guests             int       `json:"guests"`
+	DateTime           time.Time `json:"date_time"`
+	MealType           string    `json:"meal_type"`
+	Status             string    `json:"status"`
+	TotalPrice         Money     `json:"total_price"`
+	SpecialRequests    string    `json:"special_requests,omitempty"`
+	CancellationFee    *Money    `json:"cancellation_fee,omitempty"`
+	CancellationReason string    `json:"cancellation_reason,omitempty"`
+}
+
+// Money is an amount in the minor unit of its ISO 4217 currency
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// Decimal formats the amount in the major unit, e.g. "38.50" (ARS) or "3850" (CLP)
+func (m Money) Decimal() string {
+	exp := 2
+	switch m.Currency {
+	case "CLP", "ISK", "JPY", "KRW", "PYG":
+		exp = 0
+	case "BHD", "JOD", "KWD", "OMR", "TND":
+		exp = 3
+	}
+	return strconv.FormatFloat(float64(m.Amount)/math.Pow10(exp), 'f', exp, 64)
+}
+
+// User is the subset of a users-api user used to address messages
+type User struct {
+	ID        uint64 `json:"id"`
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
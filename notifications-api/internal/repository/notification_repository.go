@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// NotificationRepository persists notifications and their delivery state
+type NotificationRepository interface {
+	EnsureIndexes(ctx context.Context) error
+	// Enqueue stores a pending notification; an already stored DedupKey is ignored
+	Enqueue(ctx context.Context, n *domain.Notification) error
+	// ClaimDue leases the next notification due for delivery, or returns nil if there is none
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.Notification, error)
+	MarkSent(ctx context.Context, id primitive.ObjectID, now time.Time) error
+	// MarkAttemptFailed records a failed attempt and either reschedules it or gives up
+	MarkAttemptFailed(ctx context.Context, id primitive.ObjectID, attempts int, lastErr string, nextAttemptAt *time.Time, now time.Time) error
+	// CancelPending cancels the not yet sent notifications of a kind for a reservation
+	CancelPending(ctx context.Context, reservationID, kind string, now time.Time) (int64, error)
+	GetByReservation(ctx context.Context, reservationID string) ([]domain.Notification, error)
+}
+
+// MongoNotificationRepository implements NotificationRepository for MongoDB
+type MongoNotificationRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoNotificationRepository creates a new MongoDB notification repository
+func NewMongoNotificationRepository(collection *mongo.Collection) *MongoNotificationRepository {
+	return &MongoNotificationRepository{collection: collection}
+}
+
+// EnsureIndexes creates the indexes used by the dispatcher and for deduplication
+func (r *MongoNotificationRepository) EnsureIndexes(ctx context.Context) error {
+	_, err := r.collection.Indexes().CreateMany(ctx, []mongo.IndexModel{
+		{Keys: bson.D{{Key: "dedup_key", Value: 1}}, Options: options.Index().SetUnique(true)},
+		{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+		{Keys: bson.D{{Key: "reservation_id", Value: 1}, {Key: "created_at", Value: 1}}},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create notification indexes: %w", err)
+	}
+	return nil
+}
+
+// Enqueue stores a pending notification
+func (r *MongoNotificationRepository) Enqueue(ctx context.Context, n *domain.Notification) error {
+	result, err := r.collection.InsertOne(ctx, n)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to enqueue notification: %w", err)
+	}
+	n.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// ClaimDue atomically moves the next due notification to "sending". Notifications
+// whose lease expired (e.g. the dispatcher crashed mid-send) are claimed again.
+func (r *MongoNotificationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.Notification, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": domain.StatusPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"status": domain.StatusSending, "locked_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       domain.StatusSending,
+		"locked_until": now.Add(lease),
+		"updated_at":   now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var n domain.Notification
+	err := r.collection.FindOneAndUpdate(ctx, filter, update, opts).Decode(&n)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim notification: %w", err)
+	}
+	return &n, nil
+}
+
+// MarkSent records a successful delivery
+func (r *MongoNotificationRepository) MarkSent(ctx context.Context, id primitive.ObjectID, now time.Time) error {
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$set":   bson.M{"status": domain.StatusSent, "sent_at": now, "updated_at": now, "last_error": ""},
+		"$inc":   bson.M{"attempts": 1},
+		"$unset": bson.M{"locked_until": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to mark notification as sent: %w", err)
+	}
+	return nil
+}
+
+// MarkAttemptFailed reschedules the notification at nextAttemptAt, or marks it
+// as failed when nextAttemptAt is nil
+func (r *MongoNotificationRepository) MarkAttemptFailed(ctx context.Context, id primitive.ObjectID, attempts int, lastErr string, nextAttemptAt *time.Time, now time.Time) error {
+	set := bson.M{
+		"attempts":   attempts,
+		"last_error": lastErr,
+		"updated_at": now,
+		"status":     domain.StatusFailed,
+	}
+	if nextAttemptAt != nil {
+		set["status"] = domain.StatusPending
+		set["next_attempt_at"] = *nextAttemptAt
+	}
+
+	_, err := r.collection.UpdateByID(ctx, id, bson.M{
+		"$set":   set,
+		"$unset": bson.M{"locked_until": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record notification attempt: %w", err)
+	}
+	return nil
+}
+
+// CancelPending cancels pending notifications of a kind for a reservation
+func (r *MongoNotificationRepository) CancelPending(ctx context.Context, reservationID, kind string, now time.Time) (int64, error) {
+	result, err := r.collection.UpdateMany(ctx,
+		bson.M{"reservation_id": reservationID, "kind": kind, "status": domain.StatusPending},
+		bson.M{"$set": bson.M{"status": domain.StatusCancelled, "updated_at": now}},
+	)
+	if err != nil {
+		return 0, fmt.Errorf("failed to cancel notifications: %w", err)
+	}
+	return result.ModifiedCount, nil
+}
+
+// GetByReservation returns the notifications of a reservation, oldest first
+func (r *MongoNotificationRepository) GetByReservation(ctx context.Context, reservationID string) ([]domain.Notification, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.collection.Find(ctx, bson.M{"reservation_id": reservationID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get notifications: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	notifications := []domain.Notification{}
+	if err := cursor.All(ctx, &notifications); err != nil {
+		return nil, fmt.Errorf("failed to decode notifications: %w", err)
+	}
+	return notifications, nil
+}
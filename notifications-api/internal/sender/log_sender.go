@@ -0,0 +1,22 @@
+package sender
+
+import (
+	"context"
+	"log"
+)
+
+// LogSender prints messages instead of delivering them (development)
+type LogSender struct{}
+
+func NewLogSender() *LogSender {
+	return &LogSender{}
+}
+
+func (s *LogSender) Channel() string { return "log" }
+
+func (s *LogSender) Send(ctx context.Context, msg Message) error {
+	log.Printf("notification to=%s subject=%q\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+var _ Sender = (*LogSender)(nil)
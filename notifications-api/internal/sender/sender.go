@@ -0,0 +1,17 @@
+package sender
+
+import "context"
+
+// Message is an outgoing notification
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers messages through a channel (email, log, ...)
+type Sender interface {
+	// Channel identifies the delivery channel recorded on each notification
+	Channel() string
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,199 @@
+package sender
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeSMTPServer accepts a single SMTP session and records the received message
+type fakeSMTPServer struct {
+	listener net.Listener
+	// rejectData makes the server answer the DATA command with a transient error
+	rejectData bool
+	received   chan receivedMail
+}
+
+type receivedMail struct {
+	from string
+	to   []string
+	data string
+}
+
+func newFakeSMTPServer(t *testing.T) *fakeSMTPServer {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	s := &fakeSMTPServer{listener: l, received: make(chan receivedMail, 1)}
+	t.Cleanup(func() { l.Close() })
+	go s.serve()
+	return s
+}
+
+func (s *fakeSMTPServer) port() int {
+	return s.listener.Addr().(*net.TCPAddr).Port
+}
+
+func (s *fakeSMTPServer) serve() {
+	conn, err := s.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	reply := func(line string) { _, _ = io.WriteString(conn, line+"\r\n") }
+
+	var mailMsg receivedMail
+	reply("220 fake.smtp ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.TrimRight(line, "\r\n")
+		upper := strings.ToUpper(cmd)
+
+		switch {
+		case strings.HasPrefix(upper, "EHLO"), strings.HasPrefix(upper, "HELO"):
+			reply("250 fake.smtp")
+		case strings.HasPrefix(upper, "MAIL FROM:"):
+			mailMsg.from = strings.Trim(cmd[len("MAIL FROM:"):], "<> ")
+			reply("250 OK")
+		case strings.HasPrefix(upper, "RCPT TO:"):
+			mailMsg.to = append(mailMsg.to, strings.Trim(cmd[len("RCPT TO:"):], "<> "))
+			reply("250 OK")
+		case upper == "DATA":
+			if s.rejectData {
+				reply("451 try again later")
+				continue
+			}
+			reply("354 end with <CRLF>.<CRLF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(strings.TrimPrefix(l, "."))
+			}
+			mailMsg.data = data.String()
+			reply("250 queued")
+			s.received <- mailMsg
+		case upper == "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPSenderDeliversMessage(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	s := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "reservas@restaurant.local"})
+
+	msg := Message{
+		To:      "ana@example.com",
+		Subject: "Reserva confirmada para el 24/12/2026",
+		Body:    "Hola Ana,\n\nTu reserva está confirmada.\n  Comensales: 4",
+	}
+	if err := s.Send(context.Background(), msg); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+
+	var got receivedMail
+	select {
+	case got = <-server.received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("fake SMTP server received no message")
+	}
+
+	if got.from != "reservas@restaurant.local" {
+		t.Errorf("MAIL FROM = %q", got.from)
+	}
+	if len(got.to) != 1 || got.to[0] != "ana@example.com" {
+		t.Errorf("RCPT TO = %v", got.to)
+	}
+
+	parsed, err := mail.ReadMessage(strings.NewReader(got.data))
+	if err != nil {
+		t.Fatalf("received message is not RFC 5322: %v", err)
+	}
+	subject, err := new(mime.WordDecoder).DecodeHeader(parsed.Header.Get("Subject"))
+	if err != nil {
+		t.Fatalf("decode subject: %v", err)
+	}
+	if subject != msg.Subject {
+		t.Errorf("Subject = %q, want %q", subject, msg.Subject)
+	}
+	if ct := parsed.Header.Get("Content-Type"); !strings.Contains(ct, "utf-8") {
+		t.Errorf("Content-Type = %q, want utf-8 text", ct)
+	}
+
+	body, err := io.ReadAll(quotedprintable.NewReader(parsed.Body))
+	if err != nil {
+		t.Fatalf("decode body: %v", err)
+	}
+	// The DATA terminator adds a trailing CRLF when the message lacks one
+	if want := strings.ReplaceAll(msg.Body, "\n", "\r\n"); strings.TrimSuffix(string(body), "\r\n") != want {
+		t.Errorf("body = %q, want %q", body, want)
+	}
+}
+
+func TestSMTPSenderReportsServerRejection(t *testing.T) {
+	server := newFakeSMTPServer(t)
+	server.rejectData = true
+	s := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: server.port(), From: "reservas@restaurant.local"})
+
+	err := s.Send(context.Background(), Message{To: "ana@example.com", Subject: "x", Body: "y"})
+	if err == nil || !strings.Contains(err.Error(), "451") {
+		t.Fatalf("Send() error = %v, want the 451 reply", err)
+	}
+}
+
+func TestSMTPSenderHonoursContext(t *testing.T) {
+	// A server that accepts connections but never greets
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	defer l.Close()
+	go func() {
+		conn, err := l.Accept()
+		if err == nil {
+			defer conn.Close()
+			time.Sleep(2 * time.Second)
+		}
+	}()
+
+	s := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: l.Addr().(*net.TCPAddr).Port, From: "reservas@restaurant.local"})
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	err = s.Send(ctx, Message{To: "ana@example.com", Subject: "x", Body: "y"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Send() error = %v, want context.DeadlineExceeded", err)
+	}
+}
+
+func TestSMTPSenderRejectsHeaderInjection(t *testing.T) {
+	s := NewSMTPSender(SMTPConfig{Host: "127.0.0.1", Port: 1, From: "reservas@restaurant.local"})
+	err := s.Send(context.Background(), Message{To: "ana@example.com\r\nBcc: x@example.com", Subject: "x", Body: "y"})
+	if err == nil {
+		t.Fatal("Send() accepted a recipient with CRLF")
+	}
+}
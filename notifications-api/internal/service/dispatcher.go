@@ -0,0 +1,102 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/repository"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/sender"
+)
+
+// Retry backoff: 30s, 1m, 2m, ... capped at 1h
+const (
+	retryBaseDelay = 30 * time.Second
+	retryMaxDelay  = time.Hour
+	sendTimeout    = 30 * time.Second
+)
+
+// Dispatcher delivers due notifications and persists the outcome of every attempt
+type Dispatcher struct {
+	repo     repository.NotificationRepository
+	sender   sender.Sender
+	interval time.Duration
+	now      func() time.Time
+}
+
+func NewDispatcher(repo repository.NotificationRepository, s sender.Sender, interval time.Duration) *Dispatcher {
+	return &Dispatcher{repo: repo, sender: s, interval: interval, now: time.Now}
+}
+
+// Run polls for due notifications until the context is cancelled
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		d.DispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends every notification that is currently due
+func (d *Dispatcher) DispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// The lease outlives the send timeout so a slow attempt is never claimed twice
+		n, err := d.repo.ClaimDue(ctx, d.now(), 2*sendTimeout)
+		if err != nil {
+			log.Printf("dispatcher: %v", err)
+			return
+		}
+		if n == nil {
+			return
+		}
+		d.deliver(ctx, n)
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, n *domain.Notification) {
+	sendCtx, cancel := context.WithTimeout(ctx, sendTimeout)
+	err := d.sender.Send(sendCtx, sender.Message{To: n.Recipient, Subject: n.Subject, Body: n.Body})
+	cancel()
+
+	now := d.now()
+	if err == nil {
+		if err := d.repo.MarkSent(ctx, n.ID, now); err != nil {
+			log.Printf("dispatcher: %v", err)
+		}
+		return
+	}
+
+	attempts := n.Attempts + 1
+	var next *time.Time
+	if attempts < n.MaxAttempts {
+		at := now.Add(retryDelay(attempts))
+		next = &at
+		log.Printf("notification %s attempt %d failed, retrying at %s: %v", n.ID.Hex(), attempts, at.Format(time.RFC3339), err)
+	} else {
+		log.Printf("notification %s failed after %d attempts: %v", n.ID.Hex(), attempts, err)
+	}
+
+	if err := d.repo.MarkAttemptFailed(ctx, n.ID, attempts, err.Error(), next, now); err != nil {
+		log.Printf("dispatcher: %v", err)
+	}
+}
+
+// retryDelay doubles the wait after every failed attempt
+func retryDelay(attempts int) time.Duration {
+	delay := retryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= retryMaxDelay {
+			return retryMaxDelay
+		}
+	}
+	return delay
+}
@@ -0,0 +1,165 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/client"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/repository"
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/templates"
+)
+
+// Reservation status values published by reservations-api
+const (
+	reservationCancelled = "cancelled"
+	reservationCompleted = "completed"
+	reservationNoShow    = "no_show"
+)
+
+// ReservationGetter reads reservations from the Reservations API
+type ReservationGetter interface {
+	GetReservation(ctx context.Context, id string) (*domain.Reservation, error)
+}
+
+// UserGetter reads users from the Users API
+type UserGetter interface {
+	GetUser(ctx context.Context, id string) (*domain.User, error)
+}
+
+// NotificationService turns reservation events into queued notifications
+type NotificationService struct {
+	repo         repository.NotificationRepository
+	reservations ReservationGetter
+	users        UserGetter
+	channel      string
+	maxAttempts  int
+	reminderLead time.Duration
+	location     *time.Location
+	now          func() time.Time
+}
+
+// NewNotificationService creates a new notification service. channel is the
+// delivery channel of the configured sender.
+func NewNotificationService(repo repository.NotificationRepository, reservations ReservationGetter, users UserGetter, channel string, maxAttempts int, reminderLead time.Duration, location *time.Location) *NotificationService {
+	return &NotificationService{
+		repo:         repo,
+		reservations: reservations,
+		users:        users,
+		channel:      channel,
+		maxAttempts:  maxAttempts,
+		reminderLead: reminderLead,
+		location:     location,
+		now:          time.Now,
+	}
+}
+
+// HandleEvent queues the notifications for a reservation event.
+// occurredAt identifies the event so a redelivered message is not notified twice.
+func (s *NotificationService) HandleEvent(ctx context.Context, operation, reservationID string, occurredAt time.Time) error {
+	reservation, err := s.reservations.GetReservation(ctx, reservationID)
+	if errors.Is(err, client.ErrNotFound) {
+		// Hard-deleted reservation: nothing to tell the customer, drop pending reminders
+		_, err := s.repo.CancelPending(ctx, reservationID, domain.KindReminder, s.now())
+		return err
+	}
+	if err != nil {
+		return err
+	}
+
+	switch operation {
+	case "create", "confirm":
+		if err := s.enqueue(ctx, reservation, domain.KindConfirmation, occurredAt, s.now()); err != nil {
+			return err
+		}
+		return s.scheduleReminder(ctx, reservation, occurredAt)
+
+	case "update":
+		switch reservation.Status {
+		case reservationCancelled:
+			return s.cancelled(ctx, reservation, occurredAt)
+		case reservationCompleted, reservationNoShow:
+			// Status bookkeeping after the visit: nothing to tell the customer
+			return nil
+		}
+		if err := s.enqueue(ctx, reservation, domain.KindChange, occurredAt, s.now()); err != nil {
+			return err
+		}
+		return s.scheduleReminder(ctx, reservation, occurredAt)
+
+	case "cancel", "delete":
+		return s.cancelled(ctx, reservation, occurredAt)
+	}
+
+	return nil
+}
+
+// GetByReservation returns the notifications sent or queued for a reservation
+func (s *NotificationService) GetByReservation(ctx context.Context, reservationID string) ([]domain.Notification, error) {
+	return s.repo.GetByReservation(ctx, reservationID)
+}
+
+func (s *NotificationService) cancelled(ctx context.Context, reservation *domain.Reservation, occurredAt time.Time) error {
+	if _, err := s.repo.CancelPending(ctx, reservation.ID, domain.KindReminder, s.now()); err != nil {
+		return err
+	}
+	return s.enqueue(ctx, reservation, domain.KindCancellation, occurredAt, s.now())
+}
+
+// scheduleReminder replaces any pending reminder with one ReminderLead before the reservation
+func (s *NotificationService) scheduleReminder(ctx context.Context, reservation *domain.Reservation, occurredAt time.Time) error {
+	now := s.now()
+	if _, err := s.repo.CancelPending(ctx, reservation.ID, domain.KindReminder, now); err != nil {
+		return err
+	}
+
+	sendAt := reservation.DateTime.Add(-s.reminderLead)
+	if reservation.Status == reservationCancelled || !sendAt.After(now) {
+		// Booked inside the reminder window: the confirmation is reminder enough
+		return nil
+	}
+	return s.enqueue(ctx, reservation, domain.KindReminder, occurredAt, sendAt)
+}
+
+// enqueue renders a message for the reservation owner and stores it as pending
+func (s *NotificationService) enqueue(ctx context.Context, reservation *domain.Reservation, kind string, occurredAt, sendAt time.Time) error {
+	user, err := s.users.GetUser(ctx, reservation.OwnerID)
+	if errors.Is(err, client.ErrNotFound) {
+		log.Printf("skipping %s notification for reservation %s: owner %s not found", kind, reservation.ID, reservation.OwnerID)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Email == "" {
+		log.Printf("skipping %s notification for reservation %s: owner has no email", kind, reservation.ID)
+		return nil
+	}
+
+	message, err := templates.Render(kind, templates.Data{User: *user, Reservation: *reservation, Location: s.location})
+	if err != nil {
+		return err
+	}
+
+	now := s.now()
+	notification := &domain.Notification{
+		ReservationID: reservation.ID,
+		UserID:        reservation.OwnerID,
+		Kind:          kind,
+		DedupKey:      fmt.Sprintf("%s:%s:%d", reservation.ID, kind, occurredAt.UnixNano()),
+		Channel:       s.channel,
+		Recipient:     user.Email,
+		Subject:       message.Subject,
+		Body:          message.Body,
+		Status:        domain.StatusPending,
+		MaxAttempts:   s.maxAttempts,
+		SendAt:        sendAt,
+		NextAttemptAt: sendAt,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	return s.repo.Enqueue(ctx, notification)
+}
@@ -0,0 +1,134 @@
+package templates
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/domain"
+)
+
+// Data is what every message template can use
+type Data struct {
+	User        domain.User
+	Reservation domain.Reservation
+	// Location in which reservation times are shown to the customer
+	Location *time.Location
+}
+
+// Rendered is a message ready to be sent
+type Rendered struct {
+	Subject string
+	Body    string
+}
+
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+var funcs = template.FuncMap{
+	"meal": mealLabel,
+	"date": func(t time.Time, loc *time.Location) string {
+		return t.In(loc).Format("02/01/2006")
+	},
+	"hour": func(t time.Time, loc *time.Location) string {
+		return t.In(loc).Format("15:04")
+	},
+	"money": func(m domain.Money) string { return "$" + m.Decimal() },
+}
+
+const signature = `
+Te esperamos,
+El equipo del restaurante`
+
+const details = `
+  Fecha: {{date .Reservation.DateTime .Location}}
+  Hora: {{hour .Reservation.DateTime .Location}}
+  Servicio: {{meal .Reservation.MealType}}
+  Mesa: {{.Reservation.TableNumber}}
+  Comensales: {{.Reservation.Guests}}
+  Total: {{money .Reservation.TotalPrice}}`
+
+var messages = map[string]messageTemplate{
+	domain.KindConfirmation: parse(
+		`{{if eq .Reservation.Status "confirmed"}}Reserva confirmada{{else}}Recibimos tu reserva{{end}} para el {{date .Reservation.DateTime .Location}}`,
+		`Hola {{.User.FirstName}},
+
+{{if eq .Reservation.Status "confirmed"}}Tu reserva está confirmada.{{else}}Recibimos tu reserva y la estamos procesando.{{end}}
+`+details+`
+{{if .Reservation.SpecialRequests}}  Pedidos especiales: {{.Reservation.SpecialRequests}}
+{{end}}`+signature),
+
+	domain.KindChange: parse(
+		`Tu reserva del {{date .Reservation.DateTime .Location}} fue modificada`,
+		`Hola {{.User.FirstName}},
+
+Actualizamos tu reserva. Estos son los datos vigentes:
+`+details+`
+`+signature),
+
+	domain.KindCancellation: parse(
+		`Tu reserva del {{date .Reservation.DateTime .Location}} fue cancelada`,
+		`Hola {{.User.FirstName}},
+
+Tu reserva de {{meal .Reservation.MealType}} del {{date .Reservation.DateTime .Location}} a las {{hour .Reservation.DateTime .Location}} fue cancelada.
+{{if .Reservation.CancellationReason}}Motivo: {{.Reservation.CancellationReason}}
+{{end}}{{with .Reservation.CancellationFee}}Cargo por cancelación: {{money .}}
+{{end}}
+Esperamos verte pronto,
+El equipo del restaurante`),
+
+	domain.KindReminder: parse(
+		`Recordatorio: tu reserva del {{date .Reservation.DateTime .Location}} a las {{hour .Reservation.DateTime .Location}}`,
+		`Hola {{.User.FirstName}},
+
+Te recordamos tu reserva:
+`+details+`
+
+Si no podés asistir, por favor cancelala desde la aplicación.
+`+signature),
+}
+
+func parse(subject, body string) messageTemplate {
+	return messageTemplate{
+		subject: template.Must(template.New("subject").Funcs(funcs).Parse(subject)),
+		body:    template.Must(template.New("body").Funcs(funcs).Parse(body)),
+	}
+}
+
+// Render builds the subject and body of a notification kind
+func Render(kind string, data Data) (Rendered, error) {
+	tmpl, ok := messages[kind]
+	if !ok {
+		return Rendered{}, fmt.Errorf("unknown notification kind %q", kind)
+	}
+	if data.Location == nil {
+		data.Location = time.UTC
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return Rendered{}, fmt.Errorf("render %s subject: %w", kind, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return Rendered{}, fmt.Errorf("render %s body: %w", kind, err)
+	}
+
+	return Rendered{Subject: subject.String(), Body: body.String()}, nil
+}
+
+func mealLabel(mealType string) string {
+	switch mealType {
+	case "breakfast":
+		return "desayuno"
+	case "lunch":
+		return "almuerzo"
+	case "dinner":
+		return "cena"
+	case "event":
+		return "evento"
+	}
+	return mealType
+}
@@ -0,0 +1,48 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/blassardoy/restaurant-reservas/notifications-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func NewRouter(svc *service.NotificationService) *gin.Engine {
+	r := gin.Default()
+
+	// CORS
+	r.Use(func(c *gin.Context) {
+		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
+		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, OPTIONS")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		if c.Request.Method == http.MethodOptions {
+			c.AbortWithStatus(http.StatusNoContent)
+			return
+		}
+		c.Next()
+	})
+
+	r.GET("/health", func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{"status": "healthy"})
+	})
+
+	api := r.Group("/api")
+	{
+		// Delivery log of a reservation (status, attempts, last error)
+		api.GET("/notifications", func(c *gin.Context) {
+			reservationID := c.Query("reservation_id")
+			if reservationID == "" {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "reservation_id is required"})
+				return
+			}
+			notifications, err := svc.GetByReservation(c.Request.Context(), reservationID)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			c.JSON(http.StatusOK, notifications)
+		})
+	}
+
+	return r
+}
@@ -3,11 +3,15 @@ package main
 import (
 	"context"
 	"log"
+	"os"
 	"time"
+	_ "time/tzdata" // the runtime image ships without a zoneinfo database
 
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/config"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/controller"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/db"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/migrations"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
 	httptransport "github.com/blassardoy/restaurant-reservas/reservations-api/internal/transport/http"
@@ -40,6 +44,26 @@ func main() {
 	}()
 	log.Println("Connected to MongoDB successfully")
 
+	// Schema migrations: "reservations-api migrate [up|status]" runs them and exits,
+	// otherwise pending ones are applied on startup unless MIGRATE_ON_STARTUP=false
+	migrator := migrations.NewMigrator(migrations.Target{
+		Database:     collection.Database(),
+		Reservations: collection,
+		Currency:     cfg.Currency,
+	}, migrations.All())
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		if err := runMigrate(migrator, os.Args[2:]); err != nil {
+			log.Printf("Migration error: %v", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if cfg.MigrateOnStartup {
+		if err := migrator.Up(context.Background()); err != nil {
+			log.Fatalf("Migration error: %v", err)
+		}
+	}
+
 	// Connect to RabbitMQ
 	log.Println("Connecting to RabbitMQ...")
 	rmqPublisher, err := service.NewRabbitMQPublisher(
@@ -53,14 +77,135 @@ func main() {
 	defer rmqPublisher.Close()
 	log.Println("Connected to RabbitMQ successfully")
 
+	// Load cancellation policies (defaults overridden by CANCELLATION_POLICIES)
+	policies, err := domain.ParseCancellationPolicies(cfg.CancellationPolicies)
+	if err != nil {
+		log.Fatalf("Invalid cancellation policies: %v", err)
+	}
+
+	// Load promotions for the calculation pipeline
+	promotions, err := domain.ParsePromotions(cfg.Promotions)
+	if err != nil {
+		log.Fatalf("Invalid promotions: %v", err)
+	}
+
+	// Load the currency, rounding and charges used to price reservations
+	if !domain.IsValidCurrency(cfg.Currency) {
+		log.Fatalf("Invalid currency: %q", cfg.Currency)
+	}
+	rounding, err := domain.ParseRoundingMode(cfg.PriceRounding)
+	if err != nil {
+		log.Fatalf("Invalid price rounding: %v", err)
+	}
+	charges, err := domain.ParseCharges(cfg.PriceCharges)
+	if err != nil {
+		log.Fatalf("Invalid price charges: %v", err)
+	}
+	pricing := domain.Pricing{Currency: cfg.Currency, Rounding: rounding, Charges: charges}
+
 	// Initialize layers
 	repo := repository.NewMongoReservationRepository(collection)
-	userClient := service.NewUserClient(cfg.UsersAPIURL)
-	svc := service.NewReservationService(repo, userClient, rmqPublisher)
+	userClient := service.NewUserClient(cfg.UsersAPIURL, service.UserClientConfig{
+		Timeout:          cfg.UsersAPITimeout,
+		MaxRetries:       cfg.UsersAPIMaxRetries,
+		RetryBaseDelay:   cfg.UsersAPIRetryBaseDelay,
+		CacheTTL:         cfg.UsersAPICacheTTL,
+		BreakerThreshold: cfg.UsersAPIBreakerThreshold,
+		BreakerCooldown:  cfg.UsersAPIBreakerCooldown,
+		Fallback:         cfg.UsersAPIFallback,
+		ServiceToken:     cfg.UsersAPIServiceToken,
+	})
+	calendarFeed := service.NewCalendarFeed(cfg.CalendarFeedSecret, cfg.PublicBaseURL)
+	checkIn := service.NewCheckInTokens(cfg.CheckInSecret, domain.CheckInWindow{
+		OpensBefore: cfg.CheckInOpensBefore,
+		ClosesAfter: cfg.CheckInClosesAfter,
+	})
+	// Webhook subscriptions and deliveries live next to the reservations collection
+	webhookRepo := repository.NewMongoWebhookRepository(
+		collection.Database().Collection(repository.WebhooksCollection),
+		collection.Database().Collection(repository.WebhookDeliveriesCollection),
+	)
+	webhookCfg := service.WebhookConfig{
+		MaxAttempts:    cfg.WebhookMaxAttempts,
+		RetryBaseDelay: cfg.WebhookRetryBaseDelay,
+		RetryMaxDelay:  cfg.WebhookRetryMaxDelay,
+		Timeout:        cfg.WebhookTimeout,
+		PollInterval:   cfg.WebhookPollInterval,
+	}
+	webhookSvc := service.NewWebhookService(webhookRepo, webhookCfg)
+
+	background, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	go service.NewWebhookDispatcher(webhookRepo, webhookCfg).Run(background)
+
+	// Every replica receives every reservation event for its live streams
+	eventHub := service.NewEventHub(cfg.StreamBufferSize)
+	go service.RunEventFanout(background, rmqPublisher, eventHub)
+
+	// Reservations past the retention period move to the archive collection
+	archive := collection.Database().Collection(repository.ReservationsArchiveCollection)
+	archiveRepo := repository.NewMongoArchiveRepository(collection, archive)
+	go service.NewRetentionJob(archiveRepo, service.RetentionConfig{
+		Months:    cfg.RetentionMonths,
+		BatchSize: cfg.RetentionBatchSize,
+		Interval:  cfg.RetentionInterval,
+	}).Run(background)
+
+	// Accounts deleted in users-api are erased from the reservations (GDPR)
+	erasureRepo := repository.NewMongoErasureRepository(collection, archive, collection.Database().Collection(repository.ErasureReceiptsCollection))
+
+	// The menu catalog; pre-ordered items are priced with the reservation
+	menuRepo := repository.NewMongoMenuRepository(collection.Database().Collection(repository.MenuItemsCollection), collection)
+
+	// Invoices, service sheets and the host stand's floor use the restaurant's time zone
+	invoiceLocation, err := time.LoadLocation(cfg.InvoiceTimezone)
+	if err != nil {
+		log.Fatalf("Invalid invoice timezone: %v", err)
+	}
+	floor := domain.FloorPolicy{
+		CleaningTime: cfg.TableCleaningTime,
+		ReservedSoon: cfg.TableReservedSoon,
+		Location:     invoiceLocation,
+	}
+
+	// Tables held during checkout; the reaper announces expired holds before the TTL index purges them
+	holdRepo := repository.NewMongoHoldRepository(collection.Database().Collection(repository.HoldsCollection))
+	go service.NewHoldReaper(holdRepo, rmqPublisher, cfg.HoldSweepInterval).Run(background)
+	bulkJobRepo := repository.NewMongoBulkJobRepository(collection.Database().Collection(repository.BulkJobsCollection))
+
+	svc := service.NewReservationService(repo, userClient, rmqPublisher, policies, calendarFeed, domain.DefaultCalculationPipeline(promotions, pricing), checkIn, webhookSvc, archiveRepo, erasureRepo, menuRepo, holdRepo, bulkJobRepo, cfg.PreOrderCutoff, floor, cfg.HoldTTL)
+	go service.RunUserErasure(background, rmqPublisher, cfg.RabbitMQUserEventsQueue, svc)
 	ctrl := controller.NewReservationController(svc)
+	analyticsSvc := service.NewAnalyticsService(repository.NewMongoAnalyticsRepository(collection, archive, cfg.Currency), cfg.Currency)
+	analyticsCtrl := controller.NewAnalyticsController(analyticsSvc)
+	webhookCtrl := controller.NewWebhookController(webhookSvc)
+	streamCtrl := controller.NewStreamController(eventHub, cfg.StreamHeartbeat)
+
+	// Invoices are numbered from a gap-free counter
+	invoiceRepo := repository.NewMongoInvoiceRepository(
+		collection.Database().Collection(repository.InvoicesCollection),
+		collection.Database().Collection(repository.CountersCollection),
+	)
+	invoiceSvc := service.NewInvoiceService(invoiceRepo, userClient, service.InvoiceConfig{
+		Restaurant: domain.RestaurantDetails{
+			Name:    cfg.RestaurantName,
+			LegalID: cfg.RestaurantLegalID,
+			Address: cfg.RestaurantAddress,
+			Email:   cfg.RestaurantEmail,
+			Phone:   cfg.RestaurantPhone,
+		},
+		NumberPrefix: cfg.InvoicePrefix,
+	})
+	invoiceCtrl := controller.NewInvoiceController(svc, invoiceSvc, invoiceLocation)
+
+	// The kitchen prep list covers the days of the restaurant's time zone, the one invoices are dated in
+	menuCtrl := controller.NewMenuController(service.NewMenuService(menuRepo, cfg.Currency, invoiceLocation), svc, invoiceLocation)
+
+	// The host stand's service sheet, by day and time in the same time zone
+	sheetCtrl := controller.NewServiceSheetController(service.NewServiceSheetService(repo, userClient, invoiceLocation), invoiceLocation)
 
 	// Setup HTTP router
-	router := httptransport.NewRouter(ctrl)
+	router := httptransport.NewRouter(ctrl, analyticsCtrl, webhookCtrl, streamCtrl, invoiceCtrl, menuCtrl, sheetCtrl, userClient, cfg.JWTSecret)
 
 	// Start server
 	addr := ":" + cfg.Port
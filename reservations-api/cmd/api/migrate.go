@@ -0,0 +1,44 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/migrations"
+)
+
+// runMigrate implements "reservations-api migrate [up|status]"
+func runMigrate(migrator *migrations.Migrator, args []string) error {
+	ctx := context.Background()
+
+	command := "up"
+	if len(args) > 0 {
+		command = args[0]
+	}
+
+	switch command {
+	case "up":
+		return migrator.Up(ctx)
+	case "status":
+		statuses, err := migrator.Status(ctx)
+		if err != nil {
+			return err
+		}
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tAPPLIED AT\tDESCRIPTION")
+		for _, st := range statuses {
+			appliedAt := "pending"
+			if st.Applied != nil {
+				appliedAt = st.Applied.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Fprintf(w, "%d\t%s\t%s\n", st.Version, appliedAt, st.Description)
+		}
+		return w.Flush()
+	default:
+		return fmt.Errorf("unknown migrate command %q (expected up or status)", command)
+	}
+}
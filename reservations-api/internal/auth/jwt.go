@@ -0,0 +1,81 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Roles issued by users-api
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
+// Claims holds the identity carried by a users-api access token
+type Claims struct {
+	UserID   string
+	Username string
+	Role     string
+}
+
+// IsAdmin reports whether the token belongs to an administrator
+func (c Claims) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+// CanAccessUser reports whether the caller may act on behalf of the given user
+func (c Claims) CanAccessUser(userID string) bool {
+	return c.IsAdmin() || c.UserID == userID
+}
+
+// Verifier validates access tokens signed by users-api with the shared secret
+type Verifier struct {
+	secret []byte
+}
+
+// NewVerifier creates a new token verifier
+func NewVerifier(secret string) *Verifier {
+	return &Verifier{secret: []byte(secret)}
+}
+
+// Verify parses and validates a token, returning its claims
+func (v *Verifier) Verify(tokenString string) (*Claims, error) {
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return v.secret, nil
+	})
+	if err != nil || !token.Valid {
+		return nil, fmt.Errorf("invalid token: %w", err)
+	}
+
+	mapClaims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, errors.New("invalid token claims")
+	}
+
+	// users-api encodes the user ID as a JSON number
+	sub, ok := mapClaims["sub"].(float64)
+	if !ok {
+		return nil, errors.New("invalid user id in token")
+	}
+
+	// Refresh tokens must not be accepted as access tokens
+	if tokenType, _ := mapClaims["type"].(string); tokenType == "refresh" {
+		return nil, errors.New("refresh tokens are not accepted")
+	}
+
+	username, _ := mapClaims["username"].(string)
+	role, _ := mapClaims["role"].(string)
+
+	return &Claims{
+		UserID:   strconv.FormatUint(uint64(sub), 10),
+		Username: username,
+		Role:     role,
+	}, nil
+}
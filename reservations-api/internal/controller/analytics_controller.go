@@ -0,0 +1,171 @@
+package controller
+
+import (
+	"encoding/csv"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	// Reporting window used when from/to are omitted
+	defaultAnalyticsDays = 30
+	// Longest reporting window accepted
+	maxAnalyticsDays = 366
+)
+
+type AnalyticsController struct {
+	service service.AnalyticsService
+}
+
+func NewAnalyticsController(service service.AnalyticsService) *AnalyticsController {
+	return &AnalyticsController{service: service}
+}
+
+// GetCovers handles GET /api/admin/analytics/covers?period=day|week|month&from=&to=&meal_type=&format=
+func (c *AnalyticsController) GetCovers(ctx *gin.Context) {
+	rng, err := parseAnalyticsRange(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	period := ctx.DefaultQuery("period", domain.PeriodDay)
+	if period != domain.PeriodDay && period != domain.PeriodWeek && period != domain.PeriodMonth {
+		ctx.Error(domain.NewValidationError("period", "must be one of day, week, month"))
+		return
+	}
+
+	report, err := c.service.Covers(ctx.Request.Context(), rng, period)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "covers", report)
+}
+
+// GetUtilisation handles GET /api/admin/analytics/utilisation
+func (c *AnalyticsController) GetUtilisation(ctx *gin.Context) {
+	rng, err := parseAnalyticsRange(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report, err := c.service.Utilisation(ctx.Request.Context(), rng)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "utilisation", report)
+}
+
+// GetRevenue handles GET /api/admin/analytics/revenue
+func (c *AnalyticsController) GetRevenue(ctx *gin.Context) {
+	rng, err := parseAnalyticsRange(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report, err := c.service.Revenue(ctx.Request.Context(), rng)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "revenue", report)
+}
+
+// GetRates handles GET /api/admin/analytics/rates
+func (c *AnalyticsController) GetRates(ctx *gin.Context) {
+	rng, err := parseAnalyticsRange(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report, err := c.service.Rates(ctx.Request.Context(), rng)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "rates", report)
+}
+
+// GetLeadTime handles GET /api/admin/analytics/lead-time
+func (c *AnalyticsController) GetLeadTime(ctx *gin.Context) {
+	rng, err := parseAnalyticsRange(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report, err := c.service.LeadTime(ctx.Request.Context(), rng)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "lead-time", report)
+}
+
+// parseAnalyticsRange reads from/to (YYYY-MM-DD, both inclusive) and meal_type,
+// defaulting to the last 30 days
+func parseAnalyticsRange(ctx *gin.Context) (domain.AnalyticsRange, error) {
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return parseDateRange(ctx, today.AddDate(0, 0, -defaultAnalyticsDays+1), today, time.UTC)
+}
+
+// parseDateRange reads from/to (YYYY-MM-DD, both inclusive, days of loc) and meal_type,
+// defaulting to the days from defaultFrom to defaultTo
+func parseDateRange(ctx *gin.Context, defaultFrom, defaultTo time.Time, loc *time.Location) (domain.AnalyticsRange, error) {
+	rng := domain.AnalyticsRange{
+		From:     defaultFrom,
+		To:       defaultTo.AddDate(0, 0, 1),
+		MealType: ctx.Query("meal_type"),
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		t, err := time.ParseInLocation("2006-01-02", from, loc)
+		if err != nil {
+			return rng, domain.NewValidationError("from", "must be YYYY-MM-DD")
+		}
+		rng.From = t
+	}
+	if to := ctx.Query("to"); to != "" {
+		t, err := time.ParseInLocation("2006-01-02", to, loc)
+		if err != nil {
+			return rng, domain.NewValidationError("to", "must be YYYY-MM-DD")
+		}
+		rng.To = t.AddDate(0, 0, 1)
+	}
+
+	if !rng.To.After(rng.From) {
+		return rng, domain.NewValidationError("to", "must not be before from")
+	}
+	if rng.Days() > maxAnalyticsDays {
+		return rng, domain.NewValidationError("from", fmt.Sprintf("range cannot exceed %d days", maxAnalyticsDays))
+	}
+
+	return rng, nil
+}
+
+// respondReport writes a report as JSON, or as CSV when format=csv
+func respondReport(ctx *gin.Context, name string, report domain.Tabular) {
+	if ctx.Query("format") != "csv" {
+		ctx.JSON(http.StatusOK, report)
+		return
+	}
+
+	header, rows := report.Table()
+	ctx.Header("Content-Type", "text/csv; charset=utf-8")
+	ctx.Header("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	ctx.Status(http.StatusOK)
+
+	w := csv.NewWriter(ctx.Writer)
+	_ = w.Write(header)
+	_ = w.WriteAll(rows)
+}
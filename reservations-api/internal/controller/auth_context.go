@@ -0,0 +1,24 @@
+package controller
+
+import (
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/auth"
+	"github.com/gin-gonic/gin"
+)
+
+// claimsKey is the gin context key holding the authenticated caller
+const claimsKey = "auth_claims"
+
+// SetClaims stores the authenticated caller in the request context
+func SetClaims(ctx *gin.Context, claims *auth.Claims) {
+	ctx.Set(claimsKey, claims)
+}
+
+// ClaimsFromContext returns the authenticated caller, if any
+func ClaimsFromContext(ctx *gin.Context) (*auth.Claims, bool) {
+	value, ok := ctx.Get(claimsKey)
+	if !ok {
+		return nil, false
+	}
+	claims, ok := value.(*auth.Claims)
+	return claims, ok
+}
@@ -0,0 +1,98 @@
+package controller
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// How long browsers may reuse a calendar before revalidating it with the ETag
+const availabilityMaxAge = time.Minute
+
+// GetAvailabilityCalendar handles GET /api/tables/calendar?from=YYYY-MM-DD&to=YYYY-MM-DD&meal_type=dinner&guests=4&accessible=true
+// meal_type is optional (all meal types when omitted), guests defaults to 1 and
+// accessible=true only counts wheelchair accessible tables.
+func (c *ReservationController) GetAvailabilityCalendar(ctx *gin.Context) {
+	q, err := parseAvailabilityQuery(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	calendar, err := c.service.GetAvailabilityCalendar(ctx.Request.Context(), q)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	body, err := json.Marshal(calendar)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	sum := sha256.Sum256(body)
+	etag := `"` + hex.EncodeToString(sum[:16]) + `"`
+
+	ctx.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int(availabilityMaxAge.Seconds())))
+	ctx.Header("ETag", etag)
+	if ctx.GetHeader("If-None-Match") == etag {
+		ctx.Status(http.StatusNotModified)
+		return
+	}
+
+	ctx.Data(http.StatusOK, "application/json; charset=utf-8", body)
+}
+
+// parseAvailabilityQuery validates the calendar query; "to" is inclusive of that day
+func parseAvailabilityQuery(ctx *gin.Context) (domain.AvailabilityQuery, error) {
+	q := domain.AvailabilityQuery{MealType: ctx.Query("meal_type"), Guests: 1, Accessible: ctx.Query("accessible") == "true"}
+	verr := &domain.ValidationError{}
+
+	from, err := time.Parse("2006-01-02", ctx.Query("from"))
+	if err != nil {
+		verr.Add("from", "is required and must be YYYY-MM-DD")
+	}
+	to, err := time.Parse("2006-01-02", ctx.Query("to"))
+	if err != nil {
+		verr.Add("to", "is required and must be YYYY-MM-DD")
+	}
+	q.From, q.To = from, to.AddDate(0, 0, 1)
+
+	if q.MealType != "" && !isMealType(q.MealType) {
+		verr.Add("meal_type", "must be one of breakfast, lunch, dinner, event")
+	}
+	if guests := ctx.Query("guests"); guests != "" {
+		n, err := strconv.Atoi(guests)
+		if err != nil || n < 1 {
+			verr.Add("guests", "must be a positive number")
+		}
+		q.Guests = n
+	}
+
+	if verr.Fields["from"] == "" && verr.Fields["to"] == "" {
+		if to.Before(from) {
+			verr.Add("to", "must not be before from")
+		} else if days := int(q.To.Sub(q.From).Hours() / 24); days > domain.MaxAvailabilityDays {
+			verr.Add("from", fmt.Sprintf("range cannot exceed %d days", domain.MaxAvailabilityDays))
+		}
+	}
+
+	return q, verr.OrNil()
+}
+
+func isMealType(mealType string) bool {
+	for _, mt := range domain.MealTypes {
+		if mt == mealType {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,109 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+func init() {
+	gin.SetMode(gin.TestMode)
+}
+
+// fakeCalendarService builds the calendar from the query alone; the rest of the service is not used
+type fakeCalendarService struct {
+	service.ReservationService
+}
+
+func (fakeCalendarService) GetAvailabilityCalendar(_ context.Context, q domain.AvailabilityQuery) (*domain.AvailabilityCalendar, error) {
+	return domain.NewAvailabilityCalendar(q, nil), nil
+}
+
+func availabilityContext(target string) (*gin.Context, *httptest.ResponseRecorder) {
+	rec := httptest.NewRecorder()
+	ctx, _ := gin.CreateTestContext(rec)
+	ctx.Request = httptest.NewRequest(http.MethodGet, target, nil)
+	return ctx, rec
+}
+
+func TestParseAvailabilityQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		wantField string // field of the validation error, "" when valid
+		wantDays  int
+	}{
+		{"single day, to is inclusive", "from=2030-03-01&to=2030-03-01", "", 1},
+		{"whole range", "from=2030-03-01&to=2030-03-31&meal_type=dinner&guests=4", "", 31},
+		{"longest range", "from=2030-03-01&to=2030-05-01", "", domain.MaxAvailabilityDays},
+		{"range too long", "from=2030-03-01&to=2030-05-02", "from", 0},
+		{"to before from", "from=2030-03-02&to=2030-03-01", "to", 0},
+		{"missing from", "to=2030-03-01", "from", 0},
+		{"bad to", "from=2030-03-01&to=01/03/2030", "to", 0},
+		{"unknown meal type", "from=2030-03-01&to=2030-03-01&meal_type=brunch", "meal_type", 0},
+		{"no guests", "from=2030-03-01&to=2030-03-01&guests=0", "guests", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, _ := availabilityContext("/api/tables/calendar?" + tt.query)
+			q, err := parseAvailabilityQuery(ctx)
+
+			if tt.wantField != "" {
+				var verr *domain.ValidationError
+				if !errors.As(err, &verr) || verr.Fields[tt.wantField] == "" {
+					t.Fatalf("parseAvailabilityQuery() error = %v, want a validation error on %s", err, tt.wantField)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseAvailabilityQuery() error = %v", err)
+			}
+			if days := int(q.To.Sub(q.From) / (24 * time.Hour)); days != tt.wantDays {
+				t.Errorf("query covers %d days, want %d", days, tt.wantDays)
+			}
+		})
+	}
+
+	ctx, _ := availabilityContext("/api/tables/calendar?from=2030-03-01&to=2030-03-01&accessible=true")
+	if q, err := parseAvailabilityQuery(ctx); err != nil || q.Guests != 1 || !q.Accessible || q.MealType != "" {
+		t.Errorf("defaults = %+v, %v, want 1 guest, accessible, every meal", q, err)
+	}
+}
+
+func TestGetAvailabilityCalendarETag(t *testing.T) {
+	c := NewReservationController(fakeCalendarService{})
+	const target = "/api/tables/calendar?from=2030-03-01&to=2030-03-07&meal_type=dinner"
+
+	ctx, rec := availabilityContext(target)
+	c.GetAvailabilityCalendar(ctx)
+	etag := rec.Header().Get("ETag")
+	if rec.Code != http.StatusOK || etag == "" || rec.Body.Len() == 0 {
+		t.Fatalf("first request = %d with ETag %q, want 200 with an ETag and a body", rec.Code, etag)
+	}
+	if rec.Header().Get("Cache-Control") != "public, max-age=60" {
+		t.Errorf("Cache-Control = %q", rec.Header().Get("Cache-Control"))
+	}
+
+	ctx, rec = availabilityContext(target)
+	ctx.Request.Header.Set("If-None-Match", etag)
+	c.GetAvailabilityCalendar(ctx)
+	ctx.Writer.WriteHeaderNow()
+	if rec.Code != http.StatusNotModified || rec.Body.Len() != 0 {
+		t.Errorf("revalidation = %d with %d bytes, want an empty 304", rec.Code, rec.Body.Len())
+	}
+
+	ctx, rec = availabilityContext(target)
+	ctx.Request.Header.Set("If-None-Match", `"stale"`)
+	c.GetAvailabilityCalendar(ctx)
+	if rec.Code != http.StatusOK || rec.Header().Get("ETag") != etag {
+		t.Errorf("stale revalidation = %d, want 200 with the same ETag", rec.Code)
+	}
+}
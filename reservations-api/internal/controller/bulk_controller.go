@@ -0,0 +1,59 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// StartBulkJob handles POST /api/admin/bulk-jobs
+// Cancels or moves the reservations of a service; the job runs in the background
+func (c *ReservationController) StartBulkJob(ctx *gin.Context) {
+	var req domain.BulkJobRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		ctx.Error(domain.ForbiddenError("bulk jobs need an authenticated admin"))
+		return
+	}
+
+	job, err := c.service.StartBulkJob(ctx.Request.Context(), claims.UserID, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Location", "/api/admin/bulk-jobs/"+job.ID.Hex())
+	ctx.JSON(http.StatusAccepted, job)
+}
+
+// GetBulkJob handles GET /api/admin/bulk-jobs/:id (progress and per-reservation results)
+func (c *ReservationController) GetBulkJob(ctx *gin.Context) {
+	job, err := c.service.GetBulkJob(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, job)
+}
+
+// ListBulkJobs handles GET /api/admin/bulk-jobs?limit=
+func (c *ReservationController) ListBulkJobs(ctx *gin.Context) {
+	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
+
+	jobs, err := c.service.ListBulkJobs(ctx.Request.Context(), limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, jobs)
+}
@@ -0,0 +1,74 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/ical"
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	calendarContentType = "text/calendar; charset=utf-8"
+	// How often subscribed calendar clients are asked to refresh the feed
+	calendarFeedRefresh = time.Hour
+)
+
+// GetReservationICS handles GET /api/reservations/:id/ics
+func (c *ReservationController) GetReservationICS(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	reservation, err := c.service.GetReservation(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	cal := ical.Calendar{Name: "Restaurant reservation"}
+	if err := ical.WriteCalendar(&buf, cal, []domain.Reservation{*reservation}, time.Now()); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="reservation-`+id+`.ics"`)
+	ctx.Data(http.StatusOK, calendarContentType, buf.Bytes())
+}
+
+// GetCalendarFeedLinks handles GET /api/reservations/user/:user_id/calendar
+// Only the user (or an admin) can obtain the secret feed URL
+func (c *ReservationController) GetCalendarFeedLinks(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || !claims.CanAccessUser(userID) {
+		ctx.Error(domain.ForbiddenError("you can only access your own calendar feed"))
+		return
+	}
+
+	ctx.JSON(http.StatusOK, c.service.GetCalendarFeedLinks(userID))
+}
+
+// GetCalendarFeed handles GET /api/reservations/user/:user_id/calendar.ics?token=...
+func (c *ReservationController) GetCalendarFeed(ctx *gin.Context) {
+	userID := ctx.Param("user_id")
+	token := ctx.Query("token")
+
+	reservations, err := c.service.GetCalendarFeed(ctx.Request.Context(), userID, token)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	cal := ical.Calendar{Name: "My reservations", RefreshInterval: calendarFeedRefresh}
+	if err := ical.WriteCalendar(&buf, cal, reservations, time.Now()); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, max-age=300")
+	ctx.Data(http.StatusOK, calendarContentType, buf.Bytes())
+}
@@ -0,0 +1,88 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+	qrcode "github.com/skip2/go-qrcode"
+)
+
+// Side of the check-in QR image in pixels
+const checkInQRSize = 320
+
+// GetCheckInToken handles GET /api/reservations/:id/checkin
+// Only the reservation owner (or an admin) can obtain its check-in code
+func (c *ReservationController) GetCheckInToken(ctx *gin.Context) {
+	reservation, ok := ownedReservation(ctx, c.service)
+	if !ok {
+		return
+	}
+
+	token, err := c.service.GetCheckInToken(reservation)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.JSON(http.StatusOK, token)
+}
+
+// GetCheckInQR handles GET /api/reservations/:id/checkin.png
+func (c *ReservationController) GetCheckInQR(ctx *gin.Context) {
+	reservation, ok := ownedReservation(ctx, c.service)
+	if !ok {
+		return
+	}
+
+	token, err := c.service.GetCheckInToken(reservation)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	png, err := qrcode.Encode(token.Token, qrcode.Medium, checkInQRSize)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Data(http.StatusOK, "image/png", png)
+}
+
+// CheckIn handles POST /api/checkin (staff scanning a guest's QR code)
+func (c *ReservationController) CheckIn(ctx *gin.Context) {
+	var req domain.CheckInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	reservation, err := c.service.CheckIn(ctx.Request.Context(), req.Token)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+// ownedReservation loads the :id reservation and checks the caller may act on it
+func ownedReservation(ctx *gin.Context, reservations service.ReservationService) (*domain.Reservation, bool) {
+	reservation, err := reservations.GetReservation(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return nil, false
+	}
+
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok || !claims.CanAccessUser(reservation.OwnerID) {
+		ctx.Error(domain.ForbiddenError("you can only access your own reservations"))
+		return nil, false
+	}
+
+	return reservation, true
+}
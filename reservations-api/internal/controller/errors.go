@@ -0,0 +1,49 @@
+package controller
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/go-playground/validator/v10"
+)
+
+// bindingError turns a request binding failure into a validation error with field details
+func bindingError(err error) error {
+	var validationErrors validator.ValidationErrors
+	if errors.As(err, &validationErrors) {
+		verr := &domain.ValidationError{}
+		for _, fe := range validationErrors {
+			verr.Add(fe.Field(), fieldMessage(fe))
+		}
+		return verr
+	}
+
+	var typeErr *json.UnmarshalTypeError
+	if errors.As(err, &typeErr) && typeErr.Field != "" {
+		return domain.NewValidationError(typeErr.Field, fmt.Sprintf("must be a %s", typeErr.Type.String()))
+	}
+
+	if errors.Is(err, io.EOF) {
+		return domain.NewValidationError("body", "is required")
+	}
+	return domain.NewValidationError("body", "malformed JSON: "+err.Error())
+}
+
+// fieldMessage describes a failed binding rule
+func fieldMessage(fe validator.FieldError) string {
+	switch fe.Tag() {
+	case "required":
+		return "is required"
+	case "oneof":
+		return "must be one of " + strings.ReplaceAll(fe.Param(), " ", ", ")
+	case "min", "gte":
+		return "must be at least " + fe.Param()
+	case "max", "lte":
+		return "must be at most " + fe.Param()
+	}
+	return fmt.Sprintf("failed the %s rule", fe.Tag())
+}
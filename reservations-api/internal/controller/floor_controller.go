@@ -0,0 +1,55 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// GetFloorStatus handles GET /api/tables/status?meal_type=dinner
+// Live status of each table today: free, reserved_soon, occupied or cleaning
+func (c *ReservationController) GetFloorStatus(ctx *gin.Context) {
+	mealType := ctx.Query("meal_type")
+	if !isMealType(mealType) {
+		ctx.Error(domain.NewValidationError("meal_type", "is required and must be breakfast, lunch, dinner or event"))
+		return
+	}
+
+	floor, err := c.service.GetFloorStatus(ctx.Request.Context(), mealType)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusOK, floor)
+}
+
+// SeatWalkIn handles POST /api/walk-ins (the host seating guests without a reservation)
+func (c *ReservationController) SeatWalkIn(ctx *gin.Context) {
+	var req domain.WalkInRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	walkIn, err := c.service.SeatWalkIn(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, walkIn)
+}
+
+// CompleteReservation handles POST /api/reservations/:id/complete (the guests have left)
+func (c *ReservationController) CompleteReservation(ctx *gin.Context) {
+	reservation, err := c.service.CompleteReservation(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
@@ -0,0 +1,33 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/gin-gonic/gin"
+)
+
+// HoldTable handles POST /api/holds
+// Holds a table for the caller during checkout; the token goes in the reservation's hold_token
+func (c *ReservationController) HoldTable(ctx *gin.Context) {
+	var req domain.HoldRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	claims, ok := ClaimsFromContext(ctx)
+	if !ok {
+		ctx.Error(domain.ForbiddenError("holds need an authenticated user"))
+		return
+	}
+
+	grant, err := c.service.HoldTable(ctx.Request.Context(), claims.UserID, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Cache-Control", "no-store")
+	ctx.JSON(http.StatusCreated, grant)
+}
@@ -0,0 +1,47 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/invoice"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type InvoiceController struct {
+	reservations service.ReservationService
+	invoices     service.InvoiceService
+	// location in which invoice dates are printed
+	location *time.Location
+}
+
+func NewInvoiceController(reservations service.ReservationService, invoices service.InvoiceService, location *time.Location) *InvoiceController {
+	return &InvoiceController{reservations: reservations, invoices: invoices, location: location}
+}
+
+// GetInvoicePDF handles GET /api/reservations/:id/invoice.pdf
+// Only the reservation owner (or an admin) can download its invoice
+func (c *InvoiceController) GetInvoicePDF(ctx *gin.Context) {
+	reservation, ok := ownedReservation(ctx, c.reservations)
+	if !ok {
+		return
+	}
+
+	inv, err := c.invoices.GetInvoice(ctx.Request.Context(), reservation)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	var buf bytes.Buffer
+	if err := invoice.WritePDF(&buf, *inv, c.location); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Header("Content-Disposition", `attachment; filename="factura-`+inv.Number+`.pdf"`)
+	ctx.Header("Cache-Control", "private, no-store")
+	ctx.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -0,0 +1,129 @@
+package controller
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type MenuController struct {
+	service      service.MenuService
+	reservations service.ReservationService
+	// location whose days the kitchen prep list covers
+	location *time.Location
+}
+
+func NewMenuController(service service.MenuService, reservations service.ReservationService, location *time.Location) *MenuController {
+	return &MenuController{service: service, reservations: reservations, location: location}
+}
+
+// ListMenu handles GET /api/menu?meal_type=
+// Only the items that can be ordered are listed
+func (c *MenuController) ListMenu(ctx *gin.Context) {
+	c.listMenu(ctx, domain.MenuFilter{MealType: ctx.Query("meal_type")})
+}
+
+// ListMenuAdmin handles GET /api/admin/menu?meal_type=, inactive items included
+func (c *MenuController) ListMenuAdmin(ctx *gin.Context) {
+	c.listMenu(ctx, domain.MenuFilter{MealType: ctx.Query("meal_type"), IncludeInactive: true})
+}
+
+func (c *MenuController) listMenu(ctx *gin.Context, filter domain.MenuFilter) {
+	items, err := c.service.ListMenu(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, items)
+}
+
+// CreateMenuItem handles POST /api/admin/menu
+func (c *MenuController) CreateMenuItem(ctx *gin.Context) {
+	var req domain.MenuItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	item, err := c.service.CreateMenuItem(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, item)
+}
+
+// UpdateMenuItem handles PUT /api/admin/menu/:id
+func (c *MenuController) UpdateMenuItem(ctx *gin.Context) {
+	var req domain.MenuItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	item, err := c.service.UpdateMenuItem(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, item)
+}
+
+// DeleteMenuItem handles DELETE /api/admin/menu/:id
+// The item is deactivated rather than removed, since pre-orders reference it
+func (c *MenuController) DeleteMenuItem(ctx *gin.Context) {
+	if err := c.service.DeactivateMenuItem(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// UpdatePreOrder handles PUT /api/reservations/:id/pre-order
+// Only the reservation owner (or an admin) can change its pre-order
+func (c *MenuController) UpdatePreOrder(ctx *gin.Context) {
+	var req domain.PreOrderRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	reservation, ok := ownedReservation(ctx, c.reservations)
+	if !ok {
+		return
+	}
+
+	reservation, err := c.reservations.UpdatePreOrder(ctx.Request.Context(), reservation, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+// GetKitchenPrep handles GET /api/admin/kitchen/prep?from=&to=&meal_type=&format=
+// The range defaults to today in the restaurant's time zone
+func (c *MenuController) GetKitchenPrep(ctx *gin.Context) {
+	now := time.Now().In(c.location)
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.location)
+	rng, err := parseDateRange(ctx, today, today, c.location)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report, err := c.service.PrepReport(ctx.Request.Context(), rng)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "kitchen-prep", report)
+}
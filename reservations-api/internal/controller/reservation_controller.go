@@ -1,8 +1,11 @@
 package controller
 
 import (
+	"errors"
+	"io"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
@@ -21,72 +24,108 @@ func NewReservationController(service service.ReservationService) *ReservationCo
 func (c *ReservationController) CreateReservation(ctx *gin.Context) {
 	var req domain.CreateReservationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
 	reservation, err := c.service.CreateReservation(ctx.Request.Context(), req)
 	if err != nil {
-		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusCreated, reservation)
 }
 
+// QuoteReservation handles POST /api/reservations/quote
+// Takes the same body as create and returns the price without booking
+func (c *ReservationController) QuoteReservation(ctx *gin.Context) {
+	var req domain.CreateReservationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	quote, err := c.service.QuoteReservation(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, quote)
+}
+
 // GetReservation handles GET /api/reservations/:id
 func (c *ReservationController) GetReservation(ctx *gin.Context) {
 	id := ctx.Param("id")
 
 	reservation, err := c.service.GetReservation(ctx.Request.Context(), id)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, reservation)
 }
 
-// GetAllReservations handles GET /api/reservations
+// GetAllReservations handles GET /api/reservations?owner_id=&meal_type=&status=&from=&to=&limit=&offset=
 func (c *ReservationController) GetAllReservations(ctx *gin.Context) {
 	limit, _ := strconv.Atoi(ctx.DefaultQuery("limit", "50"))
 	offset, _ := strconv.Atoi(ctx.DefaultQuery("offset", "0"))
 
-	reservations, err := c.service.GetAllReservations(ctx.Request.Context(), limit, offset)
+	filter, err := parseReservationFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	reservations, err := c.service.GetAllReservations(ctx.Request.Context(), filter, limit, offset)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, reservations)
 }
 
-// GetUserReservations handles GET /api/reservations/user/:user_id
+// GetUserReservations handles GET /api/reservations/user/:user_id?include_archived=true
 func (c *ReservationController) GetUserReservations(ctx *gin.Context) {
 	userID := ctx.Param("user_id")
+	includeArchived := ctx.Query("include_archived") == "true"
 
-	reservations, err := c.service.GetUserReservations(ctx.Request.Context(), userID)
+	reservations, err := c.service.GetUserReservations(ctx.Request.Context(), userID, includeArchived)
 	if err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, reservations)
 }
 
+// GetErasureReceipt handles GET /api/admin/erasures/:user_id
+func (c *ReservationController) GetErasureReceipt(ctx *gin.Context) {
+	receipt, err := c.service.GetErasureReceipt(ctx.Request.Context(), ctx.Param("user_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, receipt)
+}
+
 // UpdateReservation handles PUT /api/reservations/:id
 func (c *ReservationController) UpdateReservation(ctx *gin.Context) {
 	id := ctx.Param("id")
 
 	var req domain.UpdateReservationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
 	reservation, err := c.service.UpdateReservation(ctx.Request.Context(), id, req)
 	if err != nil {
-		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -94,15 +133,38 @@ func (c *ReservationController) UpdateReservation(ctx *gin.Context) {
 }
 
 // DeleteReservation handles DELETE /api/reservations/:id
+// Reservations are no longer hard-deleted: this cancels them under their cancellation policy
 func (c *ReservationController) DeleteReservation(ctx *gin.Context) {
 	id := ctx.Param("id")
 
-	if err := c.service.DeleteReservation(ctx.Request.Context(), id); err != nil {
-		ctx.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	req := domain.CancelReservationRequest{Reason: ctx.Query("reason")}
+	reservation, err := c.service.CancelReservation(ctx.Request.Context(), id, req)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
-	ctx.Status(http.StatusNoContent)
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+// CancelReservation handles POST /api/reservations/:id/cancel
+func (c *ReservationController) CancelReservation(ctx *gin.Context) {
+	id := ctx.Param("id")
+
+	// The body is optional: an empty request cancels without a reason
+	var req domain.CancelReservationRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	reservation, err := c.service.CancelReservation(ctx.Request.Context(), id, req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
 }
 
 // ConfirmReservation handles POST /api/reservations/:id/confirm
@@ -111,34 +173,118 @@ func (c *ReservationController) ConfirmReservation(ctx *gin.Context) {
 
 	var req domain.ConfirmReservationRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		ctx.Error(bindingError(err))
 		return
 	}
 
 	reservation, err := c.service.ConfirmReservation(ctx.Request.Context(), id, req)
 	if err != nil {
-		ctx.JSON(http.StatusUnprocessableEntity, gin.H{"error": err.Error()})
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, reservation)
+}
+
+// RecordPrepayment handles POST /api/admin/reservations/:id/prepayment
+func (c *ReservationController) RecordPrepayment(ctx *gin.Context) {
+	var req domain.RecordPrepaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	reservation, err := c.service.RecordPrepayment(ctx.Request.Context(), ctx.Param("id"), req)
+	if err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	ctx.JSON(http.StatusOK, reservation)
 }
 
-// GetAvailableTables handles GET /api/tables/available?date=YYYY-MM-DD&meal_type=dinner
+// GetAvailableTables handles GET /api/tables/available?date=YYYY-MM-DD&meal_type=dinner&accessible=true
 func (c *ReservationController) GetAvailableTables(ctx *gin.Context) {
-	date := ctx.Query("date")       // Format: "2006-01-02"
+	date := ctx.Query("date") // Format: "2006-01-02"
 	mealType := ctx.Query("meal_type")
 
-	if date == "" || mealType == "" {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "date and meal_type are required"})
+	verr := &domain.ValidationError{}
+	if date == "" {
+		verr.Add("date", "is required")
+	}
+	if mealType == "" {
+		verr.Add("meal_type", "is required")
+	}
+	if err := verr.OrNil(); err != nil {
+		ctx.Error(err)
 		return
 	}
 
 	tables, err := c.service.GetAvailableTables(ctx.Request.Context(), date, mealType)
 	if err != nil {
-		ctx.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
+	// accessible=true keeps the wheelchair accessible tables
+	if ctx.Query("accessible") == "true" {
+		accessible := []domain.TableConfig{}
+		for _, table := range tables {
+			if table.Accessible {
+				accessible = append(accessible, table)
+			}
+		}
+		tables = accessible
+	}
+
 	ctx.JSON(http.StatusOK, tables)
 }
+
+// GetCancellationPolicies handles GET /api/cancellation-policies
+func (c *ReservationController) GetCancellationPolicies(ctx *gin.Context) {
+	ctx.JSON(http.StatusOK, c.service.GetCancellationPolicies())
+}
+
+// parseReservationFilter reads the listing filters shared by the listing and the export.
+// from/to accept a date (YYYY-MM-DD, "to" inclusive of that day) or an RFC 3339 timestamp.
+func parseReservationFilter(ctx *gin.Context) (domain.ReservationFilter, error) {
+	filter := domain.ReservationFilter{
+		OwnerID:  ctx.Query("owner_id"),
+		MealType: ctx.Query("meal_type"),
+		Status:   ctx.Query("status"),
+
+		Allergy:    ctx.Query("allergy"),
+		Dietary:    ctx.Query("dietary"),
+		Occasion:   ctx.Query("occasion"),
+		Wheelchair: ctx.Query("wheelchair") == "true",
+	}
+
+	if from := ctx.Query("from"); from != "" {
+		t, _, err := parseDateOrTime(from)
+		if err != nil {
+			return filter, domain.NewValidationError("from", "must be YYYY-MM-DD or RFC 3339")
+		}
+		filter.From = t
+	}
+	if to := ctx.Query("to"); to != "" {
+		t, dateOnly, err := parseDateOrTime(to)
+		if err != nil {
+			return filter, domain.NewValidationError("to", "must be YYYY-MM-DD or RFC 3339")
+		}
+		if dateOnly {
+			t = t.AddDate(0, 0, 1)
+		}
+		filter.To = t
+	}
+
+	return filter, nil
+}
+
+// parseDateOrTime parses "2006-01-02" or RFC 3339, reporting whether only a date was given
+func parseDateOrTime(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	return t, false, err
+}
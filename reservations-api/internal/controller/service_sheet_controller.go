@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"bytes"
+	"net/http"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/servicesheet"
+	"github.com/gin-gonic/gin"
+)
+
+type ServiceSheetController struct {
+	service service.ServiceSheetService
+	// location whose days the sheet covers
+	location *time.Location
+}
+
+func NewServiceSheetController(service service.ServiceSheetService, location *time.Location) *ServiceSheetController {
+	return &ServiceSheetController{service: service, location: location}
+}
+
+// GetServiceSheet handles GET /api/service-sheet?date=YYYY-MM-DD&format=json|html|pdf
+// The date defaults to today in the restaurant's time zone
+func (c *ServiceSheetController) GetServiceSheet(ctx *gin.Context) {
+	now := time.Now().In(c.location)
+	day := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, c.location)
+	if s := ctx.Query("date"); s != "" {
+		t, err := time.ParseInLocation("2006-01-02", s, c.location)
+		if err != nil {
+			ctx.Error(domain.NewValidationError("date", "must be YYYY-MM-DD"))
+			return
+		}
+		day = t
+	}
+
+	format := ctx.DefaultQuery("format", "json")
+	if format != "json" && format != "html" && format != "pdf" {
+		ctx.Error(domain.NewValidationError("format", "must be json, html or pdf"))
+		return
+	}
+
+	sheet, err := c.service.GetServiceSheet(ctx.Request.Context(), day)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	// Guest names and phones: never cached by intermediaries
+	ctx.Header("Cache-Control", "private, no-store")
+	var buf bytes.Buffer
+	switch format {
+	case "html":
+		if err := servicesheet.WriteHTML(&buf, sheet); err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Data(http.StatusOK, "text/html; charset=utf-8", buf.Bytes())
+	case "pdf":
+		if err := servicesheet.WritePDF(&buf, sheet); err != nil {
+			ctx.Error(err)
+			return
+		}
+		ctx.Header("Content-Disposition", `inline; filename="servicio-`+sheet.Date+`.pdf"`)
+		ctx.Data(http.StatusOK, "application/pdf", buf.Bytes())
+	default:
+		ctx.JSON(http.StatusOK, sheet)
+	}
+}
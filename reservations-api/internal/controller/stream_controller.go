@@ -0,0 +1,97 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// How long browsers wait before reconnecting a dropped stream
+const streamRetry = 3 * time.Second
+
+type StreamController struct {
+	hub       *service.EventHub
+	heartbeat time.Duration
+}
+
+func NewStreamController(hub *service.EventHub, heartbeat time.Duration) *StreamController {
+	return &StreamController{hub: hub, heartbeat: heartbeat}
+}
+
+// StreamReservations handles GET /api/reservations/stream?date=YYYY-MM-DD&meal_type=dinner
+// Server-Sent Events named after the operation (create, update, confirm, cancel, checkin, complete)
+// whose data is the event message. Reconnecting clients send Last-Event-ID (or
+// ?last_event_id=) to receive what they missed; a "reset" event means the gap is no
+// longer buffered and the client must reload.
+func (c *StreamController) StreamReservations(ctx *gin.Context) {
+	filter := service.EventFilter{Date: ctx.Query("date"), MealType: ctx.Query("meal_type")}
+	if filter.Date != "" {
+		if _, err := time.Parse("2006-01-02", filter.Date); err != nil {
+			ctx.Error(domain.NewValidationError("date", "must be YYYY-MM-DD"))
+			return
+		}
+	}
+	if filter.MealType != "" && !isMealType(filter.MealType) {
+		ctx.Error(domain.NewValidationError("meal_type", "must be one of breakfast, lunch, dinner, event"))
+		return
+	}
+
+	lastEventID := ctx.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = ctx.Query("last_event_id")
+	}
+
+	sub, backlog, resumed := c.hub.Subscribe(filter, lastEventID)
+	defer sub.Close()
+
+	ctx.Header("Content-Type", "text/event-stream")
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("Connection", "keep-alive")
+	// Stop reverse proxies (nginx) from buffering the stream
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+
+	w := ctx.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+	if !resumed {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, msg := range backlog {
+		writeStreamEvent(w, msg)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case msg, ok := <-sub.Events:
+			if !ok {
+				// Dropped for falling behind: the client reconnects and resumes
+				return
+			}
+			writeStreamEvent(w, msg)
+		case <-heartbeat.C:
+			// Comment line that keeps idle connections (and proxies) open
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		w.Flush()
+	}
+}
+
+func writeStreamEvent(w io.Writer, msg service.EventMessage) {
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", msg.ID, msg.Operation, data)
+}
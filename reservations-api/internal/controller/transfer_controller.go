@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/transfer"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+)
+
+const (
+	// Upper bound for an import file
+	maxImportBytes = 10 << 20
+	// Rows written between flushes while streaming an export
+	exportFlushEvery = 200
+)
+
+// ImportReservations handles POST /api/admin/reservations/import?format=csv|ndjson&dry_run=true
+func (c *ReservationController) ImportReservations(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", transfer.FormatCSV)
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+
+	body := http.MaxBytesReader(ctx.Writer, ctx.Request.Body, maxImportBytes)
+	rows, err := transfer.ReadRows(body, format)
+	if err != nil {
+		ctx.Error(domain.NewValidationError("body", err.Error()))
+		return
+	}
+
+	// Apply the same binding rules as POST /api/reservations
+	for i := range rows {
+		if rows[i].Error != "" {
+			continue
+		}
+		if err := binding.Validator.ValidateStruct(&rows[i].Request); err != nil {
+			rows[i].Error = err.Error()
+		}
+	}
+
+	report := c.service.ImportReservations(ctx.Request.Context(), rows, dryRun)
+
+	status := http.StatusOK
+	if !dryRun && report.Succeeded > 0 {
+		status = http.StatusCreated
+	}
+	ctx.JSON(status, report)
+}
+
+// ExportReservations handles GET /api/admin/reservations/export?format=csv|ndjson plus the listing filters
+func (c *ReservationController) ExportReservations(ctx *gin.Context) {
+	format := ctx.DefaultQuery("format", transfer.FormatCSV)
+	if format != transfer.FormatCSV && format != transfer.FormatNDJSON {
+		ctx.Error(domain.NewValidationError("format", "must be csv or ndjson"))
+		return
+	}
+
+	filter, err := parseReservationFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	filename := fmt.Sprintf("reservations-%s.%s", time.Now().Format("20060102-150405"), format)
+	ctx.Header("Content-Type", transfer.ContentType(format))
+	ctx.Header("Content-Disposition", `attachment; filename="`+filename+`"`)
+	ctx.Status(http.StatusOK)
+
+	writer, err := transfer.NewWriter(ctx.Writer, format)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	written := 0
+	err = c.service.ExportReservations(ctx.Request.Context(), filter, func(r domain.Reservation) error {
+		if err := writer.Write(r); err != nil {
+			return err
+		}
+		written++
+		if written%exportFlushEvery == 0 {
+			if err := writer.Flush(); err != nil {
+				return err
+			}
+			ctx.Writer.Flush()
+		}
+		return nil
+	})
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err != nil {
+		// Headers are already sent: the truncated body is all the client gets
+		log.Printf("Export aborted after %d rows: %v", written, err)
+	}
+}
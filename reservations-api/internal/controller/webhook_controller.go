@@ -0,0 +1,78 @@
+package controller
+
+import (
+	"net/http"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+type WebhookController struct {
+	service service.WebhookService
+}
+
+func NewWebhookController(service service.WebhookService) *WebhookController {
+	return &WebhookController{service: service}
+}
+
+// CreateWebhook handles POST /api/admin/webhooks
+// The response is the only time the signing secret is returned
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	var req domain.CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(bindingError(err))
+		return
+	}
+
+	webhook, err := c.service.CreateSubscription(ctx.Request.Context(), req)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusCreated, webhook)
+}
+
+// ListWebhooks handles GET /api/admin/webhooks
+func (c *WebhookController) ListWebhooks(ctx *gin.Context) {
+	webhooks, err := c.service.ListSubscriptions(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, webhooks)
+}
+
+// DeleteWebhook handles DELETE /api/admin/webhooks/:id
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	if err := c.service.DeleteSubscription(ctx.Request.Context(), ctx.Param("id")); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.Status(http.StatusNoContent)
+}
+
+// ListDeliveries handles GET /api/admin/webhooks/:id/deliveries
+func (c *WebhookController) ListDeliveries(ctx *gin.Context) {
+	deliveries, err := c.service.ListDeliveries(ctx.Request.Context(), ctx.Param("id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusOK, deliveries)
+}
+
+// ReplayDelivery handles POST /api/admin/webhooks/:id/deliveries/:delivery_id/replay
+func (c *WebhookController) ReplayDelivery(ctx *gin.Context) {
+	delivery, err := c.service.ReplayDelivery(ctx.Request.Context(), ctx.Param("id"), ctx.Param("delivery_id"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	ctx.JSON(http.StatusAccepted, delivery)
+}
@@ -0,0 +1,209 @@
+package domain
+
+import (
+	"strconv"
+	"time"
+)
+
+// Reporting periods for covers
+const (
+	PeriodDay   = "day"
+	PeriodWeek  = "week"
+	PeriodMonth = "month"
+)
+
+// LeadTimeBucketsHours are the lower bounds (in hours) of the lead-time distribution buckets
+var LeadTimeBucketsHours = []int{0, 24, 72, 168, 336, 720}
+
+// AnalyticsRange is the reporting window [From, To)
+type AnalyticsRange struct {
+	From     time.Time `json:"from"`
+	To       time.Time `json:"to"`
+	MealType string    `json:"meal_type,omitempty"`
+}
+
+// Days returns the number of calendar days covered by the range
+func (r AnalyticsRange) Days() int {
+	days := int(r.To.Sub(r.From).Hours() / 24)
+	if days < 1 {
+		return 1
+	}
+	return days
+}
+
+// CoversBucket holds the covers (seated guests) booked in one period
+type CoversBucket struct {
+	Period       string `bson:"_id" json:"period"`
+	Reservations int    `bson:"reservations" json:"reservations"`
+	Covers       int    `bson:"covers" json:"covers"`
+}
+
+// CoversReport is the covers per day, week or month
+type CoversReport struct {
+	Range   AnalyticsRange `json:"range"`
+	Period  string         `json:"period"`
+	Buckets []CoversBucket `json:"buckets"`
+}
+
+// MealTypeUsage aggregates booked tables and covers for a meal type
+type MealTypeUsage struct {
+	MealType     string `bson:"_id" json:"meal_type"`
+	TablesBooked int    `bson:"tables_booked" json:"tables_booked"`
+	Covers       int    `bson:"covers" json:"covers"`
+}
+
+// TableUtilisation compares bookings with the table catalog capacity
+type TableUtilisation struct {
+	MealType         string  `json:"meal_type"`
+	TablesBooked     int     `json:"tables_booked"`
+	TableSlots       int     `json:"table_slots"`
+	TableUtilisation float64 `json:"table_utilisation"`
+	Covers           int     `json:"covers"`
+	SeatCapacity     int     `json:"seat_capacity"`
+	SeatUtilisation  float64 `json:"seat_utilisation"`
+	AveragePartySize float64 `json:"average_party_size"`
+}
+
+// UtilisationReport is the table utilisation per meal type
+type UtilisationReport struct {
+	Range     AnalyticsRange     `json:"range"`
+	MealTypes []TableUtilisation `json:"meal_types"`
+}
+
+// MealTypeRevenue aggregates revenue and discounts for a meal type. The repository
+// returns minor units; the report is in the major unit of RevenueReport.Currency.
+type MealTypeRevenue struct {
+	MealType        string  `bson:"_id" json:"meal_type"`
+	Reservations    int     `bson:"reservations" json:"reservations"`
+	Revenue         float64 `bson:"revenue" json:"revenue"`
+	CancellationFee float64 `bson:"cancellation_fees" json:"cancellation_fees"`
+	AverageDiscount float64 `bson:"average_discount" json:"average_discount"`
+	AverageTicket   float64 `bson:"average_ticket" json:"average_ticket"`
+}
+
+// RevenueReport is the revenue per meal type
+type RevenueReport struct {
+	Range     AnalyticsRange    `json:"range"`
+	Currency  string            `json:"currency"`
+	MealTypes []MealTypeRevenue `json:"meal_types"`
+}
+
+// StatusCounts holds how many reservations ended in each outcome
+type StatusCounts struct {
+	Total     int `bson:"total" json:"total"`
+	Cancelled int `bson:"cancelled" json:"cancelled"`
+	NoShow    int `bson:"no_show" json:"no_show"`
+	Completed int `bson:"completed" json:"completed"`
+}
+
+// StatusTotal is how many reservations are in a status
+type StatusTotal struct {
+	Status       string `bson:"_id"`
+	Reservations int    `bson:"reservations"`
+}
+
+// CountStatuses folds the per-status totals into the outcomes the rates are built on
+func CountStatuses(totals []StatusTotal) StatusCounts {
+	var counts StatusCounts
+	for _, t := range totals {
+		counts.Total += t.Reservations
+		switch t.Status {
+		case StatusCancelled:
+			counts.Cancelled += t.Reservations
+		case StatusNoShow:
+			counts.NoShow += t.Reservations
+		case StatusCompleted:
+			counts.Completed += t.Reservations
+		}
+	}
+	return counts
+}
+
+// RatesReport holds the cancellation and no-show rates
+type RatesReport struct {
+	Range            AnalyticsRange `json:"range"`
+	Counts           StatusCounts   `json:"counts"`
+	CancellationRate float64        `json:"cancellation_rate"`
+	NoShowRate       float64        `json:"no_show_rate"`
+}
+
+// LeadTimeBucket counts reservations booked a given number of hours in advance
+type LeadTimeBucket struct {
+	FromHours    int `bson:"_id" json:"from_hours"`
+	ToHours      int `bson:"-" json:"to_hours,omitempty"`
+	Reservations int `bson:"reservations" json:"reservations"`
+}
+
+// LeadTimeReport is the distribution of time between booking and the reservation
+type LeadTimeReport struct {
+	Range        AnalyticsRange   `json:"range"`
+	Buckets      []LeadTimeBucket `json:"buckets"`
+	AverageHours float64          `json:"average_hours"`
+}
+
+// Tabular is implemented by reports that can be exported as CSV
+type Tabular interface {
+	Table() (header []string, rows [][]string)
+}
+
+// Table implements Tabular
+func (r CoversReport) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Buckets))
+	for _, b := range r.Buckets {
+		rows = append(rows, []string{b.Period, itoa(b.Reservations), itoa(b.Covers)})
+	}
+	return []string{"period", "reservations", "covers"}, rows
+}
+
+// Table implements Tabular
+func (r UtilisationReport) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.MealTypes))
+	for _, u := range r.MealTypes {
+		rows = append(rows, []string{
+			u.MealType, itoa(u.TablesBooked), itoa(u.TableSlots), ftoa(u.TableUtilisation),
+			itoa(u.Covers), itoa(u.SeatCapacity), ftoa(u.SeatUtilisation), ftoa(u.AveragePartySize),
+		})
+	}
+	return []string{
+		"meal_type", "tables_booked", "table_slots", "table_utilisation",
+		"covers", "seat_capacity", "seat_utilisation", "average_party_size",
+	}, rows
+}
+
+// Table implements Tabular
+func (r RevenueReport) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.MealTypes))
+	for _, m := range r.MealTypes {
+		rows = append(rows, []string{
+			m.MealType, itoa(m.Reservations), ftoa(m.Revenue), ftoa(m.CancellationFee),
+			ftoa(m.AverageDiscount), ftoa(m.AverageTicket),
+		})
+	}
+	return []string{"meal_type", "reservations", "revenue", "cancellation_fees", "average_discount", "average_ticket"}, rows
+}
+
+// Table implements Tabular
+func (r RatesReport) Table() ([]string, [][]string) {
+	return []string{"total", "cancelled", "no_show", "completed", "cancellation_rate", "no_show_rate"},
+		[][]string{{
+			itoa(r.Counts.Total), itoa(r.Counts.Cancelled), itoa(r.Counts.NoShow), itoa(r.Counts.Completed),
+			ftoa(r.CancellationRate), ftoa(r.NoShowRate),
+		}}
+}
+
+// Table implements Tabular
+func (r LeadTimeReport) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Buckets))
+	for _, b := range r.Buckets {
+		to := ""
+		if b.ToHours > 0 {
+			to = itoa(b.ToHours)
+		}
+		rows = append(rows, []string{itoa(b.FromHours), to, itoa(b.Reservations)})
+	}
+	return []string{"from_hours", "to_hours", "reservations"}, rows
+}
+
+func itoa(n int) string { return strconv.Itoa(n) }
+
+func ftoa(f float64) string { return strconv.FormatFloat(f, 'f', 4, 64) }
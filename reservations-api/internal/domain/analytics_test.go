@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestCountStatuses(t *testing.T) {
+	now := time.Now()
+	past := now.Add(-3 * time.Hour)
+
+	// A guest that never came is marked once the booked time has passed
+	noShow := &Reservation{Status: StatusConfirmed, DateTime: past}
+	if err := noShow.SetStatus(StatusNoShow, now); err != nil {
+		t.Fatalf("SetStatus(no_show) error = %v", err)
+	}
+	completed := &Reservation{Status: StatusSeated, DateTime: past}
+	if err := completed.SetStatus(StatusCompleted, now); err != nil {
+		t.Fatalf("SetStatus(completed) error = %v", err)
+	}
+	reservations := []*Reservation{
+		noShow, completed,
+		{Status: StatusCancelled}, {Status: StatusConfirmed}, {Status: StatusPending},
+	}
+
+	// Grouped by status, as the $group stage does
+	byStatus := map[string]int{}
+	for _, r := range reservations {
+		byStatus[r.Status]++
+	}
+	totals := []StatusTotal{}
+	for status, n := range byStatus {
+		totals = append(totals, StatusTotal{Status: status, Reservations: n})
+	}
+
+	want := StatusCounts{Total: 5, Cancelled: 1, NoShow: 1, Completed: 1}
+	if got := CountStatuses(totals); got != want {
+		t.Errorf("CountStatuses() = %+v, want %+v", got, want)
+	}
+	if got := CountStatuses(nil); got != (StatusCounts{}) {
+		t.Errorf("CountStatuses(nil) = %+v, want zero", got)
+	}
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// ArchiveReport summarises one retention run
+type ArchiveReport struct {
+	// Reservations booked before Cutoff were moved to the archive
+	Cutoff   time.Time `json:"cutoff"`
+	Archived int       `json:"archived"`
+}
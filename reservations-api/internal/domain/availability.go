@@ -0,0 +1,111 @@
+package domain
+
+import "time"
+
+// MaxAvailabilityDays bounds the range of an availability calendar request
+const MaxAvailabilityDays = 62
+
+// MealTypes lists every meal type in display order
+var MealTypes = []string{MealTypeBreakfast, MealTypeLunch, MealTypeDinner, MealTypeEvent}
+
+// MealOrder returns the position of a meal type in service order; unknown ones go last
+func MealOrder(mealType string) int {
+	for i, m := range MealTypes {
+		if m == mealType {
+			return i
+		}
+	}
+	return len(MealTypes)
+}
+
+// ReservedSlot lists the tables taken on one day for one meal type
+type ReservedSlot struct {
+	Date     string `bson:"date"`
+	MealType string `bson:"meal_type"`
+	Tables   []int  `bson:"tables"`
+}
+
+// MealAvailability counts the free tables that fit the party for one meal
+type MealAvailability struct {
+	MealType    string `json:"meal_type"`
+	TotalTables int    `json:"total_tables"`
+	FreeTables  int    `json:"free_tables"`
+}
+
+// DayAvailability holds the availability of every requested meal on one day
+type DayAvailability struct {
+	Date  string             `json:"date"`
+	Meals []MealAvailability `json:"meals"`
+}
+
+// AvailabilityCalendar is the per-day, per-meal availability over [From, To]
+type AvailabilityCalendar struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	MealType string `json:"meal_type,omitempty"`
+	Guests   int    `json:"guests"`
+	// Accessible is set when only wheelchair accessible tables were counted
+	Accessible bool              `json:"accessible,omitempty"`
+	Days       []DayAvailability `json:"days"`
+}
+
+// AvailabilityQuery is a validated availability calendar request; To is exclusive
+type AvailabilityQuery struct {
+	From     time.Time
+	To       time.Time
+	MealType string
+	Guests   int
+	// Accessible only counts the wheelchair accessible tables
+	Accessible bool
+}
+
+// NewAvailabilityCalendar fills every day and meal of the query, counting as free the
+// tables large enough for the party (and accessible, if asked) that do not appear in the
+// reserved slots
+func NewAvailabilityCalendar(q AvailabilityQuery, reserved []ReservedSlot) *AvailabilityCalendar {
+	taken := make(map[string]map[int]bool, len(reserved))
+	for _, slot := range reserved {
+		key := slot.Date + "|" + slot.MealType
+		if taken[key] == nil {
+			taken[key] = make(map[int]bool, len(slot.Tables))
+		}
+		for _, n := range slot.Tables {
+			taken[key][n] = true
+		}
+	}
+
+	mealTypes := MealTypes
+	if q.MealType != "" {
+		mealTypes = []string{q.MealType}
+	}
+
+	cal := &AvailabilityCalendar{
+		From:       q.From.Format("2006-01-02"),
+		To:         q.To.AddDate(0, 0, -1).Format("2006-01-02"),
+		MealType:   q.MealType,
+		Guests:     q.Guests,
+		Accessible: q.Accessible,
+		Days:       []DayAvailability{},
+	}
+
+	for day := q.From; day.Before(q.To); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		meals := make([]MealAvailability, 0, len(mealTypes))
+		for _, mealType := range mealTypes {
+			meal := MealAvailability{MealType: mealType}
+			for _, table := range GetTablesForMealType(mealType) {
+				if table.Capacity < q.Guests || (q.Accessible && !table.Accessible) {
+					continue
+				}
+				meal.TotalTables++
+				if !taken[date+"|"+mealType][table.TableNumber] {
+					meal.FreeTables++
+				}
+			}
+			meals = append(meals, meal)
+		}
+		cal.Days = append(cal.Days, DayAvailability{Date: date, Meals: meals})
+	}
+
+	return cal
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewAvailabilityCalendar(t *testing.T) {
+	day := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	reserved := []ReservedSlot{
+		{Date: "2030-03-01", MealType: MealTypeDinner, Tables: []int{1, 9}},
+		{Date: "2030-03-02", MealType: MealTypeDinner, Tables: []int{6}},
+		{Date: "2030-03-02", MealType: MealTypeDinner, Tables: []int{7}}, // same slot, listed twice
+		{Date: "2030-03-01", MealType: MealTypeLunch, Tables: []int{1}},
+	}
+
+	t.Run("single meal", func(t *testing.T) {
+		cal := NewAvailabilityCalendar(AvailabilityQuery{From: day, To: day.AddDate(0, 0, 2), MealType: MealTypeDinner, Guests: 1}, reserved)
+		if cal.From != "2030-03-01" || cal.To != "2030-03-02" {
+			t.Errorf("range = %s..%s, want the inclusive 2030-03-01..2030-03-02", cal.From, cal.To)
+		}
+		if len(cal.Days) != 2 {
+			t.Fatalf("got %d days, want 2", len(cal.Days))
+		}
+		for i, want := range []int{8, 8} {
+			meals := cal.Days[i].Meals
+			if len(meals) != 1 || meals[0].MealType != MealTypeDinner || meals[0].TotalTables != 10 || meals[0].FreeTables != want {
+				t.Errorf("%s meals = %+v, want dinner with %d of 10 free", cal.Days[i].Date, meals, want)
+			}
+		}
+	})
+
+	t.Run("every meal in service order", func(t *testing.T) {
+		cal := NewAvailabilityCalendar(AvailabilityQuery{From: day, To: day.AddDate(0, 0, 1), Guests: 1}, reserved)
+		meals := cal.Days[0].Meals
+		if len(meals) != len(MealTypes) {
+			t.Fatalf("meals = %+v, want all %d", meals, len(MealTypes))
+		}
+		for i, meal := range meals {
+			if meal.MealType != MealTypes[i] {
+				t.Errorf("meal %d = %s, want %s", i, meal.MealType, MealTypes[i])
+			}
+		}
+		if meals[1].FreeTables != 9 {
+			t.Errorf("lunch free tables = %d, want 9", meals[1].FreeTables)
+		}
+	})
+
+	t.Run("only tables that fit the party", func(t *testing.T) {
+		// Dinner tables of 6 or more: 6, 7, 8, 9 and 10; 9 is taken
+		cal := NewAvailabilityCalendar(AvailabilityQuery{From: day, To: day.AddDate(0, 0, 1), MealType: MealTypeDinner, Guests: 5}, reserved)
+		if meal := cal.Days[0].Meals[0]; meal.TotalTables != 5 || meal.FreeTables != 4 {
+			t.Errorf("meal = %+v, want 4 of 5 free", meal)
+		}
+	})
+
+	t.Run("only accessible tables", func(t *testing.T) {
+		// Accessible dinner tables: 1, 3, 6 and 9; 1 and 9 are taken
+		cal := NewAvailabilityCalendar(AvailabilityQuery{From: day, To: day.AddDate(0, 0, 1), MealType: MealTypeDinner, Guests: 1, Accessible: true}, reserved)
+		if meal := cal.Days[0].Meals[0]; meal.TotalTables != 4 || meal.FreeTables != 2 {
+			t.Errorf("meal = %+v, want 2 of 4 free", meal)
+		}
+	})
+}
@@ -0,0 +1,205 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Bulk actions an admin can run over the reservations of a service
+const (
+	BulkActionCancel = "cancel"
+	BulkActionMove   = "move"
+)
+
+// Bulk job statuses
+const (
+	BulkJobRunning   = "running"
+	BulkJobCompleted = "completed"
+)
+
+// Outcomes of a bulk job for each reservation
+const (
+	BulkResultCancelled = "cancelled"
+	BulkResultMoved     = "moved"
+	BulkResultFailed    = "failed"
+)
+
+// BulkSelector picks the reservations of a bulk job: those booked on Date, optionally
+// only for one meal type or table
+type BulkSelector struct {
+	Date        string `bson:"date" json:"date" binding:"required"` // YYYY-MM-DD
+	MealType    string `bson:"meal_type,omitempty" json:"meal_type,omitempty" binding:"omitempty,oneof=breakfast lunch dinner event"`
+	TableNumber int    `bson:"table_number,omitempty" json:"table_number,omitempty" binding:"omitempty,min=1"`
+}
+
+// Filter returns the listing filter of the selected day, meal type and table
+func (s BulkSelector) Filter() (ReservationFilter, error) {
+	day, err := time.Parse("2006-01-02", s.Date)
+	if err != nil {
+		return ReservationFilter{}, NewValidationError("select.date", "must be YYYY-MM-DD")
+	}
+	return ReservationFilter{MealType: s.MealType, TableNumber: s.TableNumber, From: day, To: day.AddDate(0, 0, 1)}, nil
+}
+
+// BulkMoveTarget is where a move sends the reservations: another day, keeping the time
+// booked, another table, or both
+type BulkMoveTarget struct {
+	Date        string `bson:"date,omitempty" json:"date,omitempty"` // YYYY-MM-DD
+	TableNumber int    `bson:"table_number,omitempty" json:"table_number,omitempty" binding:"omitempty,min=1"`
+}
+
+// BulkJobRequest DTO for cancelling or moving the reservations of a service
+type BulkJobRequest struct {
+	Action string       `json:"action" binding:"required,oneof=cancel move"`
+	Select BulkSelector `json:"select"`
+	// Reason is recorded on cancelled reservations and sent to the guests with the event
+	Reason string          `json:"reason,omitempty" binding:"max=500"`
+	MoveTo *BulkMoveTarget `json:"move_to,omitempty"`
+}
+
+// BulkResult reports what a bulk job did to one reservation
+type BulkResult struct {
+	ReservationID string `bson:"reservation_id" json:"reservation_id"`
+	Status        string `bson:"status" json:"status"`
+	// Where a moved reservation is booked now
+	TableNumber int        `bson:"table_number,omitempty" json:"table_number,omitempty"`
+	DateTime    *time.Time `bson:"date_time,omitempty" json:"date_time,omitempty"`
+	Error       string     `bson:"error,omitempty" json:"error,omitempty"`
+}
+
+// BulkJob is a tracked bulk run with the outcome for each reservation it selected
+type BulkJob struct {
+	ID        primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Action    string             `bson:"action" json:"action"`
+	Select    BulkSelector       `bson:"select" json:"select"`
+	Reason    string             `bson:"reason,omitempty" json:"reason,omitempty"`
+	MoveTo    *BulkMoveTarget    `bson:"move_to,omitempty" json:"move_to,omitempty"`
+	Status    string             `bson:"status" json:"status"`
+	CreatedBy string             `bson:"created_by" json:"created_by"`
+	// Total reservations selected, and how many of them the action succeeded or failed for
+	Total       int          `bson:"total" json:"total"`
+	Succeeded   int          `bson:"succeeded" json:"succeeded"`
+	Failed      int          `bson:"failed" json:"failed"`
+	Results     []BulkResult `bson:"results" json:"results"`
+	CreatedAt   time.Time    `bson:"created_at" json:"created_at"`
+	CompletedAt *time.Time   `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+}
+
+// NewBulkJob validates a bulk request and returns its job, not started yet
+func NewBulkJob(req BulkJobRequest, createdBy string, now time.Time) (*BulkJob, error) {
+	verr := &ValidationError{}
+	if _, err := time.Parse("2006-01-02", req.Select.Date); err != nil {
+		verr.Add("select.date", "must be YYYY-MM-DD")
+	}
+	switch req.Action {
+	case BulkActionCancel:
+		if req.Reason == "" {
+			verr.Add("reason", "is required to cancel reservations")
+		}
+		if req.MoveTo != nil {
+			verr.Add("move_to", "only applies to moves")
+		}
+	case BulkActionMove:
+		switch {
+		case req.MoveTo == nil || (req.MoveTo.Date == "" && req.MoveTo.TableNumber == 0):
+			verr.Add("move_to", "needs a date, a table or both")
+		case req.MoveTo.Date != "":
+			if _, err := time.Parse("2006-01-02", req.MoveTo.Date); err != nil {
+				verr.Add("move_to.date", "must be YYYY-MM-DD")
+			}
+		}
+	default:
+		verr.Add("action", "must be cancel or move")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	return &BulkJob{
+		Action:    req.Action,
+		Select:    req.Select,
+		Reason:    req.Reason,
+		MoveTo:    req.MoveTo,
+		Status:    BulkJobRunning,
+		CreatedBy: createdBy,
+		Results:   []BulkResult{},
+		CreatedAt: now,
+	}, nil
+}
+
+// BulkSelectable reports whether a bulk job acts on the reservation: only the guests
+// still expected, not those seated, gone or already cancelled
+func BulkSelectable(r *Reservation) bool {
+	return !r.WalkIn && (r.Status == StatusPending || r.Status == StatusConfirmed)
+}
+
+// Record adds the outcome for one reservation to the job
+func (j *BulkJob) Record(result BulkResult) {
+	if result.Status == BulkResultFailed {
+		j.Failed++
+	} else {
+		j.Succeeded++
+	}
+	j.Results = append(j.Results, result)
+}
+
+// Complete marks the job as done
+func (j *BulkJob) Complete(now time.Time) {
+	j.Status = BulkJobCompleted
+	j.CompletedAt = &now
+}
+
+// CancelByRestaurant cancels a reservation the restaurant can no longer serve. No
+// cancellation fee is charged, as the guest did not cancel.
+func (r *Reservation) CancelByRestaurant(reason string, now time.Time) error {
+	if !BulkSelectable(r) {
+		return ConflictError("%s reservations cannot be cancelled by the restaurant", r.Status)
+	}
+
+	r.Status = StatusCancelled
+	r.CancellationFee = nil
+	r.CancellationReason = reason
+	r.CancelledAt = &now
+	r.UpdatedAt = now
+	return nil
+}
+
+// MoveTo rebooks the reservation on the target day, at the same time, and table. The
+// time is kept on the restaurant's clock in loc, also across a daylight saving change.
+// The target table must exist for the meal type and fit the party; whether it is free
+// is up to the caller.
+func (r *Reservation) MoveTo(target BulkMoveTarget, loc *time.Location, now time.Time) error {
+	if !BulkSelectable(r) {
+		return ConflictError("%s reservations cannot be moved", r.Status)
+	}
+
+	dateTime, table := r.DateTime, r.TableNumber
+	if target.Date != "" {
+		day, err := time.Parse("2006-01-02", target.Date)
+		if err != nil {
+			return NewValidationError("move_to.date", "must be YYYY-MM-DD")
+		}
+		at := r.DateTime.In(loc)
+		dateTime = time.Date(day.Year(), day.Month(), day.Day(), at.Hour(), at.Minute(), at.Second(), 0, loc).UTC()
+	}
+	if target.TableNumber != 0 {
+		table = target.TableNumber
+	}
+	if dateTime.Equal(r.DateTime) && table == r.TableNumber {
+		return ConflictError("reservation is already booked there")
+	}
+
+	capacity, ok := GetTableCapacity(table, r.MealType)
+	if !ok {
+		return NewValidationError("move_to.table_number", fmt.Sprintf("table %d does not exist for %s", table, r.MealType))
+	}
+	if r.Guests > capacity {
+		return ConflictError("table %d seats %d guests, the party is %d", table, capacity, r.Guests)
+	}
+
+	r.DateTime, r.TableNumber = dateTime, table
+	r.UpdatedAt = now
+	return r.CheckTable()
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewBulkJob(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	dinner := BulkSelector{Date: "2030-03-01", MealType: MealTypeDinner}
+
+	tests := []struct {
+		name string
+		req  BulkJobRequest
+		ok   bool
+	}{
+		{"cancel with a reason", BulkJobRequest{Action: BulkActionCancel, Select: dinner, Reason: "kitchen flooded"}, true},
+		{"cancel without a reason", BulkJobRequest{Action: BulkActionCancel, Select: dinner}, false},
+		{"cancel with a target", BulkJobRequest{Action: BulkActionCancel, Select: dinner, Reason: "closed", MoveTo: &BulkMoveTarget{TableNumber: 2}}, false},
+		{"move to another day", BulkJobRequest{Action: BulkActionMove, Select: dinner, MoveTo: &BulkMoveTarget{Date: "2030-03-02"}}, true},
+		{"move nowhere", BulkJobRequest{Action: BulkActionMove, Select: dinner, MoveTo: &BulkMoveTarget{}}, false},
+		{"move to a bad date", BulkJobRequest{Action: BulkActionMove, Select: dinner, MoveTo: &BulkMoveTarget{Date: "02/03/2030"}}, false},
+		{"bad selected date", BulkJobRequest{Action: BulkActionCancel, Select: BulkSelector{Date: "tomorrow"}, Reason: "closed"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			job, err := NewBulkJob(tt.req, "admin", now)
+			if !tt.ok {
+				if !errors.Is(err, ErrValidation) {
+					t.Fatalf("NewBulkJob() error = %v, want validation error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NewBulkJob() error = %v", err)
+			}
+			if job.Status != BulkJobRunning || job.CreatedBy != "admin" {
+				t.Errorf("job = %+v, want running for admin", job)
+			}
+		})
+	}
+}
+
+func TestCancelByRestaurant(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	fee := NewMoney(1000, "ARS")
+
+	r := &Reservation{Status: StatusConfirmed, DateTime: now.Add(8 * time.Hour), CancellationFee: &fee}
+	if err := r.CancelByRestaurant("kitchen flooded", now); err != nil {
+		t.Fatalf("CancelByRestaurant() error = %v", err)
+	}
+	if r.Status != StatusCancelled || r.CancellationFee != nil || r.CancellationReason != "kitchen flooded" {
+		t.Errorf("reservation = %+v, want cancelled free of charge with the reason", r)
+	}
+
+	if err := (&Reservation{Status: StatusSeated}).CancelByRestaurant("closed", now); !errors.Is(err, ErrConflict) {
+		t.Errorf("CancelByRestaurant() of a seated reservation error = %v, want conflict", err)
+	}
+}
+
+func TestMoveTo(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	booked := time.Date(2030, time.March, 1, 21, 30, 0, 0, time.UTC)
+	reservation := func() *Reservation {
+		return &Reservation{Status: StatusConfirmed, TableNumber: 1, Guests: 2, MealType: MealTypeDinner, DateTime: booked}
+	}
+
+	r := reservation()
+	if err := r.MoveTo(BulkMoveTarget{Date: "2030-03-02", TableNumber: 3}, time.UTC, now); err != nil {
+		t.Fatalf("MoveTo() error = %v", err)
+	}
+	if want := booked.AddDate(0, 0, 1); !r.DateTime.Equal(want) || r.TableNumber != 3 {
+		t.Errorf("moved to table %d at %v, want table 3 at %v", r.TableNumber, r.DateTime, want)
+	}
+
+	// 21:30 in Madrid stays 21:30 when the clocks go forward on 2030-03-31
+	madrid, err := time.LoadLocation("Europe/Madrid")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r = reservation()
+	r.DateTime = time.Date(2030, time.March, 30, 21, 30, 0, 0, madrid).UTC()
+	if err := r.MoveTo(BulkMoveTarget{Date: "2030-03-31"}, madrid, now); err != nil {
+		t.Fatalf("MoveTo() error = %v", err)
+	}
+	if want := time.Date(2030, time.March, 31, 21, 30, 0, 0, madrid); !r.DateTime.Equal(want) || r.DateTime.Location() != time.UTC {
+		t.Errorf("moved to %v, want %v in UTC", r.DateTime, want.UTC())
+	}
+
+	tests := []struct {
+		name   string
+		target BulkMoveTarget
+		want   error
+	}{
+		{"same place", BulkMoveTarget{Date: "2030-03-01", TableNumber: 1}, ErrConflict},
+		{"unknown table", BulkMoveTarget{TableNumber: 99}, ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := reservation().MoveTo(tt.target, time.UTC, now); !errors.Is(err, tt.want) {
+				t.Errorf("MoveTo() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
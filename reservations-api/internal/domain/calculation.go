@@ -1,190 +1,586 @@
 package domain
 
 import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 )
 
-// CalculationResult holds the result of concurrent calculations
+// Built-in calculation stage names
+const (
+	StageAvailability = "availability"
+	StagePrice        = "price"
+	StageDiscount     = "discount"
+	StageLoyalty      = "loyalty"
+	StagePromo        = "promo"
+	StagePreOrder     = "pre_order"
+)
+
+// rulesRevision numbers the rules hard-coded in the built-in stages (per-person prices,
+// time-based and loyalty discounts). Bump it whenever one of them changes, so reservations
+// priced under the old rules can be told apart.
+const rulesRevision = 1
+
+// Price triggers: what recalculated a reservation's price
+const (
+	PriceTriggerCreate   = "create"
+	PriceTriggerUpdate   = "update"
+	PriceTriggerConfirm  = "confirm"
+	PriceTriggerPreOrder = "pre_order"
+)
+
+// CalculationInput is the reservation data every stage reads
+type CalculationInput struct {
+	TableNumber int
+	Guests      int
+	DateTime    time.Time
+	MealType    string
+	OwnerID     string
+	PreOrder    []PreOrderLine
+}
+
+// CalculationInput returns the data the calculation pipeline needs from the reservation
+func (r *Reservation) CalculationInput() CalculationInput {
+	return CalculationInput{
+		TableNumber: r.TableNumber,
+		Guests:      r.Guests,
+		DateTime:    r.DateTime,
+		MealType:    r.MealType,
+		OwnerID:     r.OwnerID,
+		PreOrder:    r.PreOrder,
+	}
+}
+
+// CalculationResult holds the aggregated result of the calculation stages
 type CalculationResult struct {
-	Available    bool
-	BasePrice    float64
-	Discount     float64
-	FinalPrice   float64
+	Available bool
+	BasePrice Money
+	Discount  Money
+	Discounts []DiscountLine
+	// Items are the pre-ordered menu items, which discounts do not apply to
+	Items      []PreOrderLine
+	ItemsTotal Money
+	// Subtotal is the discounted price plus the items, before the charges
+	Subtotal     Money
+	Charges      []ChargeLine
+	FinalPrice   Money
+	Rounding     RoundingMode
 	Restrictions []string
+	// CalculatedAt is when the pipeline ran, RulesVersion identifies the rules it applied
+	CalculatedAt time.Time
+	RulesVersion string
 }
 
-// PartialResult represents a single calculation result
-type PartialResult struct {
-	Type string
-	Data interface{}
+// Breakdown returns the price as persisted on the reservation
+func (r *CalculationResult) Breakdown() *PriceBreakdown {
+	return &PriceBreakdown{
+		Base:         r.BasePrice,
+		Discounts:    r.Discounts,
+		Discount:     r.Discount,
+		Items:        r.Items,
+		ItemsTotal:   r.ItemsTotal,
+		Subtotal:     r.Subtotal,
+		Charges:      r.Charges,
+		Total:        r.FinalPrice,
+		Rounding:     r.Rounding,
+		CalculatedAt: r.CalculatedAt,
+		RulesVersion: r.RulesVersion,
+	}
 }
 
-// AvailabilityResult represents table availability check result
-type AvailabilityResult struct {
-	Available bool
-	Reason    string
+// ApplyPrice sets the calculated price on the reservation. trigger says what recalculated
+// it; when a recalculation changes the total, the breakdown keeps the total it replaced.
+func (r *Reservation) ApplyPrice(result *CalculationResult, trigger string) {
+	breakdown := result.Breakdown()
+	breakdown.Trigger = trigger
+	if r.Price != nil && r.TotalPrice != result.FinalPrice {
+		previous := r.TotalPrice
+		breakdown.PreviousTotal = &previous
+	}
+	r.Price = breakdown
+	r.TotalPrice = result.FinalPrice
 }
 
-// PriceResult represents price calculation result
-type PriceResult struct {
-	BasePrice float64
+// DiscountLine is one discount applied to the base price
+type DiscountLine struct {
+	// Rule is the calculation stage that granted the discount
+	Rule        string  `bson:"rule" json:"rule"`
+	Code        string  `bson:"code" json:"code"`
+	Description string  `bson:"description" json:"description"`
+	Percent     float64 `bson:"percent" json:"percent"`
+	Amount      Money   `bson:"amount" json:"amount"`
 }
 
-// DiscountResult represents discount calculation result
-type DiscountResult struct {
-	DiscountPercent float64
-	DiscountAmount  float64
+// StageOutput is the contribution of a stage to the result. Stages only fill
+// what they compute; the pipeline merges the outputs.
+type StageOutput struct {
+	// Restrictions make the reservation unavailable
+	Restrictions []string
+	// BasePrice is set by the stage that prices the reservation, in minor units of the
+	// pricing currency
+	BasePrice *int64
+	// Discounts are percentages of the base price; amounts are filled in on aggregation
+	Discounts []DiscountLine
+	// Items are priced line items added after the discounts
+	Items []PreOrderLine
 }
 
-// CalculateReservationConcurrent performs concurrent calculations for a reservation
-// Returns: availability, base price, discount, and final price
-func CalculateReservationConcurrent(tableNumber int, guests int, dateTime time.Time, mealType string, ownerID string) (*CalculationResult, error) {
-	results := make(chan PartialResult, 3)
-	var wg sync.WaitGroup
+// StageResults holds the outputs of the stages that already ran, by stage name
+type StageResults map[string]StageOutput
 
-	// Goroutine 1: Check table availability
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		availability := checkTableAvailability(tableNumber, dateTime)
-		results <- PartialResult{Type: "availability", Data: availability}
-	}()
-
-	// Goroutine 2: Calculate base price
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		price := calculateBasePrice(guests, mealType)
-		results <- PartialResult{Type: "price", Data: price}
-	}()
-
-	// Goroutine 3: Apply discounts
-	wg.Add(1)
-	go func() {
-		defer wg.Done()
-		discount := calculateDiscount(dateTime, ownerID, mealType)
-		results <- PartialResult{Type: "discount", Data: discount}
-	}()
-
-	// Close channel when all goroutines finish
-	go func() {
-		wg.Wait()
-		close(results)
-	}()
-
-	// Collect results
-	finalResult := &CalculationResult{
-		Available:    true,
-		Restrictions: []string{},
+// CalculationStage is one step of the calculation pipeline
+type CalculationStage interface {
+	Name() string
+	// DependsOn lists the stages whose output Run needs; independent stages run concurrently
+	DependsOn() []string
+	// Run computes the stage output. prior holds the outputs of DependsOn (read-only).
+	Run(ctx context.Context, in CalculationInput, prior StageResults) (StageOutput, error)
+}
+
+// StageError reports the failure of a single stage
+type StageError struct {
+	Stage string
+	Err   error
+}
+
+func (e *StageError) Error() string {
+	return fmt.Sprintf("%s stage: %v", e.Stage, e.Err)
+}
+
+func (e *StageError) Unwrap() error {
+	return e.Err
+}
+
+// CalculationPipeline runs calculation stages in dependency order, running
+// the stages of a same wave concurrently
+type CalculationPipeline struct {
+	// waves groups the stages whose dependencies are satisfied by earlier waves
+	waves   [][]CalculationStage
+	pricing Pricing
+	// version fingerprints the stages and their configuration
+	version string
+}
+
+// NewCalculationPipeline validates the stages (unique names, known and acyclic
+// dependencies) and plans the execution waves
+func NewCalculationPipeline(stages ...CalculationStage) (*CalculationPipeline, error) {
+	byName := make(map[string]CalculationStage, len(stages))
+	for _, stage := range stages {
+		if _, dup := byName[stage.Name()]; dup {
+			return nil, fmt.Errorf("duplicate calculation stage %q", stage.Name())
+		}
+		byName[stage.Name()] = stage
+	}
+	for _, stage := range stages {
+		for _, dep := range stage.DependsOn() {
+			if _, ok := byName[dep]; !ok {
+				return nil, fmt.Errorf("calculation stage %q depends on unknown stage %q", stage.Name(), dep)
+			}
+		}
 	}
-	// Discount is calculated from a percentage after we know base price
-	discountPercent := 0.0
 
-	for partial := range results {
-		switch partial.Type {
-		case "availability":
-			avail := partial.Data.(AvailabilityResult)
-			finalResult.Available = avail.Available
-			if !avail.Available {
-				finalResult.Restrictions = append(finalResult.Restrictions, avail.Reason)
+	done := make(map[string]bool, len(stages))
+	pending := append([]CalculationStage(nil), stages...)
+	pipeline := &CalculationPipeline{pricing: DefaultPricing()}
+	for len(pending) > 0 {
+		var wave, rest []CalculationStage
+		for _, stage := range pending {
+			if dependenciesDone(stage, done) {
+				wave = append(wave, stage)
+			} else {
+				rest = append(rest, stage)
 			}
+		}
+		if len(wave) == 0 {
+			return nil, fmt.Errorf("calculation stages have a dependency cycle")
+		}
+		for _, stage := range wave {
+			done[stage.Name()] = true
+		}
+		pipeline.waves = append(pipeline.waves, wave)
+		pending = rest
+	}
+	pipeline.version = rulesVersion(pipeline.stageOrder(), pipeline.pricing)
 
-		case "price":
-			price := partial.Data.(PriceResult)
-			finalResult.BasePrice = price.BasePrice
+	return pipeline, nil
+}
+
+// rulesVersion is the rules revision followed by a short hash of the stage names and
+// their configuration, e.g. "r1-3f9a0c2b"
+func rulesVersion(config ...any) string {
+	h := sha256.New()
+	for _, c := range config {
+		fmt.Fprintf(h, "%+v\n", c)
+	}
+	return fmt.Sprintf("r%d-%s", rulesRevision, hex.EncodeToString(h.Sum(nil))[:8])
+}
 
-		case "discount":
-			disc := partial.Data.(DiscountResult)
-			discountPercent = disc.DiscountPercent
+// RulesVersion identifies the rules the pipeline applies
+func (p *CalculationPipeline) RulesVersion() string {
+	return p.version
+}
+
+func dependenciesDone(stage CalculationStage, done map[string]bool) bool {
+	for _, dep := range stage.DependsOn() {
+		if !done[dep] {
+			return false
 		}
 	}
+	return true
+}
+
+// DefaultCalculationPipeline returns the built-in stages priced with pricing. promotions may be empty.
+func DefaultCalculationPipeline(promotions []Promotion, pricing Pricing) *CalculationPipeline {
+	pipeline, err := NewCalculationPipeline(
+		AvailabilityStage{},
+		PriceStage{Currency: pricing.Currency},
+		DiscountStage{},
+		LoyaltyStage{},
+		PromoStage{Promotions: promotions},
+		PreOrderStage{Currency: pricing.Currency},
+	)
+	if err != nil {
+		// The built-in stages are independent: this cannot happen
+		panic(err)
+	}
+	pipeline.pricing = pricing
+	pipeline.version = rulesVersion(pipeline.stageOrder(), pricing, promotions)
+	return pipeline
+}
+
+// Run executes every stage and aggregates their outputs. It stops at the first
+// failing wave, returning the stage errors joined, and honours ctx cancellation.
+func (p *CalculationPipeline) Run(ctx context.Context, in CalculationInput) (*CalculationResult, error) {
+	results := make(StageResults)
 
-	// Calculate discount amount from percentage and final price
-	discountAmount := finalResult.BasePrice * discountPercent / 100.0
-	finalResult.Discount = discountAmount
-	finalResult.FinalPrice = finalResult.BasePrice - discountAmount
-	if finalResult.FinalPrice < 0 {
-		finalResult.FinalPrice = 0
+	for _, wave := range p.waves {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		outputs, err := runWave(ctx, wave, in, results)
+		if err != nil {
+			return nil, err
+		}
+		for name, output := range outputs {
+			results[name] = output
+		}
 	}
 
-	return finalResult, nil
+	result := aggregate(p.stageOrder(), results, p.pricing)
+	result.CalculatedAt = time.Now().UTC()
+	result.RulesVersion = p.version
+	return result, nil
 }
 
-// checkTableAvailability simulates checking if a table is available
-func checkTableAvailability(tableNumber int, dateTime time.Time) AvailabilityResult {
-	// Simulate some processing time
-	time.Sleep(50 * time.Millisecond)
+// runWave runs independent stages concurrently. The first failure cancels the others.
+func runWave(ctx context.Context, wave []CalculationStage, in CalculationInput, prior StageResults) (StageResults, error) {
+	if len(wave) == 1 {
+		output, err := runStage(ctx, wave[0], in, prior)
+		if err != nil {
+			return nil, err
+		}
+		return StageResults{wave[0].Name(): output}, nil
+	}
+
+	waveCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
 
-	// Simple logic: tables 1-10 always available, others check time
-	if tableNumber <= 10 {
-		return AvailabilityResult{Available: true}
+	type stageResult struct {
+		name   string
+		output StageOutput
+		err    error
+	}
+	results := make(chan stageResult, len(wave))
+	var wg sync.WaitGroup
+	for _, stage := range wave {
+		wg.Add(1)
+		go func(stage CalculationStage) {
+			defer wg.Done()
+			output, err := runStage(waveCtx, stage, in, prior)
+			if err != nil {
+				cancel()
+			}
+			results <- stageResult{name: stage.Name(), output: output, err: err}
+		}(stage)
+	}
+	wg.Wait()
+	close(results)
+
+	outputs := make(StageResults, len(wave))
+	var errs []error
+	for result := range results {
+		if result.err == nil {
+			outputs[result.name] = result.output
+			continue
+		}
+		// Siblings cancelled because of another stage's failure are not errors of their own
+		if errors.Is(result.err, context.Canceled) && ctx.Err() == nil {
+			continue
+		}
+		errs = append(errs, result.err)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return outputs, nil
+}
+
+// runStage runs a stage, turning its failure into a StageError
+func runStage(ctx context.Context, stage CalculationStage, in CalculationInput, prior StageResults) (StageOutput, error) {
+	output, err := stage.Run(ctx, in, prior)
+	if err != nil {
+		return StageOutput{}, &StageError{Stage: stage.Name(), Err: err}
+	}
+	return output, nil
+}
 
-	// Check if it's a weekend (higher demand)
-	weekday := dateTime.Weekday()
-	if weekday == time.Saturday || weekday == time.Sunday {
-		// Weekend: only available before 6 PM
-		if dateTime.Hour() >= 18 {
-			return AvailabilityResult{Available: false, Reason: "Table not available on weekend evenings"}
+// stageOrder lists the stage names in planning order, so aggregation is deterministic
+func (p *CalculationPipeline) stageOrder() []string {
+	names := []string{}
+	for _, wave := range p.waves {
+		for _, stage := range wave {
+			names = append(names, stage.Name())
 		}
 	}
+	return names
+}
+
+// aggregate merges the stage outputs: restrictions, base price, then discount
+// amounts from their percentages, the items, the subtotal, the charges and the final
+// price. Each discount and charge is rounded to the minor unit on its own.
+func aggregate(order []string, results StageResults, pricing Pricing) *CalculationResult {
+	result := &CalculationResult{
+		BasePrice:    NewMoney(0, pricing.Currency),
+		Discount:     NewMoney(0, pricing.Currency),
+		ItemsTotal:   NewMoney(0, pricing.Currency),
+		Rounding:     pricing.Rounding,
+		Restrictions: []string{},
+		Discounts:    []DiscountLine{},
+	}
+
+	for _, name := range order {
+		output := results[name]
+		result.Restrictions = append(result.Restrictions, output.Restrictions...)
+		if output.BasePrice != nil {
+			result.BasePrice = NewMoney(*output.BasePrice, pricing.Currency)
+		}
+		for _, line := range output.Discounts {
+			line.Rule = name
+			result.Discounts = append(result.Discounts, line)
+		}
+		result.Items = append(result.Items, output.Items...)
+	}
+	result.Available = len(result.Restrictions) == 0
+
+	for i := range result.Discounts {
+		result.Discounts[i].Amount = result.BasePrice.Percent(result.Discounts[i].Percent, pricing.Rounding)
+		result.Discount = result.Discount.Add(result.Discounts[i].Amount)
+	}
+	result.Subtotal = result.BasePrice.Sub(result.Discount)
+	if result.Subtotal.Amount < 0 {
+		result.Subtotal.Amount = 0
+	}
+	for _, item := range result.Items {
+		result.ItemsTotal = result.ItemsTotal.Add(item.Amount)
+	}
+	result.Subtotal = result.Subtotal.Add(result.ItemsTotal)
+	result.Charges, result.FinalPrice = pricing.applyCharges(result.Subtotal)
 
-	return AvailabilityResult{Available: true}
+	return result
 }
 
-// calculateBasePrice calculates the base price for a reservation
-func calculateBasePrice(guests int, mealType string) PriceResult {
-	// Simulate some processing time
-	time.Sleep(50 * time.Millisecond)
+// AvailabilityStage applies the table opening rules
+type AvailabilityStage struct{}
 
-	basePerPerson := 0.0
+func (AvailabilityStage) Name() string        { return StageAvailability }
+func (AvailabilityStage) DependsOn() []string { return nil }
+
+func (AvailabilityStage) Run(ctx context.Context, in CalculationInput, _ StageResults) (StageOutput, error) {
+	// Simple logic: tables 1-10 always available, others check time
+	if in.TableNumber <= 10 {
+		return StageOutput{}, nil
+	}
 
-	switch mealType {
-	case MealTypeBreakfast:
-		basePerPerson = 15.0
-	case MealTypeLunch:
-		basePerPerson = 25.0
-	case MealTypeDinner:
-		basePerPerson = 40.0
-	case MealTypeEvent:
-		basePerPerson = 75.0
-	default:
-		basePerPerson = 30.0
+	// Weekend (higher demand): only available before 6 PM
+	weekday := in.DateTime.Weekday()
+	if (weekday == time.Saturday || weekday == time.Sunday) && in.DateTime.Hour() >= 18 {
+		return StageOutput{Restrictions: []string{"Table not available on weekend evenings"}}, nil
 	}
 
-	total := float64(guests) * basePerPerson
+	return StageOutput{}, nil
+}
 
-	return PriceResult{BasePrice: total}
+// PriceStage prices the reservation per guest and meal type
+type PriceStage struct {
+	// Currency the prices are charged in, the pricing currency
+	Currency string
 }
 
-// calculateDiscount applies discounts based on time and user
-func calculateDiscount(dateTime time.Time, ownerID string, mealType string) DiscountResult {
-	// Simulate some processing time
-	time.Sleep(50 * time.Millisecond)
+func (PriceStage) Name() string        { return StagePrice }
+func (PriceStage) DependsOn() []string { return nil }
+
+func (s PriceStage) Run(ctx context.Context, in CalculationInput, _ StageResults) (StageOutput, error) {
+	total := int64(in.Guests) * BasePricePerPerson(in.MealType, s.Currency)
+	return StageOutput{BasePrice: &total}, nil
+}
+
+// basePrices are the per-guest prices of each meal type in major units
+var basePrices = map[string]int64{
+	MealTypeBreakfast: 15,
+	MealTypeLunch:     25,
+	MealTypeDinner:    40,
+	MealTypeEvent:     75,
+}
+
+// BasePricePerPerson returns the per-guest price of a meal type in minor units of currency
+func BasePricePerPerson(mealType, currency string) int64 {
+	price, ok := basePrices[mealType]
+	if !ok {
+		price = 30
+	}
+	return price * int64(math.Pow10(CurrencyExponent(currency)))
+}
+
+// DiscountStage applies the time-based discounts
+type DiscountStage struct{}
+
+func (DiscountStage) Name() string        { return StageDiscount }
+func (DiscountStage) DependsOn() []string { return nil }
 
-	discountPercent := 0.0
+func (DiscountStage) Run(ctx context.Context, in CalculationInput, _ StageResults) (StageOutput, error) {
+	lines := []DiscountLine{}
 
 	// Early bird discount (before 6 PM)
-	if dateTime.Hour() < 18 && mealType == MealTypeDinner {
-		discountPercent += 10.0
+	if in.DateTime.Hour() < 18 && in.MealType == MealTypeDinner {
+		lines = append(lines, DiscountLine{Code: "early_bird", Description: "Early dinner (before 6 PM)", Percent: 10.0})
 	}
 
 	// Weekday discount (Monday-Thursday)
-	weekday := dateTime.Weekday()
+	weekday := in.DateTime.Weekday()
 	if weekday >= time.Monday && weekday <= time.Thursday {
-		discountPercent += 5.0
+		lines = append(lines, DiscountLine{Code: "weekday", Description: "Monday to Thursday", Percent: 5.0})
 	}
 
+	return StageOutput{Discounts: lines}, nil
+}
+
+// LoyaltyStage rewards returning customers
+type LoyaltyStage struct{}
+
+func (LoyaltyStage) Name() string        { return StageLoyalty }
+func (LoyaltyStage) DependsOn() []string { return nil }
+
+func (LoyaltyStage) Run(ctx context.Context, in CalculationInput, _ StageResults) (StageOutput, error) {
 	// Loyal customer discount (simulated - ID ending in even number)
-	if len(ownerID) > 0 && (ownerID[len(ownerID)-1]%2 == 0) {
-		discountPercent += 5.0
+	if len(in.OwnerID) > 0 && (in.OwnerID[len(in.OwnerID)-1]%2 == 0) {
+		return StageOutput{Discounts: []DiscountLine{{Code: "loyalty", Description: "Loyal customer", Percent: 5.0}}}, nil
+	}
+	return StageOutput{}, nil
+}
+
+// Promotion is a percentage discount for reservations in [From, To)
+type Promotion struct {
+	Code        string
+	Description string
+	Percent     float64
+	// MealType limits the promotion to one meal type (empty means all)
+	MealType string
+	From     time.Time
+	To       time.Time
+}
+
+// PromoStage applies the promotions running at the reservation time
+type PromoStage struct {
+	Promotions []Promotion
+}
+
+func (PromoStage) Name() string        { return StagePromo }
+func (PromoStage) DependsOn() []string { return nil }
+
+func (s PromoStage) Run(ctx context.Context, in CalculationInput, _ StageResults) (StageOutput, error) {
+	lines := []DiscountLine{}
+	for _, promo := range s.Promotions {
+		if promo.MealType != "" && promo.MealType != in.MealType {
+			continue
+		}
+		if in.DateTime.Before(promo.From) || !in.DateTime.Before(promo.To) {
+			continue
+		}
+		lines = append(lines, DiscountLine{Code: promo.Code, Description: promo.Description, Percent: promo.Percent})
+	}
+	return StageOutput{Discounts: lines}, nil
+}
+
+// PreOrderStage prices the menu items pre-ordered with the reservation
+type PreOrderStage struct {
+	// Currency the menu is priced in, the pricing currency
+	Currency string
+}
+
+func (PreOrderStage) Name() string        { return StagePreOrder }
+func (PreOrderStage) DependsOn() []string { return nil }
+
+func (s PreOrderStage) Run(ctx context.Context, in CalculationInput, _ StageResults) (StageOutput, error) {
+	items := make([]PreOrderLine, 0, len(in.PreOrder))
+	for _, item := range in.PreOrder {
+		if item.UnitPrice.Currency != s.Currency {
+			return StageOutput{}, fmt.Errorf("%s is priced in %s, not %s", item.Name, item.UnitPrice.Currency, s.Currency)
+		}
+		item.Amount = NewMoney(item.UnitPrice.Amount*int64(item.Quantity), s.Currency)
+		items = append(items, item)
 	}
+	return StageOutput{Items: items}, nil
+}
+
+// ParsePromotions reads a spec such as "summer:15:dinner:2026-01-05:2026-02-28;launch:10::2026-03-01:2026-03-07",
+// each promotion being "<code>:<percent>:<meal type or empty>:<from>:<to>" with inclusive dates
+func ParsePromotions(spec string) ([]Promotion, error) {
+	promotions := []Promotion{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) != 5 || parts[0] == "" {
+			return nil, fmt.Errorf("invalid promotion %q", entry)
+		}
+		percent, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid percent in promotion %q", entry)
+		}
+		if parts[2] != "" && !isValidMealType(parts[2]) {
+			return nil, fmt.Errorf("invalid meal type in promotion %q", entry)
+		}
+		from, err := time.Parse("2006-01-02", parts[3])
+		if err != nil {
+			return nil, fmt.Errorf("invalid start date in promotion %q", entry)
+		}
+		to, err := time.Parse("2006-01-02", parts[4])
+		if err != nil || to.Before(from) {
+			return nil, fmt.Errorf("invalid end date in promotion %q", entry)
+		}
 
-	// Return percentage; the discount amount is derived from BasePrice
-	// inside CalculateReservationConcurrent after collecting all results.
-	return DiscountResult{
-		DiscountPercent: discountPercent,
-		DiscountAmount:  0, // computed from percent during aggregation
+		promotions = append(promotions, Promotion{
+			Code:        parts[0],
+			Description: "Promotion " + parts[0],
+			Percent:     percent,
+			MealType:    parts[2],
+			From:        from,
+			To:          to.AddDate(0, 0, 1),
+		})
 	}
+	return promotions, nil
 }
@@ -0,0 +1,500 @@
+package domain
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// funcStage is a configurable stage for tests
+type funcStage struct {
+	name string
+	deps []string
+	run  func(ctx context.Context, in CalculationInput, prior StageResults) (StageOutput, error)
+}
+
+func (s funcStage) Name() string        { return s.name }
+func (s funcStage) DependsOn() []string { return s.deps }
+func (s funcStage) Run(ctx context.Context, in CalculationInput, prior StageResults) (StageOutput, error) {
+	return s.run(ctx, in, prior)
+}
+
+// blockingStage waits until the context is done
+func blockingStage(name string) funcStage {
+	return funcStage{name: name, run: func(ctx context.Context, _ CalculationInput, _ StageResults) (StageOutput, error) {
+		<-ctx.Done()
+		return StageOutput{}, ctx.Err()
+	}}
+}
+
+// A Wednesday and a Saturday evening
+var (
+	wednesdayEvening   = time.Date(2030, time.January, 2, 20, 0, 0, 0, time.UTC)
+	wednesdayAfternoon = time.Date(2030, time.January, 2, 17, 0, 0, 0, time.UTC)
+	saturdayEvening    = time.Date(2030, time.January, 5, 20, 0, 0, 0, time.UTC)
+)
+
+func TestDefaultPipeline(t *testing.T) {
+	promo := Promotion{
+		Code: "launch", Percent: 20, MealType: MealTypeLunch,
+		From: time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2030, time.January, 3, 0, 0, 0, 0, time.UTC),
+	}
+	pipeline := DefaultCalculationPipeline([]Promotion{promo}, DefaultPricing())
+
+	tests := []struct {
+		name          string
+		in            CalculationInput
+		wantAvailable bool
+		wantBase      int64
+		wantCodes     []string
+		wantFinal     int64
+	}{
+		{
+			name:          "weekday dinner, regular customer",
+			in:            CalculationInput{TableNumber: 1, Guests: 2, DateTime: wednesdayEvening, MealType: MealTypeDinner, OwnerID: "7"},
+			wantAvailable: true,
+			wantBase:      8000,
+			wantCodes:     []string{"weekday"},
+			wantFinal:     7600,
+		},
+		{
+			name:          "early weekday dinner, loyal customer",
+			in:            CalculationInput{TableNumber: 1, Guests: 2, DateTime: wednesdayAfternoon, MealType: MealTypeDinner, OwnerID: "8"},
+			wantAvailable: true,
+			wantBase:      8000,
+			wantCodes:     []string{"early_bird", "weekday", "loyalty"},
+			wantFinal:     6400,
+		},
+		{
+			name:          "lunch during a promotion",
+			in:            CalculationInput{TableNumber: 2, Guests: 4, DateTime: wednesdayAfternoon, MealType: MealTypeLunch, OwnerID: "7"},
+			wantAvailable: true,
+			wantBase:      10000,
+			wantCodes:     []string{"weekday", "launch"},
+			wantFinal:     7500,
+		},
+		{
+			name:          "large table on a weekend evening",
+			in:            CalculationInput{TableNumber: 12, Guests: 2, DateTime: saturdayEvening, MealType: MealTypeDinner, OwnerID: "7"},
+			wantAvailable: false,
+			wantBase:      8000,
+			wantCodes:     []string{},
+			wantFinal:     8000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := pipeline.Run(context.Background(), tt.in)
+			if err != nil {
+				t.Fatalf("Run() error = %v", err)
+			}
+			if result.Available != tt.wantAvailable {
+				t.Errorf("Available = %v, want %v (restrictions %v)", result.Available, tt.wantAvailable, result.Restrictions)
+			}
+			if result.BasePrice != NewMoney(tt.wantBase, DefaultCurrency) {
+				t.Errorf("BasePrice = %v, want %d", result.BasePrice, tt.wantBase)
+			}
+			if result.FinalPrice != NewMoney(tt.wantFinal, DefaultCurrency) {
+				t.Errorf("FinalPrice = %v, want %d", result.FinalPrice, tt.wantFinal)
+			}
+
+			codes := []string{}
+			sum := NewMoney(0, DefaultCurrency)
+			for _, line := range result.Discounts {
+				codes = append(codes, line.Code)
+				sum = sum.Add(line.Amount)
+			}
+			if len(codes) != len(tt.wantCodes) {
+				t.Fatalf("discounts = %v, want %v", codes, tt.wantCodes)
+			}
+			for i := range codes {
+				if codes[i] != tt.wantCodes[i] {
+					t.Errorf("discounts = %v, want %v", codes, tt.wantCodes)
+					break
+				}
+			}
+			if sum != result.Discount {
+				t.Errorf("discount lines add up to %v, Discount = %v", sum, result.Discount)
+			}
+		})
+	}
+}
+
+func TestPipelineFinalPriceNeverNegative(t *testing.T) {
+	generous := funcStage{name: "generous", run: func(context.Context, CalculationInput, StageResults) (StageOutput, error) {
+		return StageOutput{Discounts: []DiscountLine{{Code: "all", Percent: 150}}}, nil
+	}}
+	pipeline, err := NewCalculationPipeline(PriceStage{}, generous)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pipeline.Run(context.Background(), CalculationInput{Guests: 2, MealType: MealTypeLunch})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.FinalPrice.IsZero() {
+		t.Errorf("FinalPrice = %v, want 0", result.FinalPrice)
+	}
+}
+
+func TestPipelineRunsIndependentStagesConcurrently(t *testing.T) {
+	// Each stage only returns once the other one has started: run sequentially this would deadlock
+	var started sync.WaitGroup
+	started.Add(2)
+	rendezvous := func(name string) funcStage {
+		return funcStage{name: name, run: func(ctx context.Context, _ CalculationInput, _ StageResults) (StageOutput, error) {
+			started.Done()
+			done := make(chan struct{})
+			go func() { started.Wait(); close(done) }()
+			select {
+			case <-done:
+				return StageOutput{}, nil
+			case <-ctx.Done():
+				return StageOutput{}, ctx.Err()
+			}
+		}}
+	}
+
+	pipeline, err := NewCalculationPipeline(rendezvous("a"), rendezvous("b"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := pipeline.Run(ctx, CalculationInput{}); err != nil {
+		t.Fatalf("Run() error = %v, independent stages did not run concurrently", err)
+	}
+}
+
+func TestPipelineRunsDependentStagesInOrder(t *testing.T) {
+	var base int64
+	surcharge := funcStage{
+		name: "surcharge",
+		deps: []string{StagePrice},
+		run: func(_ context.Context, _ CalculationInput, prior StageResults) (StageOutput, error) {
+			price, ok := prior[StagePrice]
+			if !ok || price.BasePrice == nil {
+				return StageOutput{}, errors.New("price stage output missing")
+			}
+			base = *price.BasePrice
+			return StageOutput{Restrictions: []string{"surcharge checked"}}, nil
+		},
+	}
+
+	// Declared before the stage it depends on on purpose
+	pipeline, err := NewCalculationPipeline(surcharge, PriceStage{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := pipeline.Run(context.Background(), CalculationInput{Guests: 3, MealType: MealTypeBreakfast})
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if base != 4500 {
+		t.Errorf("dependent stage saw base price %v, want 4500", base)
+	}
+	if result.Available {
+		t.Error("restriction from the dependent stage was not aggregated")
+	}
+}
+
+func TestNewCalculationPipelineRejectsInvalidGraphs(t *testing.T) {
+	noop := func(context.Context, CalculationInput, StageResults) (StageOutput, error) { return StageOutput{}, nil }
+
+	tests := map[string][]CalculationStage{
+		"duplicate name": {PriceStage{}, PriceStage{}},
+		"unknown dependency": {
+			funcStage{name: "a", deps: []string{"missing"}, run: noop},
+		},
+		"cycle": {
+			funcStage{name: "a", deps: []string{"b"}, run: noop},
+			funcStage{name: "b", deps: []string{"a"}, run: noop},
+		},
+	}
+	for name, stages := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := NewCalculationPipeline(stages...); err == nil {
+				t.Error("NewCalculationPipeline() accepted an invalid stage graph")
+			}
+		})
+	}
+}
+
+func TestPipelinePropagatesStageErrors(t *testing.T) {
+	boom := errors.New("pricing service down")
+	failing := funcStage{name: "failing", run: func(context.Context, CalculationInput, StageResults) (StageOutput, error) {
+		return StageOutput{}, boom
+	}}
+	var ranAfter atomic.Bool
+	after := funcStage{name: "after", deps: []string{"failing"}, run: func(context.Context, CalculationInput, StageResults) (StageOutput, error) {
+		ranAfter.Store(true)
+		return StageOutput{}, nil
+	}}
+
+	// The blocking sibling is cancelled by the failure instead of hanging the pipeline
+	pipeline, err := NewCalculationPipeline(failing, blockingStage("sibling"), after)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pipeline.Run(context.Background(), CalculationInput{})
+	if !errors.Is(err, boom) {
+		t.Fatalf("Run() error = %v, want the stage error", err)
+	}
+	var stageErr *StageError
+	if !errors.As(err, &stageErr) || stageErr.Stage != "failing" {
+		t.Errorf("Run() error = %v, want a StageError for the failing stage", err)
+	}
+	if errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, the cancelled sibling must not be reported", err)
+	}
+	if ranAfter.Load() {
+		t.Error("a stage depending on a failed stage ran")
+	}
+}
+
+func TestPipelineHonoursDeadline(t *testing.T) {
+	pipeline, err := NewCalculationPipeline(PriceStage{}, blockingStage("slow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err = pipeline.Run(ctx, CalculationInput{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Run() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Run() took %s after the deadline", elapsed)
+	}
+}
+
+func TestPipelineDoesNotStartWhenCancelled(t *testing.T) {
+	var ran atomic.Bool
+	stage := funcStage{name: "a", run: func(context.Context, CalculationInput, StageResults) (StageOutput, error) {
+		ran.Store(true)
+		return StageOutput{}, nil
+	}}
+	pipeline, err := NewCalculationPipeline(stage)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := pipeline.Run(ctx, CalculationInput{}); !errors.Is(err, context.Canceled) {
+		t.Errorf("Run() error = %v, want context.Canceled", err)
+	}
+	if ran.Load() {
+		t.Error("stage ran with a cancelled context")
+	}
+}
+
+// TestPipelineConcurrentRuns shares one pipeline between goroutines; run with -race
+func TestPipelineConcurrentRuns(t *testing.T) {
+	pipeline := DefaultCalculationPipeline(nil, DefaultPricing())
+	in := CalculationInput{TableNumber: 3, Guests: 4, DateTime: wednesdayAfternoon, MealType: MealTypeDinner, OwnerID: "42"}
+
+	want, err := pipeline.Run(context.Background(), in)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 50)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got, err := pipeline.Run(context.Background(), in)
+			if err != nil {
+				errs <- err
+				return
+			}
+			if got.FinalPrice != want.FinalPrice || len(got.Discounts) != len(want.Discounts) {
+				errs <- errors.New("concurrent run returned a different result")
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+}
+
+func TestPipelineBreakdownRecordsRulesAndVersion(t *testing.T) {
+	promo := Promotion{
+		Code: "launch", Percent: 20, MealType: MealTypeLunch,
+		From: time.Date(2030, time.January, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2030, time.January, 3, 0, 0, 0, 0, time.UTC),
+	}
+	pipeline := DefaultCalculationPipeline([]Promotion{promo}, DefaultPricing())
+
+	result, err := pipeline.Run(context.Background(), CalculationInput{TableNumber: 2, Guests: 4, DateTime: wednesdayAfternoon, MealType: MealTypeLunch, OwnerID: "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	breakdown := result.Breakdown()
+
+	rules := []string{}
+	for _, line := range breakdown.Discounts {
+		rules = append(rules, line.Rule+"/"+line.Code)
+	}
+	if strings.Join(rules, ",") != "discount/weekday,promo/launch" {
+		t.Errorf("discount rules = %v, want discount/weekday and promo/launch", rules)
+	}
+	if breakdown.CalculatedAt.IsZero() {
+		t.Error("CalculatedAt not set")
+	}
+	if breakdown.RulesVersion == "" || breakdown.RulesVersion != pipeline.RulesVersion() {
+		t.Errorf("RulesVersion = %q, want the pipeline's %q", breakdown.RulesVersion, pipeline.RulesVersion())
+	}
+
+	// Same rules, same version; other promotions or charges, another version
+	if again := DefaultCalculationPipeline([]Promotion{promo}, DefaultPricing()); again.RulesVersion() != pipeline.RulesVersion() {
+		t.Errorf("rules version not stable: %q then %q", pipeline.RulesVersion(), again.RulesVersion())
+	}
+	if other := DefaultCalculationPipeline(nil, DefaultPricing()); other.RulesVersion() == pipeline.RulesVersion() {
+		t.Error("rules version ignores the promotions")
+	}
+	charged := DefaultPricing()
+	charged.Charges = []ChargeRule{{Code: "iva", Kind: ChargeTax, Percent: 21}}
+	if other := DefaultCalculationPipeline([]Promotion{promo}, charged); other.RulesVersion() == pipeline.RulesVersion() {
+		t.Error("rules version ignores the charges")
+	}
+}
+
+func TestPipelinePricesPreOrderAfterDiscounts(t *testing.T) {
+	pricing := DefaultPricing()
+	pricing.Charges = []ChargeRule{{Code: "iva", Kind: ChargeTax, Percent: 21}}
+	pipeline := DefaultCalculationPipeline(nil, pricing)
+
+	preOrder := []PreOrderLine{{Name: "Provoleta", UnitPrice: NewMoney(1200, DefaultCurrency), Quantity: 2}}
+	result, err := pipeline.Run(context.Background(), CalculationInput{
+		TableNumber: 2, Guests: 2, DateTime: wednesdayAfternoon, MealType: MealTypeLunch, OwnerID: "7", PreOrder: preOrder,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 2 x 25.00 lunch, 5% weekday discount on the covers only, 2 x 12.00 provoleta, 21% VAT
+	if result.Discount.Amount != 250 || result.ItemsTotal.Amount != 2400 {
+		t.Errorf("discount = %v, items = %v, want 2.50 and 24.00", result.Discount, result.ItemsTotal)
+	}
+	if result.Subtotal.Amount != 7150 || result.FinalPrice.Amount != 8652 {
+		t.Errorf("subtotal = %v, total = %v, want 71.50 and 86.52", result.Subtotal, result.FinalPrice)
+	}
+	if len(result.Items) != 1 || result.Items[0].Amount.Amount != 2400 {
+		t.Errorf("items = %+v, want the provoleta line priced at 24.00", result.Items)
+	}
+
+	// Items priced in another currency are not mixed into the total
+	preOrder[0].UnitPrice = NewMoney(1200, "USD")
+	if _, err := pipeline.Run(context.Background(), CalculationInput{
+		TableNumber: 2, Guests: 2, DateTime: wednesdayAfternoon, MealType: MealTypeLunch, PreOrder: preOrder,
+	}); err == nil {
+		t.Error("pre-order in USD priced in ARS")
+	}
+}
+
+func TestApplyPriceKeepsReplacedTotal(t *testing.T) {
+	pipeline := DefaultCalculationPipeline(nil, DefaultPricing())
+	r := &Reservation{TableNumber: 1, Guests: 2, DateTime: wednesdayEvening, MealType: MealTypeDinner, OwnerID: "7"}
+
+	first, err := pipeline.Run(context.Background(), r.CalculationInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ApplyPrice(first, PriceTriggerCreate)
+	if r.Price.Trigger != PriceTriggerCreate || r.Price.PreviousTotal != nil {
+		t.Errorf("first calculation: trigger %q, previous total %v", r.Price.Trigger, r.Price.PreviousTotal)
+	}
+
+	r.Guests = 4
+	second, err := pipeline.Run(context.Background(), r.CalculationInput())
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.ApplyPrice(second, PriceTriggerUpdate)
+	if r.Price.Trigger != PriceTriggerUpdate || r.Price.PreviousTotal == nil || *r.Price.PreviousTotal != first.FinalPrice {
+		t.Errorf("recalculation: trigger %q, previous total %v, want %v", r.Price.Trigger, r.Price.PreviousTotal, first.FinalPrice)
+	}
+	if r.TotalPrice != second.FinalPrice || r.Price.Total != r.TotalPrice {
+		t.Errorf("TotalPrice = %v, breakdown total %v, want %v", r.TotalPrice, r.Price.Total, second.FinalPrice)
+	}
+
+	// An unchanged total does not report a previous one
+	r.ApplyPrice(second, PriceTriggerConfirm)
+	if r.Price.PreviousTotal != nil {
+		t.Errorf("unchanged total reported previous total %v", r.Price.PreviousTotal)
+	}
+}
+
+func BenchmarkDefaultPipeline(b *testing.B) {
+	pipeline := DefaultCalculationPipeline(nil, DefaultPricing())
+	in := CalculationInput{TableNumber: 3, Guests: 4, DateTime: wednesdayAfternoon, MealType: MealTypeDinner, OwnerID: "42"}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if _, err := pipeline.Run(ctx, in); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkDefaultPipelineParallel(b *testing.B) {
+	pipeline := DefaultCalculationPipeline(nil, DefaultPricing())
+	in := CalculationInput{TableNumber: 3, Guests: 4, DateTime: wednesdayAfternoon, MealType: MealTypeDinner, OwnerID: "42"}
+	ctx := context.Background()
+
+	b.ReportAllocs()
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			if _, err := pipeline.Run(ctx, in); err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
+func TestBasePriceFollowsCurrencyExponent(t *testing.T) {
+	tests := []struct {
+		currency  string
+		wantBase  int64
+		wantFinal int64
+	}{
+		{"ARS", 8000, 7600},
+		{"JPY", 80, 76},       // no minor unit
+		{"KWD", 80000, 76000}, // thousandths
+	}
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if got, want := BasePricePerPerson(MealTypeDinner, tt.currency), tt.wantBase/2; got != want {
+				t.Errorf("BasePricePerPerson(dinner, %s) = %d, want %d", tt.currency, got, want)
+			}
+
+			pipeline := DefaultCalculationPipeline(nil, Pricing{Currency: tt.currency, Rounding: RoundHalfUp})
+			result, err := pipeline.Run(context.Background(), CalculationInput{TableNumber: 1, Guests: 2, DateTime: wednesdayEvening, MealType: MealTypeDinner, OwnerID: "7"})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if result.BasePrice != NewMoney(tt.wantBase, tt.currency) || result.FinalPrice != NewMoney(tt.wantFinal, tt.currency) {
+				t.Errorf("base %v, final %v, want %d and %d %s", result.BasePrice, result.FinalPrice, tt.wantBase, tt.wantFinal, tt.currency)
+			}
+		})
+	}
+}
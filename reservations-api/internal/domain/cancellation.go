@@ -0,0 +1,180 @@
+package domain
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CancellationTier is one step of a cancellation policy: cancelling at least
+// MinHoursBefore hours before the reservation costs FeePercent of the total price
+type CancellationTier struct {
+	MinHoursBefore float64 `bson:"min_hours_before" json:"min_hours_before"`
+	FeePercent     float64 `bson:"fee_percent" json:"fee_percent"`
+}
+
+// CancellationPolicy holds the cancellation tiers that apply to a meal type
+type CancellationPolicy struct {
+	MealType string             `bson:"meal_type" json:"meal_type"`
+	Tiers    []CancellationTier `bson:"tiers" json:"tiers"`
+}
+
+// CancellationPolicies maps a meal type to its cancellation policy
+type CancellationPolicies map[string]CancellationPolicy
+
+// CancelReservationRequest DTO for cancelling a reservation
+type CancelReservationRequest struct {
+	Reason string `json:"reason,omitempty"`
+}
+
+// DefaultCancellationPolicies returns the built-in policy for each meal type
+func DefaultCancellationPolicies() CancellationPolicies {
+	return CancellationPolicies{
+		MealTypeBreakfast: {
+			MealType: MealTypeBreakfast,
+			Tiers: []CancellationTier{
+				{MinHoursBefore: 2, FeePercent: 0},
+				{MinHoursBefore: 0, FeePercent: 50},
+			},
+		},
+		MealTypeLunch: {
+			MealType: MealTypeLunch,
+			Tiers: []CancellationTier{
+				{MinHoursBefore: 4, FeePercent: 0},
+				{MinHoursBefore: 0, FeePercent: 50},
+			},
+		},
+		MealTypeDinner: {
+			MealType: MealTypeDinner,
+			Tiers: []CancellationTier{
+				{MinHoursBefore: 24, FeePercent: 0},
+				{MinHoursBefore: 2, FeePercent: 50},
+				{MinHoursBefore: 0, FeePercent: 100},
+			},
+		},
+		MealTypeEvent: {
+			MealType: MealTypeEvent,
+			Tiers: []CancellationTier{
+				{MinHoursBefore: 72, FeePercent: 0},
+				{MinHoursBefore: 24, FeePercent: 50},
+				{MinHoursBefore: 0, FeePercent: 100},
+			},
+		},
+	}
+}
+
+// ParseCancellationPolicies overrides the default policies with a spec such as
+// "dinner=24:0,2:50,0:100;event=72:0,0:100", where each tier is
+// "<min hours before>:<fee percent>"
+func ParseCancellationPolicies(spec string) (CancellationPolicies, error) {
+	policies := DefaultCancellationPolicies()
+	if strings.TrimSpace(spec) == "" {
+		return policies, nil
+	}
+
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		mealType, rawTiers, ok := strings.Cut(entry, "=")
+		mealType = strings.TrimSpace(mealType)
+		if !ok || !isValidMealType(mealType) {
+			return nil, fmt.Errorf("invalid cancellation policy entry %q", entry)
+		}
+
+		tiers := []CancellationTier{}
+		for _, rawTier := range strings.Split(rawTiers, ",") {
+			hours, percent, ok := strings.Cut(strings.TrimSpace(rawTier), ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid cancellation tier %q for %s", rawTier, mealType)
+			}
+			minHours, err := strconv.ParseFloat(hours, 64)
+			if err != nil || minHours < 0 {
+				return nil, fmt.Errorf("invalid hours in cancellation tier %q for %s", rawTier, mealType)
+			}
+			fee, err := strconv.ParseFloat(percent, 64)
+			if err != nil || fee < 0 || fee > 100 {
+				return nil, fmt.Errorf("invalid fee in cancellation tier %q for %s", rawTier, mealType)
+			}
+			tiers = append(tiers, CancellationTier{MinHoursBefore: minHours, FeePercent: fee})
+		}
+
+		policies[mealType] = NewCancellationPolicy(mealType, tiers)
+	}
+
+	return policies, nil
+}
+
+// NewCancellationPolicy builds a policy with its tiers sorted from the
+// earliest cancellation window to the latest
+func NewCancellationPolicy(mealType string, tiers []CancellationTier) CancellationPolicy {
+	sorted := append([]CancellationTier(nil), tiers...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].MinHoursBefore > sorted[j].MinHoursBefore
+	})
+	return CancellationPolicy{MealType: mealType, Tiers: sorted}
+}
+
+// For returns the policy for a meal type, or a policy without fees if none is configured
+func (p CancellationPolicies) For(mealType string) CancellationPolicy {
+	if policy, ok := p[mealType]; ok {
+		return policy
+	}
+	return CancellationPolicy{
+		MealType: mealType,
+		Tiers:    []CancellationTier{{MinHoursBefore: 0, FeePercent: 0}},
+	}
+}
+
+// FeePercentAt returns the fee percentage for cancelling at the given time
+func (p CancellationPolicy) FeePercentAt(reservationTime, cancelledAt time.Time) float64 {
+	if len(p.Tiers) == 0 {
+		return 0
+	}
+
+	hoursBefore := reservationTime.Sub(cancelledAt).Hours()
+	for _, tier := range p.Tiers {
+		if hoursBefore >= tier.MinHoursBefore {
+			return tier.FeePercent
+		}
+	}
+
+	// Past every window (e.g. the reservation time already passed): the
+	// strictest tier applies
+	return p.Tiers[len(p.Tiers)-1].FeePercent
+}
+
+// Cancel marks the reservation as cancelled and records the fee due under the
+// cancellation policy agreed at booking time
+func (r *Reservation) Cancel(fallback CancellationPolicy, reason string, now time.Time) error {
+	if r.Status == StatusCancelled {
+		return ConflictError("reservation already cancelled")
+	}
+	if err := r.checkTransition(StatusCancelled); err != nil {
+		return err
+	}
+
+	policy := fallback
+	if r.CancellationPolicy != nil {
+		policy = *r.CancellationPolicy
+	}
+
+	percent := policy.FeePercentAt(r.DateTime, now)
+	r.Status = StatusCancelled
+	r.CancellationFee = nil
+	rounding := RoundHalfUp
+	if r.Price != nil && r.Price.Rounding != "" {
+		rounding = r.Price.Rounding
+	}
+	if fee := r.TotalPrice.Percent(percent, rounding); !fee.IsZero() {
+		r.CancellationFee = &fee
+	}
+	r.CancellationReason = reason
+	r.CancelledAt = &now
+	r.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,124 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestParseCancellationPolicies(t *testing.T) {
+	policies, err := ParseCancellationPolicies("dinner=0:100, 24:0 ,2:50; event=48:0")
+	if err != nil {
+		t.Fatalf("ParseCancellationPolicies() error = %v", err)
+	}
+
+	dinner := policies[MealTypeDinner].Tiers
+	want := []CancellationTier{{24, 0}, {2, 50}, {0, 100}}
+	if len(dinner) != len(want) {
+		t.Fatalf("dinner tiers = %v, want %v", dinner, want)
+	}
+	for i := range want {
+		if dinner[i] != want[i] {
+			t.Errorf("dinner tier %d = %v, want %v (sorted earliest first)", i, dinner[i], want[i])
+		}
+	}
+	if got := policies[MealTypeEvent].Tiers; len(got) != 1 || got[0] != (CancellationTier{48, 0}) {
+		t.Errorf("event tiers = %v", got)
+	}
+	if got := policies[MealTypeLunch]; len(got.Tiers) != len(DefaultCancellationPolicies()[MealTypeLunch].Tiers) {
+		t.Errorf("lunch policy = %v, want the default", got)
+	}
+
+	for _, spec := range []string{
+		"brunch=0:10",  // unknown meal type
+		"dinner",       // no tiers
+		"dinner=24",    // tier without a fee
+		"dinner=-1:10", // negative hours
+		"dinner=2:150", // fee over 100%
+		"dinner=x:10",
+	} {
+		if _, err := ParseCancellationPolicies(spec); err == nil {
+			t.Errorf("ParseCancellationPolicies(%q) succeeded, want an error", spec)
+		}
+	}
+
+	if policies, err := ParseCancellationPolicies("  "); err != nil || len(policies) != len(DefaultCancellationPolicies()) {
+		t.Errorf("empty spec = %v, %v, want the defaults", policies, err)
+	}
+}
+
+func TestFeePercentAt(t *testing.T) {
+	at := time.Date(2030, time.March, 1, 21, 0, 0, 0, time.UTC)
+	dinner := DefaultCancellationPolicies()[MealTypeDinner]
+
+	tests := []struct {
+		name   string
+		before time.Duration
+		want   float64
+	}{
+		{"well ahead", 72 * time.Hour, 0},
+		{"exactly at the free window", 24 * time.Hour, 0},
+		{"just inside the late window", 24*time.Hour - time.Minute, 50},
+		{"exactly two hours before", 2 * time.Hour, 50},
+		{"last minute", time.Minute, 100},
+		{"after the reservation time", -time.Hour, 100},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dinner.FeePercentAt(at, at.Add(-tt.before)); got != tt.want {
+				t.Errorf("FeePercentAt() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if got := (CancellationPolicy{}).FeePercentAt(at, at); got != 0 {
+		t.Errorf("FeePercentAt() without tiers = %v, want 0", got)
+	}
+}
+
+func TestReservationCancel(t *testing.T) {
+	at := time.Date(2030, time.March, 1, 21, 0, 0, 0, time.UTC)
+	dinner := DefaultCancellationPolicies()[MealTypeDinner]
+
+	t.Run("fee rounded with the reservation's mode", func(t *testing.T) {
+		r := &Reservation{Status: StatusConfirmed, DateTime: at, TotalPrice: NewMoney(1235, DefaultCurrency), Price: &PriceBreakdown{Rounding: RoundHalfEven}}
+		if err := r.Cancel(dinner, "plans changed", at.Add(-3*time.Hour)); err != nil {
+			t.Fatalf("Cancel() error = %v", err)
+		}
+		// 50% of 12.35 is 6.175: half-even rounds to 6.18
+		if r.CancellationFee == nil || r.CancellationFee.Amount != 618 {
+			t.Errorf("fee = %v, want 618", r.CancellationFee)
+		}
+		if r.Status != StatusCancelled || r.CancelledAt == nil || r.CancellationReason != "plans changed" {
+			t.Errorf("reservation = %+v, want cancelled with the reason", r)
+		}
+	})
+
+	t.Run("no fee in the free window", func(t *testing.T) {
+		r := &Reservation{Status: StatusPending, DateTime: at, TotalPrice: NewMoney(5000, DefaultCurrency)}
+		if err := r.Cancel(dinner, "", at.Add(-48*time.Hour)); err != nil {
+			t.Fatalf("Cancel() error = %v", err)
+		}
+		if r.CancellationFee != nil {
+			t.Errorf("fee = %v, want none", r.CancellationFee)
+		}
+	})
+
+	t.Run("policy agreed at booking wins", func(t *testing.T) {
+		agreed := NewCancellationPolicy(MealTypeDinner, []CancellationTier{{0, 0}})
+		r := &Reservation{Status: StatusConfirmed, DateTime: at, TotalPrice: NewMoney(5000, DefaultCurrency), CancellationPolicy: &agreed}
+		if err := r.Cancel(dinner, "", at.Add(-time.Minute)); err != nil {
+			t.Fatalf("Cancel() error = %v", err)
+		}
+		if r.CancellationFee != nil {
+			t.Errorf("fee = %v, want none under the agreed policy", r.CancellationFee)
+		}
+	})
+
+	t.Run("already cancelled", func(t *testing.T) {
+		r := &Reservation{Status: StatusCancelled, DateTime: at}
+		if err := r.Cancel(dinner, "", at); !errors.Is(err, ErrConflict) {
+			t.Errorf("Cancel() error = %v, want conflict", err)
+		}
+	})
+}
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// CheckInWindow is how long before and after the booked time guests can check in
+type CheckInWindow struct {
+	OpensBefore time.Duration
+	ClosesAfter time.Duration
+}
+
+// Opens returns the earliest check-in time for a reservation
+func (w CheckInWindow) Opens(r *Reservation) time.Time {
+	return r.DateTime.Add(-w.OpensBefore)
+}
+
+// Closes returns the latest check-in time for a reservation
+func (w CheckInWindow) Closes(r *Reservation) time.Time {
+	return r.DateTime.Add(w.ClosesAfter)
+}
+
+// CheckInRequest DTO for redeeming a check-in token
+type CheckInRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+// Seat checks the guests in: only confirmed reservations can be seated, and only within the window
+func (r *Reservation) Seat(window CheckInWindow, now time.Time) error {
+	switch r.Status {
+	case StatusConfirmed:
+	case StatusSeated:
+		return ConflictError("reservation already checked in")
+	default:
+		return ConflictError("only confirmed reservations can check in (status is %s)", r.Status)
+	}
+
+	if opens := window.Opens(r); now.Before(opens) {
+		return ConflictError("check-in opens at %s", opens.Format(time.RFC3339))
+	}
+	if now.After(window.Closes(r)) {
+		return ConflictError("check-in window has closed")
+	}
+
+	r.Status = StatusSeated
+	r.SeatedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
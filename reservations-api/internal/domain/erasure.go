@@ -0,0 +1,56 @@
+package domain
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErasureCancellationReason is recorded on reservations cancelled because their owner
+// deleted the account
+const ErasureCancellationReason = "account deleted"
+
+// ErasureReceipt records how a deleted user's reservations were erased
+type ErasureReceipt struct {
+	ID     primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	UserID string             `bson:"user_id" json:"user_id"`
+	// Tombstone replaced the user as owner of every reservation
+	Tombstone string `bson:"tombstone" json:"tombstone"`
+	// Upcoming reservations cancelled, and live and archived reservations anonymized
+	Cancelled          int   `bson:"cancelled" json:"cancelled"`
+	Anonymized         int64 `bson:"anonymized" json:"anonymized"`
+	ArchivedAnonymized int64 `bson:"archived_anonymized" json:"archived_anonymized"`
+	// RequestedAt is when users-api deleted the account
+	RequestedAt time.Time `bson:"requested_at" json:"requested_at"`
+	CompletedAt time.Time `bson:"completed_at" json:"completed_at"`
+}
+
+// ErasedOwnerID is the tombstone owner of an erased user's reservations. It is derived
+// from the receipt, not the user, so it cannot be traced back to the account.
+func ErasedOwnerID(receiptID primitive.ObjectID) string {
+	return "erased-" + receiptID.Hex()
+}
+
+// CancelForErasure cancels an upcoming pending or confirmed reservation, freeing its
+// table, and reports whether it did. No cancellation fee is charged.
+func (r *Reservation) CancelForErasure(now time.Time) bool {
+	if !r.DateTime.After(now) || (r.Status != StatusPending && r.Status != StatusConfirmed) {
+		return false
+	}
+
+	r.Status = StatusCancelled
+	r.CancellationFee = nil
+	r.CancellationReason = ErasureCancellationReason
+	r.CancelledAt = &now
+	r.UpdatedAt = now
+	return true
+}
+
+// Anonymize drops what identifies the owner: the owner ID becomes the tombstone and the
+// free-text special requests are cleared, as are the allergies and dietary tags (health data)
+func (r *Reservation) Anonymize(tombstone string) {
+	r.OwnerID = tombstone
+	r.SpecialRequests = ""
+	r.Allergies = nil
+	r.Dietary = nil
+}
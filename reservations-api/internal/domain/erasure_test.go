@@ -0,0 +1,70 @@
+package domain
+
+import (
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestCancelForErasure(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	policy := &CancellationPolicy{MealType: MealTypeDinner}
+
+	tests := []struct {
+		name      string
+		at        time.Time
+		status    string
+		cancelled bool
+	}{
+		{"upcoming pending", now.Add(48 * time.Hour), StatusPending, true},
+		{"upcoming confirmed", now.Add(time.Hour), StatusConfirmed, true},
+		{"upcoming already cancelled", now.Add(48 * time.Hour), StatusCancelled, false},
+		{"seated", now.Add(time.Hour), StatusSeated, false},
+		{"past confirmed", now.Add(-time.Hour), StatusConfirmed, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reservation{DateTime: tt.at, Status: tt.status, TotalPrice: NewMoney(12000, DefaultCurrency), CancellationPolicy: policy}
+			if got := r.CancelForErasure(now); got != tt.cancelled {
+				t.Fatalf("CancelForErasure = %v, want %v", got, tt.cancelled)
+			}
+			if !tt.cancelled {
+				if r.Status != tt.status {
+					t.Errorf("status changed to %s", r.Status)
+				}
+				return
+			}
+			if r.Status != StatusCancelled || r.CancellationReason != ErasureCancellationReason {
+				t.Errorf("status = %s, reason = %q", r.Status, r.CancellationReason)
+			}
+			if r.CancellationFee != nil {
+				t.Errorf("fee = %v, want none", r.CancellationFee)
+			}
+			if r.CancelledAt == nil || !r.CancelledAt.Equal(now) {
+				t.Errorf("cancelled_at = %v, want %v", r.CancelledAt, now)
+			}
+		})
+	}
+}
+
+func TestAnonymize(t *testing.T) {
+	tombstone := ErasedOwnerID(primitive.NewObjectID())
+	r := &Reservation{
+		OwnerID: "42", SpecialRequests: "Alergia al maní, mesa cerca de la ventana", Guests: 4,
+		Allergies: []string{"peanuts"}, Dietary: []string{"diabetic"}, Occasion: OccasionBirthday,
+	}
+
+	r.Anonymize(tombstone)
+
+	if r.OwnerID != tombstone || r.SpecialRequests != "" {
+		t.Errorf("owner = %q, special requests = %q", r.OwnerID, r.SpecialRequests)
+	}
+	if r.Allergies != nil || r.Dietary != nil {
+		t.Errorf("allergies = %v, dietary = %v, want the health data cleared", r.Allergies, r.Dietary)
+	}
+	if r.Guests != 4 || r.Occasion != OccasionBirthday {
+		t.Errorf("guests = %d, want the booking details kept", r.Guests)
+	}
+}
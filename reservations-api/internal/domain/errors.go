@@ -0,0 +1,107 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Error categories. Match them with errors.Is; the HTTP layer maps each one
+// to a status code and a machine-readable error code.
+var (
+	ErrNotFound   = errors.New("not found")
+	ErrConflict   = errors.New("conflict")
+	ErrForbidden  = errors.New("forbidden")
+	ErrValidation = errors.New("validation failed")
+	ErrUpstream   = errors.New("upstream service unavailable")
+)
+
+// Error is a domain error of a given category with a message for the client
+type Error struct {
+	Kind    error
+	Message string
+	// Err is the underlying cause, kept for errors.Is/As and logs
+	Err error
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is(err, ErrNotFound) (etc.) match on the category
+func (e *Error) Is(target error) bool {
+	return target == e.Kind
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// NotFoundError reports a missing resource
+func NotFoundError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrNotFound, Message: fmt.Sprintf(format, args...)}
+}
+
+// ConflictError reports a request that clashes with the current state
+// (table already booked, reservation already cancelled, ...)
+func ConflictError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrConflict, Message: fmt.Sprintf(format, args...)}
+}
+
+// ForbiddenError reports a caller that may not access the resource
+func ForbiddenError(format string, args ...interface{}) error {
+	return &Error{Kind: ErrForbidden, Message: fmt.Sprintf(format, args...)}
+}
+
+// UpstreamError reports a dependency (Users API, ...) that could not answer
+func UpstreamError(cause error, format string, args ...interface{}) error {
+	return &Error{Kind: ErrUpstream, Message: fmt.Sprintf(format, args...), Err: cause}
+}
+
+// ValidationError reports invalid input, field by field
+type ValidationError struct {
+	// Fields maps a request field (JSON name) to what is wrong with it
+	Fields map[string]string
+}
+
+// NewValidationError creates a validation error for a single field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: map[string]string{field: message}}
+}
+
+// Add records a problem with a field
+func (e *ValidationError) Add(field, message string) {
+	if e.Fields == nil {
+		e.Fields = map[string]string{}
+	}
+	e.Fields[field] = message
+}
+
+// OrNil returns the error if a field was recorded, nil otherwise
+func (e *ValidationError) OrNil() error {
+	if e == nil || len(e.Fields) == 0 {
+		return nil
+	}
+	return e
+}
+
+// Error lists the field problems in a stable order
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+
+	messages := make([]string, 0, len(fields))
+	for _, field := range fields {
+		messages = append(messages, fmt.Sprintf("%s: %s", field, e.Fields[field]))
+	}
+	return "validation failed: " + strings.Join(messages, "; ")
+}
+
+// Is makes errors.Is(err, ErrValidation) match
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
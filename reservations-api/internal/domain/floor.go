@@ -0,0 +1,204 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+	"time"
+)
+
+// Live table statuses at the host stand
+const (
+	TableFree         = "free"
+	TableReservedSoon = "reserved_soon"
+	TableOccupied     = "occupied"
+	TableCleaning     = "cleaning"
+)
+
+// WalkInGuestName is shown for walk-ins seated without a name
+const WalkInGuestName = "Sin reserva"
+
+// FloorPolicy decides how tables move between statuses
+type FloorPolicy struct {
+	// CleaningTime is how long a table stays in cleaning after its guests leave
+	CleaningTime time.Duration
+	// ReservedSoon is how long before a booked time its table is held for it
+	ReservedSoon time.Duration
+	// Location is the restaurant's time zone, which decides what "today" is
+	Location *time.Location
+}
+
+// Today returns local midnight of the day now falls on
+func (p FloorPolicy) Today(now time.Time) time.Time {
+	local := now.In(p.Location)
+	return time.Date(local.Year(), local.Month(), local.Day(), 0, 0, 0, 0, p.Location)
+}
+
+// TableStatus is what a table is doing right now
+type TableStatus struct {
+	TableConfig
+	Status string `json:"status"`
+	// Reservation occupying the table, or expected at it when reserved soon
+	ReservationID string `json:"reservation_id,omitempty"`
+	WalkIn        bool   `json:"walk_in,omitempty"`
+	Guests        int    `json:"guests,omitempty"`
+	// Since is when the guests sat down (occupied) or left (cleaning)
+	Since *time.Time `json:"since,omitempty"`
+	// Until is when the table is expected to be free again
+	Until *time.Time `json:"until,omitempty"`
+	// NextReservation is the booked time of the next reservation still to arrive
+	NextReservation *time.Time `json:"next_reservation,omitempty"`
+}
+
+// FloorStatus is the live status of every table of a meal type
+type FloorStatus struct {
+	Date     string        `json:"date"`
+	MealType string        `json:"meal_type"`
+	At       time.Time     `json:"at"`
+	Tables   []TableStatus `json:"tables"`
+	// Number of tables in each status
+	Counts map[string]int `json:"counts"`
+}
+
+// NewFloorStatus derives the status of each table from the day's reservations of the meal
+// type: seated guests occupy their table, which then stays in cleaning for the policy's
+// cleaning time after they complete, and a pending or confirmed reservation holds its
+// table from ReservedSoon before its booked time until its expected end.
+func NewFloorStatus(mealType string, reservations []Reservation, policy FloorPolicy, now time.Time) *FloorStatus {
+	byTable := make(map[int][]Reservation)
+	for _, r := range reservations {
+		if r.MealType == mealType {
+			byTable[r.TableNumber] = append(byTable[r.TableNumber], r)
+		}
+	}
+
+	floor := &FloorStatus{
+		Date:     policy.Today(now).Format("2006-01-02"),
+		MealType: mealType,
+		At:       now,
+		Tables:   []TableStatus{},
+		Counts:   map[string]int{TableFree: 0, TableReservedSoon: 0, TableOccupied: 0, TableCleaning: 0},
+	}
+	for _, table := range GetTablesForMealType(mealType) {
+		status := newTableStatus(table, byTable[table.TableNumber], policy, now)
+		floor.Tables = append(floor.Tables, status)
+		floor.Counts[status.Status]++
+	}
+	return floor
+}
+
+func newTableStatus(table TableConfig, reservations []Reservation, policy FloorPolicy, now time.Time) TableStatus {
+	sort.Slice(reservations, func(i, j int) bool { return reservations[i].DateTime.Before(reservations[j].DateTime) })
+
+	status := TableStatus{TableConfig: table, Status: TableFree}
+	for _, r := range reservations {
+		if (r.Status == StatusPending || r.Status == StatusConfirmed) && now.Before(r.DateTime.Add(r.ExpectedDuration())) {
+			at := r.DateTime
+			status.NextReservation = &at
+			break
+		}
+	}
+
+	for _, r := range reservations {
+		switch {
+		case r.Status == StatusSeated:
+			status.occupy(TableOccupied, r, r.SeatedAt, r.ExpectedDuration())
+			return status
+		case r.Status == StatusCompleted && r.CompletedAt != nil && now.Before(r.CompletedAt.Add(policy.CleaningTime)):
+			status.occupy(TableCleaning, r, r.CompletedAt, policy.CleaningTime)
+		}
+	}
+	if status.Status != TableFree {
+		return status
+	}
+
+	for _, r := range reservations {
+		if (r.Status == StatusPending || r.Status == StatusConfirmed) &&
+			!now.Before(r.DateTime.Add(-policy.ReservedSoon)) && now.Before(r.DateTime.Add(r.ExpectedDuration())) {
+			at := r.DateTime
+			status.Status = TableReservedSoon
+			status.ReservationID = r.ID.Hex()
+			status.Guests = r.Guests
+			status.Since = &at
+			break
+		}
+	}
+	return status
+}
+
+// occupy records who holds the table since when, and for how long
+func (s *TableStatus) occupy(status string, r Reservation, since *time.Time, d time.Duration) {
+	s.Status = status
+	s.ReservationID = r.ID.Hex()
+	s.WalkIn = r.WalkIn
+	s.Guests = r.Guests
+	if since != nil {
+		until := since.Add(d)
+		s.Since, s.Until = since, &until
+	}
+}
+
+// Table returns the status of a table of the floor
+func (f *FloorStatus) Table(number int) (TableStatus, bool) {
+	for _, t := range f.Tables {
+		if t.TableNumber == number {
+			return t, true
+		}
+	}
+	return TableStatus{}, false
+}
+
+// WalkInRequest DTO for seating guests who arrive without a reservation
+type WalkInRequest struct {
+	TableNumber     int    `json:"table_number" binding:"required,min=1"`
+	Guests          int    `json:"guests" binding:"required,min=1,max=20"`
+	MealType        string `json:"meal_type" binding:"required,oneof=breakfast lunch dinner event"`
+	GuestName       string `json:"guest_name,omitempty" binding:"max=100"`
+	SpecialRequests string `json:"special_requests,omitempty" binding:"max=500"`
+}
+
+// SeatWalkIn seats a walk-in at a free table of the floor. The table must fit the party
+// and be free for as long as the walk-in is expected to stay, so it cannot push back
+// the next reservation of the table.
+func (f *FloorStatus) SeatWalkIn(req WalkInRequest, now time.Time) (*Reservation, error) {
+	table, ok := f.Table(req.TableNumber)
+	if !ok || req.MealType != f.MealType {
+		return nil, NewValidationError("table_number", "does not exist for "+req.MealType)
+	}
+	if req.Guests > table.Capacity {
+		return nil, ConflictError("table %d seats %d guests", table.TableNumber, table.Capacity)
+	}
+	if table.Status != TableFree {
+		return nil, ConflictError("table %d is %s", table.TableNumber, strings.ReplaceAll(table.Status, "_", " "))
+	}
+
+	walkIn := Reservation{
+		WalkIn:          true,
+		GuestName:       strings.TrimSpace(req.GuestName),
+		TableNumber:     req.TableNumber,
+		Guests:          req.Guests,
+		DateTime:        now,
+		MealType:        req.MealType,
+		Status:          StatusSeated,
+		TotalPrice:      NewMoney(0, DefaultCurrency), // will be calculated
+		SpecialRequests: req.SpecialRequests,
+		SeatedAt:        &now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	if next := table.NextReservation; next != nil && next.Before(now.Add(walkIn.ExpectedDuration())) {
+		return nil, ConflictError("table %d is booked at %s", table.TableNumber, next.In(now.Location()).Format("15:04"))
+	}
+	return &walkIn, nil
+}
+
+// Complete frees the table of seated guests who have left
+func (r *Reservation) Complete(now time.Time) error {
+	if r.Status != StatusSeated {
+		return ConflictError("only seated reservations can be completed (status is %s)", r.Status)
+	}
+
+	r.Status = StatusCompleted
+	r.CompletedAt = &now
+	r.UpdatedAt = now
+	return nil
+}
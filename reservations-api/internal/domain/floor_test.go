@@ -0,0 +1,119 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testFloorPolicy = FloorPolicy{CleaningTime: 10 * time.Minute, ReservedSoon: 30 * time.Minute, Location: time.UTC}
+
+func TestNewFloorStatus(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 21, 0, 0, 0, time.UTC)
+	ago := func(d time.Duration) *time.Time { at := now.Add(-d); return &at }
+	reservation := func(table int, status string, at time.Time) Reservation {
+		return Reservation{ID: primitive.NewObjectID(), TableNumber: table, Guests: 2, MealType: MealTypeDinner, DateTime: at, Status: status}
+	}
+
+	seated := reservation(1, StatusSeated, now.Add(-time.Hour))
+	seated.SeatedAt = ago(50 * time.Minute)
+	walkIn := reservation(2, StatusSeated, now.Add(-20*time.Minute))
+	walkIn.WalkIn, walkIn.SeatedAt = true, ago(20*time.Minute)
+	justLeft := reservation(3, StatusCompleted, now.Add(-2*time.Hour))
+	justLeft.CompletedAt = ago(5 * time.Minute)
+	leftLongAgo := reservation(4, StatusCompleted, now.Add(-3*time.Hour))
+	leftLongAgo.CompletedAt = ago(time.Hour)
+	lunch := reservation(10, StatusSeated, now)
+	lunch.MealType = MealTypeLunch
+
+	floor := NewFloorStatus(MealTypeDinner, []Reservation{
+		seated, walkIn, justLeft, leftLongAgo,
+		reservation(5, StatusConfirmed, now.Add(20*time.Minute)), // held for it
+		reservation(6, StatusPending, now.Add(-15*time.Minute)),  // running late
+		reservation(7, StatusConfirmed, now.Add(2*time.Hour)),    // later tonight
+		reservation(8, StatusCancelled, now.Add(10*time.Minute)), // no longer expected
+		reservation(9, StatusConfirmed, now.Add(-3*time.Hour)),   // never came
+		lunch,
+	}, testFloorPolicy, now)
+
+	want := map[int]string{
+		1: TableOccupied, 2: TableOccupied, 3: TableCleaning, 4: TableFree, 5: TableReservedSoon,
+		6: TableReservedSoon, 7: TableFree, 8: TableFree, 9: TableFree, 10: TableFree,
+	}
+	for _, table := range floor.Tables {
+		if table.Status != want[table.TableNumber] {
+			t.Errorf("table %d status = %s, want %s", table.TableNumber, table.Status, want[table.TableNumber])
+		}
+	}
+
+	if table, _ := floor.Table(2); !table.WalkIn || table.Until == nil || !table.Until.Equal(now.Add(100*time.Minute)) {
+		t.Errorf("walk-in table = %+v, want a walk-in expected to leave in 100 minutes", table)
+	}
+	if table, _ := floor.Table(3); table.Until == nil || !table.Until.Equal(now.Add(5*time.Minute)) {
+		t.Errorf("cleaning table until = %v, want in 5 minutes", table.Until)
+	}
+	if table, _ := floor.Table(7); table.NextReservation == nil || !table.NextReservation.Equal(now.Add(2*time.Hour)) {
+		t.Errorf("table 7 next reservation = %v, want in 2 hours", table.NextReservation)
+	}
+	if floor.Counts[TableOccupied] != 2 || floor.Counts[TableCleaning] != 1 || floor.Counts[TableReservedSoon] != 2 || floor.Counts[TableFree] != 5 {
+		t.Errorf("counts = %v", floor.Counts)
+	}
+}
+
+func TestSeatWalkIn(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 19, 0, 0, 0, time.UTC)
+	occupied := Reservation{ID: primitive.NewObjectID(), TableNumber: 1, Guests: 2, MealType: MealTypeDinner, DateTime: now, Status: StatusSeated, SeatedAt: &now}
+	laterTonight := Reservation{ID: primitive.NewObjectID(), TableNumber: 4, Guests: 4, MealType: MealTypeDinner, DateTime: now.Add(90 * time.Minute), Status: StatusConfirmed}
+	floor := NewFloorStatus(MealTypeDinner, []Reservation{occupied, laterTonight}, testFloorPolicy, now)
+
+	tests := []struct {
+		name string
+		req  WalkInRequest
+		want error
+	}{
+		{"free table", WalkInRequest{TableNumber: 3, Guests: 4, MealType: MealTypeDinner}, nil},
+		{"occupied", WalkInRequest{TableNumber: 1, Guests: 2, MealType: MealTypeDinner}, ErrConflict},
+		{"too many guests", WalkInRequest{TableNumber: 2, Guests: 3, MealType: MealTypeDinner}, ErrConflict},
+		{"booked before the walk-in would leave", WalkInRequest{TableNumber: 4, Guests: 2, MealType: MealTypeDinner}, ErrConflict},
+		{"unknown table", WalkInRequest{TableNumber: 11, Guests: 2, MealType: MealTypeDinner}, ErrValidation},
+		{"other meal", WalkInRequest{TableNumber: 3, Guests: 2, MealType: MealTypeLunch}, ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			walkIn, err := floor.SeatWalkIn(tt.req, now)
+			if tt.want != nil {
+				if !errors.Is(err, tt.want) {
+					t.Fatalf("SeatWalkIn() error = %v, want %v", err, tt.want)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SeatWalkIn() error = %v", err)
+			}
+			if !walkIn.WalkIn || walkIn.Status != StatusSeated || walkIn.SeatedAt == nil || walkIn.OwnerID != "" {
+				t.Errorf("walk-in = %+v, want seated without an owner", walkIn)
+			}
+			if err := walkIn.Validate(); err != nil {
+				t.Errorf("walk-in Validate() = %v", err)
+			}
+		})
+	}
+}
+
+func TestCompleteReservation(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 23, 0, 0, 0, time.UTC)
+
+	r := &Reservation{Status: StatusSeated}
+	if err := r.Complete(now); err != nil {
+		t.Fatalf("Complete() error = %v", err)
+	}
+	if r.Status != StatusCompleted || r.CompletedAt == nil || !r.CompletedAt.Equal(now) {
+		t.Errorf("reservation = %+v, want completed now", r)
+	}
+
+	if err := (&Reservation{Status: StatusConfirmed}).Complete(now); !errors.Is(err, ErrConflict) {
+		t.Errorf("Complete() of a confirmed reservation error = %v, want conflict", err)
+	}
+}
@@ -0,0 +1,106 @@
+package domain
+
+import (
+	"sort"
+	"strings"
+)
+
+// Dietary tags guests can ask for
+var DietaryTags = []string{
+	"vegetarian", "vegan", "pescatarian", "gluten_free", "lactose_free",
+	"halal", "kosher", "low_sodium", "diabetic",
+}
+
+// Occasions guests can celebrate with a reservation
+const (
+	OccasionBirthday    = "birthday"
+	OccasionAnniversary = "anniversary"
+	OccasionBusiness    = "business"
+	OccasionDate        = "date"
+	OccasionCelebration = "celebration"
+)
+
+// Occasions lists every occasion
+var Occasions = []string{OccasionBirthday, OccasionAnniversary, OccasionBusiness, OccasionDate, OccasionCelebration}
+
+// AccessibilityNeeds are what the party needs at the table
+type AccessibilityNeeds struct {
+	// Wheelchair requires a table flagged accessible
+	Wheelchair bool `bson:"wheelchair" json:"wheelchair"`
+	HighChairs int  `bson:"high_chairs" json:"high_chairs"`
+}
+
+// Allows reports whether a table meets the needs
+func (n AccessibilityNeeds) Allows(table TableConfig) bool {
+	return !n.Wheelchair || table.Accessible
+}
+
+// ApplyRequests copies the structured requests the update sets onto a reservation
+func (req UpdateReservationRequest) ApplyRequests(r *Reservation) {
+	if req.Allergies != nil {
+		r.Allergies = normalizeTags(*req.Allergies)
+	}
+	if req.Dietary != nil {
+		r.Dietary = normalizeTags(*req.Dietary)
+	}
+	if req.Accessibility != nil {
+		r.Accessibility = *req.Accessibility
+	}
+	if req.Occasion != nil {
+		r.Occasion = *req.Occasion
+	}
+}
+
+// ChangesTable reports whether the update can make the booked table unsuitable
+func (req UpdateReservationRequest) ChangesTable() bool {
+	return req.TableNumber != nil || req.MealType != nil || req.Accessibility != nil
+}
+
+// validateRequests checks the structured requests against their vocabularies. Allergies
+// use the menu allergens.
+func (r *Reservation) validateRequests(verr *ValidationError) {
+	for _, a := range r.Allergies {
+		if !contains(Allergens, a) {
+			verr.Add("allergies", "must only contain "+strings.Join(Allergens, ", "))
+			break
+		}
+	}
+	for _, d := range r.Dietary {
+		if !contains(DietaryTags, d) {
+			verr.Add("dietary", "must only contain "+strings.Join(DietaryTags, ", "))
+			break
+		}
+	}
+	if r.Accessibility.HighChairs < 0 || r.Accessibility.HighChairs > r.Guests {
+		verr.Add("accessibility.high_chairs", "must be between 0 and the number of guests")
+	}
+	if r.Occasion != "" && !contains(Occasions, r.Occasion) {
+		verr.Add("occasion", "must be one of "+strings.Join(Occasions, ", "))
+	}
+}
+
+// CheckTable refuses a table that does not meet the party's accessibility needs
+func (r *Reservation) CheckTable() error {
+	for _, table := range GetTablesForMealType(r.MealType) {
+		if table.TableNumber == r.TableNumber && !r.Accessibility.Allows(table) {
+			return ConflictError("table %d is not wheelchair accessible", r.TableNumber)
+		}
+	}
+	return nil
+}
+
+// normalizeTags sorts the tags and drops duplicates, so equal requests are stored alike
+func normalizeTags(tags []string) []string {
+	if len(tags) == 0 {
+		return nil
+	}
+	sorted := append([]string(nil), tags...)
+	sort.Strings(sorted)
+	unique := sorted[:1]
+	for _, tag := range sorted[1:] {
+		if tag != unique[len(unique)-1] {
+			unique = append(unique, tag)
+		}
+	}
+	return unique
+}
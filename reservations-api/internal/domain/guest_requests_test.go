@@ -0,0 +1,82 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewReservationNormalizesRequests(t *testing.T) {
+	r := NewReservation(CreateReservationRequest{
+		Allergies: []string{"peanuts", "gluten", "peanuts"},
+		Dietary:   []string{"vegan"},
+	})
+	if len(r.Allergies) != 2 || r.Allergies[0] != "gluten" || r.Allergies[1] != "peanuts" {
+		t.Errorf("allergies = %v, want [gluten peanuts]", r.Allergies)
+	}
+	if len(r.Dietary) != 1 {
+		t.Errorf("dietary = %v, want [vegan]", r.Dietary)
+	}
+	if r := NewReservation(CreateReservationRequest{}); r.Allergies != nil {
+		t.Errorf("allergies = %v, want none", r.Allergies)
+	}
+}
+
+func TestValidateStructuredRequests(t *testing.T) {
+	base := Reservation{
+		OwnerID: "u1", TableNumber: 1, Guests: 4, MealType: MealTypeDinner, Status: StatusPending,
+		DateTime: time.Now().Add(48 * time.Hour),
+	}
+	valid := base
+	valid.Allergies = []string{"gluten"}
+	valid.Dietary = []string{"vegetarian"}
+	valid.Accessibility = AccessibilityNeeds{Wheelchair: true, HighChairs: 2}
+	valid.Occasion = OccasionBirthday
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid reservation: %v", err)
+	}
+
+	invalid := base
+	invalid.Allergies = []string{"lactose"}
+	invalid.Dietary = []string{"carnivore"}
+	invalid.Accessibility = AccessibilityNeeds{HighChairs: 5}
+	invalid.Occasion = "wedding"
+	var verr *ValidationError
+	if err := invalid.Validate(); !errors.As(err, &verr) || len(verr.Fields) != 4 {
+		t.Errorf("invalid reservation: error = %v, want allergies, dietary, high chairs and occasion rejected", err)
+	}
+}
+
+func TestCheckTableNeedsAccessibleTable(t *testing.T) {
+	r := &Reservation{MealType: MealTypeDinner, TableNumber: 2, Accessibility: AccessibilityNeeds{Wheelchair: true}}
+	if err := r.CheckTable(); !errors.Is(err, ErrConflict) {
+		t.Errorf("table 2: error = %v, want a conflict", err)
+	}
+
+	r.TableNumber = 1
+	if err := r.CheckTable(); err != nil {
+		t.Errorf("table 1: error = %v, want none", err)
+	}
+
+	r.TableNumber, r.Accessibility.Wheelchair = 2, false
+	if err := r.CheckTable(); err != nil {
+		t.Errorf("no wheelchair: error = %v, want none", err)
+	}
+}
+
+func TestCalendarCountsAccessibleTables(t *testing.T) {
+	day := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	q := AvailabilityQuery{From: day, To: day.AddDate(0, 0, 1), MealType: MealTypeDinner, Guests: 2}
+	reserved := []ReservedSlot{{Date: "2030-03-01", MealType: MealTypeDinner, Tables: []int{1, 2}}}
+
+	all := NewAvailabilityCalendar(q, reserved).Days[0].Meals[0]
+	q.Accessible = true
+	accessible := NewAvailabilityCalendar(q, reserved).Days[0].Meals[0]
+
+	if accessible.TotalTables >= all.TotalTables {
+		t.Errorf("accessible tables = %d, want fewer than %d", accessible.TotalTables, all.TotalTables)
+	}
+	if accessible.FreeTables != accessible.TotalTables-1 {
+		t.Errorf("free accessible tables = %d of %d, want table 1 taken", accessible.FreeTables, accessible.TotalTables)
+	}
+}
@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// HoldPurgeAfter is how long after expiring a hold is deleted by the TTL index, which
+// leaves the hold reaper time to announce the release first
+const HoldPurgeAfter = time.Hour
+
+// Hold keeps a table for a guest while they finish booking it. Only the holder, who
+// got the token, can book the table until the hold expires.
+type Hold struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Slot is the table, meal type and day held; there is one hold per slot
+	Slot        string    `bson:"slot" json:"-"`
+	TokenHash   string    `bson:"token_hash" json:"-"`
+	OwnerID     string    `bson:"owner_id" json:"owner_id"`
+	TableNumber int       `bson:"table_number" json:"table_number"`
+	Guests      int       `bson:"guests" json:"guests"`
+	DateTime    time.Time `bson:"date_time" json:"date_time"`
+	MealType    string    `bson:"meal_type" json:"meal_type"`
+	CreatedAt   time.Time `bson:"created_at" json:"created_at"`
+	// ExpiresAt is when the table is released
+	ExpiresAt time.Time `bson:"expires_at" json:"expires_at"`
+	// Released is set once the hold reaper has announced the expiry
+	Released bool `bson:"released,omitempty" json:"-"`
+}
+
+// HoldRequest DTO for holding a table during checkout
+type HoldRequest struct {
+	TableNumber int       `json:"table_number" binding:"required,min=1"`
+	Guests      int       `json:"guests" binding:"required,min=1,max=20"`
+	DateTime    time.Time `json:"date_time" binding:"required"`
+	MealType    string    `json:"meal_type" binding:"required,oneof=breakfast lunch dinner event"`
+}
+
+// HoldGrant is a new hold with the token that books its table
+type HoldGrant struct {
+	*Hold
+	Token string `json:"token"`
+}
+
+// HoldSlot identifies a table for a meal type on the day of dateTime, the way the table
+// availability check does
+func HoldSlot(dateTime time.Time, mealType string, tableNumber int) string {
+	return fmt.Sprintf("%s|%s|%d", dateTime.Format("2006-01-02"), mealType, tableNumber)
+}
+
+// NewHold holds a table for ttl on behalf of ownerID. The table must exist for the meal
+// type and fit the party.
+func NewHold(req HoldRequest, ownerID string, ttl time.Duration, now time.Time) (*HoldGrant, error) {
+	verr := &ValidationError{}
+	capacity, ok := GetTableCapacity(req.TableNumber, req.MealType)
+	switch {
+	case !ok:
+		verr.Add("table_number", "does not exist for "+req.MealType)
+	case req.Guests > capacity:
+		verr.Add("guests", fmt.Sprintf("table %d seats %d guests", req.TableNumber, capacity))
+	}
+	if !req.DateTime.After(now) {
+		verr.Add("date_time", "must be in the future")
+	}
+	if err := verr.OrNil(); err != nil {
+		return nil, err
+	}
+
+	raw := make([]byte, 24)
+	if _, err := rand.Read(raw); err != nil {
+		return nil, fmt.Errorf("failed to generate hold token: %w", err)
+	}
+	token := base64.RawURLEncoding.EncodeToString(raw)
+
+	return &HoldGrant{
+		Hold: &Hold{
+			Slot:        HoldSlot(req.DateTime, req.MealType, req.TableNumber),
+			TokenHash:   HashHoldToken(token),
+			OwnerID:     ownerID,
+			TableNumber: req.TableNumber,
+			Guests:      req.Guests,
+			DateTime:    req.DateTime,
+			MealType:    req.MealType,
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(ttl),
+		},
+		Token: token,
+	}, nil
+}
+
+// HashHoldToken returns what is stored of a hold token
+func HashHoldToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// Active reports whether the hold still keeps its table
+func (h *Hold) Active(now time.Time) bool {
+	return now.Before(h.ExpiresAt)
+}
+
+// Allows reports whether a reservation of ownerID presenting token may book the held
+// table. The token is not a bearer token: only the guest who took the hold may use it.
+func (h *Hold) Allows(ownerID, token string) bool {
+	if token == "" || ownerID != h.OwnerID {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(h.TokenHash), []byte(HashHoldToken(token))) == 1
+}
+
+// WithHolds adds the held tables to the reserved ones, as the availability calendar
+// counts both as taken
+func WithHolds(reserved []ReservedSlot, holds []Hold) []ReservedSlot {
+	for _, h := range holds {
+		reserved = append(reserved, ReservedSlot{
+			Date:     h.DateTime.Format("2006-01-02"),
+			MealType: h.MealType,
+			Tables:   []int{h.TableNumber},
+		})
+	}
+	return reserved
+}
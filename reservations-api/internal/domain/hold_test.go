@@ -0,0 +1,54 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestNewHold(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	req := HoldRequest{TableNumber: 3, Guests: 4, DateTime: now.Add(8 * time.Hour), MealType: MealTypeDinner}
+
+	grant, err := NewHold(req, "42", 10*time.Minute, now)
+	if err != nil {
+		t.Fatalf("NewHold() error = %v", err)
+	}
+	if grant.Slot != "2030-03-01|dinner|3" || !grant.ExpiresAt.Equal(now.Add(10*time.Minute)) {
+		t.Errorf("hold = %+v, want dinner table 3 until 12:10", grant.Hold)
+	}
+	if grant.TokenHash == grant.Token || !grant.Allows("42", grant.Token) || grant.Allows("42", "") || grant.Allows("42", grant.Token+"x") {
+		t.Errorf("token %q is not checked against its hash", grant.Token)
+	}
+	if grant.Allows("43", grant.Token) || grant.Allows("", grant.Token) {
+		t.Errorf("the token of guest 42's hold books the table for someone else")
+	}
+	if !grant.Active(now.Add(9*time.Minute)) || grant.Active(now.Add(10*time.Minute)) {
+		t.Errorf("hold should be active until it expires")
+	}
+
+	tests := map[string]HoldRequest{
+		"unknown table":   {TableNumber: 11, Guests: 2, DateTime: req.DateTime, MealType: MealTypeDinner},
+		"too many guests": {TableNumber: 1, Guests: 3, DateTime: req.DateTime, MealType: MealTypeDinner},
+		"in the past":     {TableNumber: 3, Guests: 2, DateTime: now.Add(-time.Hour), MealType: MealTypeDinner},
+	}
+	for name, req := range tests {
+		if _, err := NewHold(req, "42", time.Minute, now); !errors.Is(err, ErrValidation) {
+			t.Errorf("%s: error = %v, want a validation error", name, err)
+		}
+	}
+}
+
+func TestAvailabilityCalendarCountsHolds(t *testing.T) {
+	day := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	q := AvailabilityQuery{From: day, To: day.AddDate(0, 0, 1), MealType: MealTypeDinner, Guests: 2}
+	reserved := []ReservedSlot{{Date: "2030-03-01", MealType: MealTypeDinner, Tables: []int{1, 2}}}
+	holds := []Hold{{TableNumber: 2, MealType: MealTypeDinner, DateTime: day.Add(21 * time.Hour)}, {TableNumber: 5, MealType: MealTypeDinner, DateTime: day.Add(20 * time.Hour)}}
+
+	without := NewAvailabilityCalendar(q, reserved)
+	with := NewAvailabilityCalendar(q, WithHolds(reserved, holds))
+
+	if got, want := with.Days[0].Meals[0].FreeTables, without.Days[0].Meals[0].FreeTables-1; got != want {
+		t.Errorf("free tables with holds = %d, want %d", got, want)
+	}
+}
@@ -0,0 +1,95 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Invoice is the fiscal document of a reservation. It is issued once, numbered from a
+// gap-free sequence, and is a snapshot: later changes to the reservation or the customer
+// do not alter an issued invoice.
+type Invoice struct {
+	ID primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	// Sequence is the position in the gap-free invoice sequence, Number its printed form
+	Sequence      int64              `bson:"sequence" json:"sequence"`
+	Number        string             `bson:"number" json:"number"`
+	ReservationID primitive.ObjectID `bson:"reservation_id" json:"reservation_id"`
+	IssuedAt      time.Time          `bson:"issued_at" json:"issued_at"`
+	Restaurant    RestaurantDetails  `bson:"restaurant" json:"restaurant"`
+	Customer      InvoiceCustomer    `bson:"customer" json:"customer"`
+	// What was served: date, meal type, table and party size
+	DateTime    time.Time      `bson:"date_time" json:"date_time"`
+	MealType    string         `bson:"meal_type" json:"meal_type"`
+	TableNumber int            `bson:"table_number" json:"table_number"`
+	Guests      int            `bson:"guests" json:"guests"`
+	Price       PriceBreakdown `bson:"price" json:"price"`
+}
+
+// RestaurantDetails identifies the issuer on invoices
+type RestaurantDetails struct {
+	Name    string `bson:"name" json:"name"`
+	LegalID string `bson:"legal_id,omitempty" json:"legal_id,omitempty"`
+	Address string `bson:"address,omitempty" json:"address,omitempty"`
+	Email   string `bson:"email,omitempty" json:"email,omitempty"`
+	Phone   string `bson:"phone,omitempty" json:"phone,omitempty"`
+}
+
+// InvoiceCustomer is the billed customer as known when the invoice was issued
+type InvoiceCustomer struct {
+	UserID string `bson:"user_id" json:"user_id"`
+	Name   string `bson:"name" json:"name"`
+	Email  string `bson:"email,omitempty" json:"email,omitempty"`
+}
+
+// UnknownCustomerName bills customers the Users API no longer knows (deleted accounts)
+const UnknownCustomerName = "Consumidor final"
+
+// FormatInvoiceNumber prints a sequence number with the configured prefix, e.g. "A-00000042"
+func FormatInvoiceNumber(prefix string, sequence int64) string {
+	return fmt.Sprintf("%s%08d", prefix, sequence)
+}
+
+// CanInvoice reports whether the reservation can be invoiced: it must have been
+// confirmed and not cancelled, and belong to a customer
+func (r *Reservation) CanInvoice() error {
+	if r.WalkIn {
+		return ConflictError("walk-ins have no customer account to invoice")
+	}
+	switch r.Status {
+	case StatusConfirmed, StatusSeated, StatusCompleted:
+		return nil
+	case StatusCancelled:
+		return ConflictError("cancelled reservations cannot be invoiced")
+	}
+	return ConflictError("only confirmed reservations can be invoiced")
+}
+
+// NewInvoice builds the unnumbered invoice of a reservation. Reservations priced before
+// the breakdown was stored are invoiced as a single line at their total price.
+func NewInvoice(r *Reservation, restaurant RestaurantDetails, customer InvoiceCustomer, now time.Time) *Invoice {
+	price := PriceBreakdown{
+		Base:      r.TotalPrice,
+		Discounts: []DiscountLine{},
+		Discount:  NewMoney(0, r.TotalPrice.Currency),
+		Subtotal:  r.TotalPrice,
+		Charges:   []ChargeLine{},
+		Total:     r.TotalPrice,
+	}
+	if r.Price != nil {
+		price = *r.Price
+	}
+
+	return &Invoice{
+		ReservationID: r.ID,
+		IssuedAt:      now,
+		Restaurant:    restaurant,
+		Customer:      customer,
+		DateTime:      r.DateTime,
+		MealType:      r.MealType,
+		TableNumber:   r.TableNumber,
+		Guests:        r.Guests,
+		Price:         price,
+	}
+}
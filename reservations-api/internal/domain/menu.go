@@ -0,0 +1,247 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Menu categories
+const (
+	MenuCategoryStarter = "starter"
+	MenuCategoryMain    = "main"
+	MenuCategoryDessert = "dessert"
+	MenuCategoryDrink   = "drink"
+	MenuCategorySetMenu = "set_menu"
+)
+
+// MenuCategories lists every menu category, in the order a menu is printed
+var MenuCategories = []string{MenuCategoryStarter, MenuCategoryMain, MenuCategoryDessert, MenuCategoryDrink, MenuCategorySetMenu}
+
+// Allergens are the 14 allergens food businesses must declare (EU Regulation 1169/2011)
+var Allergens = []string{
+	"gluten", "crustaceans", "eggs", "fish", "peanuts", "soybeans", "milk",
+	"nuts", "celery", "mustard", "sesame", "sulphites", "lupin", "molluscs",
+}
+
+// MaxPreOrderQuantity caps the quantity of a single pre-order line
+const MaxPreOrderQuantity = 50
+
+// MenuItem is a dish or set menu guests can pre-order with a reservation
+type MenuItem struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	Name        string             `bson:"name" json:"name"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	Category    string             `bson:"category" json:"category"`
+	Price       Money              `bson:"price" json:"price"`
+	// MealTypes lists the meals the item is served at
+	MealTypes []string `bson:"meal_types" json:"meal_types"`
+	Allergens []string `bson:"allergens" json:"allergens"`
+	// Inactive items are kept for the pre-orders that reference them but cannot be ordered
+	Active    bool      `bson:"active" json:"active"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+	UpdatedAt time.Time `bson:"updated_at" json:"updated_at"`
+}
+
+// ServedAt reports whether the item can be ordered for a meal type
+func (m *MenuItem) ServedAt(mealType string) bool {
+	if !m.Active {
+		return false
+	}
+	for _, mt := range m.MealTypes {
+		if mt == mealType {
+			return true
+		}
+	}
+	return false
+}
+
+// MenuItemRequest DTO for creating or replacing a menu item
+type MenuItemRequest struct {
+	Name        string `json:"name" binding:"required,max=100"`
+	Description string `json:"description" binding:"max=500"`
+	Category    string `json:"category" binding:"required"`
+	// Price in minor units of the pricing currency
+	Price     int64    `json:"price" binding:"min=0"`
+	MealTypes []string `json:"meal_types" binding:"required,min=1"`
+	Allergens []string `json:"allergens"`
+	// Active defaults to true
+	Active *bool `json:"active,omitempty"`
+}
+
+// Validate checks the category, meal types and allergens
+func (r MenuItemRequest) Validate() error {
+	verr := &ValidationError{}
+	if !contains(MenuCategories, r.Category) {
+		verr.Add("category", "must be one of "+strings.Join(MenuCategories, ", "))
+	}
+	for _, mt := range r.MealTypes {
+		if !isValidMealType(mt) {
+			verr.Add("meal_types", "must only contain "+strings.Join(MealTypes, ", "))
+			break
+		}
+	}
+	for _, a := range r.Allergens {
+		if !contains(Allergens, a) {
+			verr.Add("allergens", "must only contain "+strings.Join(Allergens, ", "))
+			break
+		}
+	}
+	return verr.OrNil()
+}
+
+// Apply copies the request onto an item priced in currency
+func (r MenuItemRequest) Apply(item *MenuItem, currency string, now time.Time) {
+	item.Name = strings.TrimSpace(r.Name)
+	item.Description = r.Description
+	item.Category = r.Category
+	item.Price = NewMoney(r.Price, currency)
+	item.MealTypes = r.MealTypes
+	item.Allergens = r.Allergens
+	if item.Allergens == nil {
+		item.Allergens = []string{}
+	}
+	item.Active = r.Active == nil || *r.Active
+	if item.CreatedAt.IsZero() {
+		item.CreatedAt = now
+	}
+	item.UpdatedAt = now
+}
+
+// MenuFilter narrows menu listings
+type MenuFilter struct {
+	// MealType keeps the items served at a meal type (empty means all)
+	MealType string
+	// IncludeInactive also lists the items that can no longer be ordered
+	IncludeInactive bool
+}
+
+// PreOrderLine is a menu item ordered with a reservation. Name, price and allergens are
+// copied from the menu when ordered, so later menu changes do not alter the order.
+type PreOrderLine struct {
+	MenuItemID primitive.ObjectID `bson:"menu_item_id" json:"menu_item_id"`
+	Name       string             `bson:"name" json:"name"`
+	UnitPrice  Money              `bson:"unit_price" json:"unit_price"`
+	Quantity   int                `bson:"quantity" json:"quantity"`
+	Notes      string             `bson:"notes,omitempty" json:"notes,omitempty"`
+	Allergens  []string           `bson:"allergens" json:"allergens"`
+	// Amount is UnitPrice times Quantity
+	Amount Money `bson:"amount" json:"amount"`
+}
+
+// PreOrderItemRequest is one line of a pre-order
+type PreOrderItemRequest struct {
+	MenuItemID string `json:"menu_item_id" binding:"required"`
+	Quantity   int    `json:"quantity" binding:"required,min=1"`
+	Notes      string `json:"notes" binding:"max=200"`
+}
+
+// PreOrderRequest DTO replacing the pre-order of a reservation; no items clears it
+type PreOrderRequest struct {
+	Items []PreOrderItemRequest `json:"items" binding:"dive"`
+}
+
+// MenuItemIDs returns the distinct menu item IDs of the request, or a validation error
+// when one is malformed or repeated
+func (r PreOrderRequest) MenuItemIDs() ([]primitive.ObjectID, error) {
+	ids := make([]primitive.ObjectID, 0, len(r.Items))
+	seen := map[primitive.ObjectID]bool{}
+	for _, item := range r.Items {
+		id, err := primitive.ObjectIDFromHex(item.MenuItemID)
+		if err != nil {
+			return nil, NewValidationError("items", "menu item "+item.MenuItemID+" not found")
+		}
+		if seen[id] {
+			return nil, NewValidationError("items", "menu item "+item.MenuItemID+" is listed twice")
+		}
+		if item.Quantity > MaxPreOrderQuantity {
+			return nil, NewValidationError("items", "quantity cannot exceed "+itoa(MaxPreOrderQuantity))
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// NewPreOrder builds the pre-order lines of a request from the menu items it references,
+// checking each is still served at the reservation's meal type
+func NewPreOrder(req PreOrderRequest, menu map[primitive.ObjectID]MenuItem, mealType string) ([]PreOrderLine, error) {
+	ids, err := req.MenuItemIDs()
+	if err != nil {
+		return nil, err
+	}
+
+	lines := make([]PreOrderLine, 0, len(req.Items))
+	for i, item := range req.Items {
+		menuItem, ok := menu[ids[i]]
+		if !ok {
+			return nil, NewValidationError("items", "menu item "+item.MenuItemID+" not found")
+		}
+		if !menuItem.ServedAt(mealType) {
+			return nil, NewValidationError("items", menuItem.Name+" is not served at "+mealType)
+		}
+		lines = append(lines, PreOrderLine{
+			MenuItemID: menuItem.ID,
+			Name:       menuItem.Name,
+			UnitPrice:  menuItem.Price,
+			Quantity:   item.Quantity,
+			Notes:      strings.TrimSpace(item.Notes),
+			Allergens:  menuItem.Allergens,
+			Amount:     NewMoney(menuItem.Price.Amount*int64(item.Quantity), menuItem.Price.Currency),
+		})
+	}
+	return lines, nil
+}
+
+// CanEditPreOrder checks the pre-order can still change: the reservation is pending or
+// confirmed and the kitchen cutoff before the booked time has not passed
+func (r *Reservation) CanEditPreOrder(cutoff time.Duration, now time.Time) error {
+	if r.Status != StatusPending && r.Status != StatusConfirmed {
+		return ConflictError("the pre-order of a %s reservation cannot change", r.Status)
+	}
+	if closes := r.DateTime.Add(-cutoff); !now.Before(closes) {
+		return ConflictError("pre-orders closed at %s", closes.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// PrepLine is how many portions of a menu item the kitchen prepares for one day and meal
+type PrepLine struct {
+	Date         string             `bson:"date" json:"date"`
+	MealType     string             `bson:"meal_type" json:"meal_type"`
+	MenuItemID   primitive.ObjectID `bson:"menu_item_id" json:"menu_item_id"`
+	Name         string             `bson:"name" json:"name"`
+	Quantity     int                `bson:"quantity" json:"quantity"`
+	Reservations int                `bson:"reservations" json:"reservations"`
+	Allergens    []string           `bson:"allergens" json:"allergens"`
+	// Notes are the guests' notes on the item (e.g. "sin sal"), one per line that has one
+	Notes []string `bson:"notes" json:"notes"`
+}
+
+// PrepReport is the kitchen prep list: pre-ordered portions per day, meal and item
+type PrepReport struct {
+	Range AnalyticsRange `json:"range"`
+	Lines []PrepLine     `json:"lines"`
+}
+
+// Table implements Tabular
+func (r PrepReport) Table() ([]string, [][]string) {
+	rows := make([][]string, 0, len(r.Lines))
+	for _, l := range r.Lines {
+		rows = append(rows, []string{
+			l.Date, l.MealType, l.Name, itoa(l.Quantity), itoa(l.Reservations),
+			strings.Join(l.Allergens, " "), strings.Join(l.Notes, "; "),
+		})
+	}
+	return []string{"date", "meal_type", "item", "quantity", "reservations", "allergens", "notes"}, rows
+}
+
+func contains(values []string, v string) bool {
+	for _, value := range values {
+		if value == v {
+			return true
+		}
+	}
+	return false
+}
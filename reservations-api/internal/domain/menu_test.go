@@ -0,0 +1,123 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func testMenu() (map[primitive.ObjectID]MenuItem, MenuItem, MenuItem) {
+	provoleta := MenuItem{
+		ID: primitive.NewObjectID(), Name: "Provoleta", Price: NewMoney(1200, "ARS"),
+		MealTypes: []string{MealTypeDinner, MealTypeEvent}, Allergens: []string{"milk"}, Active: true,
+	}
+	medialunas := MenuItem{
+		ID: primitive.NewObjectID(), Name: "Medialunas", Price: NewMoney(400, "ARS"),
+		MealTypes: []string{MealTypeBreakfast}, Allergens: []string{"gluten", "eggs"}, Active: true,
+	}
+	return map[primitive.ObjectID]MenuItem{provoleta.ID: provoleta, medialunas.ID: medialunas}, provoleta, medialunas
+}
+
+func TestNewPreOrderCopiesTheMenu(t *testing.T) {
+	menu, provoleta, _ := testMenu()
+
+	lines, err := NewPreOrder(PreOrderRequest{Items: []PreOrderItemRequest{
+		{MenuItemID: provoleta.ID.Hex(), Quantity: 3, Notes: " sin orégano "},
+	}}, menu, MealTypeDinner)
+	if err != nil {
+		t.Fatalf("NewPreOrder() error = %v", err)
+	}
+
+	line := lines[0]
+	if line.Name != "Provoleta" || line.UnitPrice.Amount != 1200 || line.Amount.Amount != 3600 {
+		t.Errorf("line = %+v, want 3 provoletas at 12.00", line)
+	}
+	if line.Notes != "sin orégano" || len(line.Allergens) != 1 || line.Allergens[0] != "milk" {
+		t.Errorf("notes = %q, allergens = %v", line.Notes, line.Allergens)
+	}
+
+	// An empty request clears the pre-order
+	if lines, err := NewPreOrder(PreOrderRequest{}, menu, MealTypeDinner); err != nil || lines == nil || len(lines) != 0 {
+		t.Errorf("empty pre-order = %v, %v, want no lines", lines, err)
+	}
+}
+
+func TestNewPreOrderRejectsItems(t *testing.T) {
+	menu, provoleta, medialunas := testMenu()
+	retired := provoleta
+	retired.ID = primitive.NewObjectID()
+	retired.Active = false
+	menu[retired.ID] = retired
+
+	tests := []struct {
+		name  string
+		items []PreOrderItemRequest
+	}{
+		{"not served at the meal", []PreOrderItemRequest{{MenuItemID: medialunas.ID.Hex(), Quantity: 1}}},
+		{"inactive", []PreOrderItemRequest{{MenuItemID: retired.ID.Hex(), Quantity: 1}}},
+		{"unknown", []PreOrderItemRequest{{MenuItemID: primitive.NewObjectID().Hex(), Quantity: 1}}},
+		{"malformed ID", []PreOrderItemRequest{{MenuItemID: "provoleta", Quantity: 1}}},
+		{"listed twice", []PreOrderItemRequest{{MenuItemID: provoleta.ID.Hex(), Quantity: 1}, {MenuItemID: provoleta.ID.Hex(), Quantity: 2}}},
+		{"too many", []PreOrderItemRequest{{MenuItemID: provoleta.ID.Hex(), Quantity: MaxPreOrderQuantity + 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewPreOrder(PreOrderRequest{Items: tt.items}, menu, MealTypeDinner)
+			var verr *ValidationError
+			if !errors.As(err, &verr) {
+				t.Errorf("error = %v, want a validation error", err)
+			}
+		})
+	}
+}
+
+func TestCanEditPreOrder(t *testing.T) {
+	at := time.Date(2030, time.March, 1, 21, 0, 0, 0, time.UTC)
+	cutoff := 24 * time.Hour
+
+	tests := []struct {
+		name   string
+		status string
+		now    time.Time
+		ok     bool
+	}{
+		{"before the cutoff", StatusConfirmed, at.Add(-25 * time.Hour), true},
+		{"pending", StatusPending, at.Add(-48 * time.Hour), true},
+		{"at the cutoff", StatusConfirmed, at.Add(-cutoff), false},
+		{"cancelled", StatusCancelled, at.Add(-48 * time.Hour), false},
+		{"seated", StatusSeated, at.Add(-48 * time.Hour), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &Reservation{Status: tt.status, DateTime: at}
+			err := r.CanEditPreOrder(cutoff, tt.now)
+			if tt.ok && err != nil {
+				t.Errorf("error = %v, want none", err)
+			}
+			if !tt.ok && !errors.Is(err, ErrConflict) {
+				t.Errorf("error = %v, want a conflict", err)
+			}
+		})
+	}
+}
+
+func TestMenuItemRequestValidate(t *testing.T) {
+	valid := MenuItemRequest{Name: "Flan", Category: MenuCategoryDessert, Price: 900, MealTypes: []string{MealTypeLunch}, Allergens: []string{"milk", "eggs"}}
+	if err := valid.Validate(); err != nil {
+		t.Errorf("valid request: %v", err)
+	}
+
+	invalid := MenuItemRequest{Name: "Flan", Category: "postre", MealTypes: []string{"merienda"}, Allergens: []string{"lactose"}}
+	var verr *ValidationError
+	if err := invalid.Validate(); !errors.As(err, &verr) || len(verr.Fields) != 3 {
+		t.Errorf("invalid request: error = %v, want category, meal_types and allergens rejected", err)
+	}
+
+	item := MenuItem{}
+	valid.Apply(&item, "ARS", time.Now())
+	if !item.Active || item.Price != NewMoney(900, "ARS") {
+		t.Errorf("applied item = %+v, want an active item priced at ARS 9.00", item)
+	}
+}
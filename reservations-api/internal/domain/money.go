@@ -0,0 +1,161 @@
+package domain
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// DefaultCurrency prices reservations when no currency is configured
+const DefaultCurrency = "ARS"
+
+// Money is an exact amount of an ISO 4217 currency in its minor units: cents for ARS
+// or USD, whole yen for JPY. Amounts of different currencies are never mixed.
+type Money struct {
+	Amount   int64  `bson:"amount" json:"amount"`
+	Currency string `bson:"currency" json:"currency"`
+}
+
+// NewMoney returns an amount of minor units of a currency
+func NewMoney(amount int64, currency string) Money {
+	return Money{Amount: amount, Currency: currency}
+}
+
+// currencyExponents lists the currencies whose minor unit is not the hundredth
+var currencyExponents = map[string]int{
+	"CLP": 0, "ISK": 0, "JPY": 0, "KRW": 0, "PYG": 0,
+	"BHD": 3, "JOD": 3, "KWD": 3, "OMR": 3, "TND": 3,
+}
+
+// CurrencyExponent returns how many decimal digits the minor unit of a currency has
+func CurrencyExponent(currency string) int {
+	if exp, ok := currencyExponents[currency]; ok {
+		return exp
+	}
+	return 2
+}
+
+// IsValidCurrency reports whether code looks like an ISO 4217 code (three capital letters)
+func IsValidCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// IsZero reports a zero amount; it also lets BSON omitempty drop zero amounts
+func (m Money) IsZero() bool {
+	return m.Amount == 0
+}
+
+// Add returns m + o. Both amounts must be in the same currency.
+func (m Money) Add(o Money) Money {
+	m.mustMatch(o)
+	return Money{Amount: m.Amount + o.Amount, Currency: m.Currency}
+}
+
+// Sub returns m - o. Both amounts must be in the same currency.
+func (m Money) Sub(o Money) Money {
+	m.mustMatch(o)
+	return Money{Amount: m.Amount - o.Amount, Currency: m.Currency}
+}
+
+// Percent returns percent % of m rounded to the minor unit. The percentage is taken in
+// basis points (hundredths of a percent) so the arithmetic stays in integers.
+func (m Money) Percent(percent float64, mode RoundingMode) Money {
+	basisPoints := int64(math.Round(percent * 100))
+	return Money{Amount: divRound(m.Amount*basisPoints, 10000, mode), Currency: m.Currency}
+}
+
+// Decimal formats the amount in major units, e.g. "38.50"
+func (m Money) Decimal() string {
+	exp := CurrencyExponent(m.Currency)
+	if exp == 0 {
+		return strconv.FormatInt(m.Amount, 10)
+	}
+
+	sign := ""
+	amount := m.Amount
+	if amount < 0 {
+		sign, amount = "-", -amount
+	}
+	unit := int64(math.Pow10(exp))
+	return fmt.Sprintf("%s%d.%0*d", sign, amount/unit, exp, amount%unit)
+}
+
+// String formats the amount with its currency, e.g. "38.50 ARS"
+func (m Money) String() string {
+	return m.Decimal() + " " + m.Currency
+}
+
+func (m Money) mustMatch(o Money) {
+	if m.Currency != o.Currency {
+		panic(fmt.Sprintf("money: cannot combine %s and %s", m.Currency, o.Currency))
+	}
+}
+
+// RoundingMode decides how amounts that fall between two minor units are rounded
+type RoundingMode string
+
+// Rounding modes
+const (
+	// RoundHalfUp rounds halves away from zero (0.125 -> 0.13)
+	RoundHalfUp RoundingMode = "half_up"
+	// RoundHalfEven rounds halves to the even minor unit (0.125 -> 0.12, 0.135 -> 0.14)
+	RoundHalfEven RoundingMode = "half_even"
+	// RoundDown truncates toward zero (0.129 -> 0.12)
+	RoundDown RoundingMode = "down"
+)
+
+// ParseRoundingMode reads a rounding mode name
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	switch mode := RoundingMode(strings.TrimSpace(s)); mode {
+	case RoundHalfUp, RoundHalfEven, RoundDown:
+		return mode, nil
+	}
+	return "", fmt.Errorf("invalid rounding mode %q (want half_up, half_even or down)", s)
+}
+
+// divRound returns n / d rounded to an integer with the given mode
+func divRound(n, d int64, mode RoundingMode) int64 {
+	q, r := n/d, n%d
+	if r == 0 {
+		return q
+	}
+
+	// Go truncates toward zero: stepping away from zero means q+1 for positive
+	// quotients and q-1 for negative ones
+	away := q + 1
+	if (n < 0) != (d < 0) {
+		away = q - 1
+	}
+
+	twice, divisor := 2*abs64(r), abs64(d)
+	switch mode {
+	case RoundDown:
+		return q
+	case RoundHalfEven:
+		if twice > divisor || (twice == divisor && q%2 != 0) {
+			return away
+		}
+		return q
+	default:
+		if twice >= divisor {
+			return away
+		}
+		return q
+	}
+}
+
+func abs64(v int64) int64 {
+	if v < 0 {
+		return -v
+	}
+	return v
+}
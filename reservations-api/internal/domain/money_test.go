@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"context"
+	"testing"
+)
+
+func TestMoneyPercentRounding(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  int64
+		percent float64
+		mode    RoundingMode
+		want    int64
+	}{
+		{"exact", 8000, 5, RoundHalfUp, 400},
+		{"half up", 1250, 1, RoundHalfUp, 13},
+		{"half even down", 1250, 1, RoundHalfEven, 12},
+		{"half even up", 1350, 1, RoundHalfEven, 14},
+		{"down", 1299, 1, RoundDown, 12},
+		{"below half", 1249, 1, RoundHalfUp, 12},
+		{"fractional percent", 10000, 12.5, RoundHalfUp, 1250},
+		{"negative half up", -1250, 1, RoundHalfUp, -13},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NewMoney(tt.amount, DefaultCurrency).Percent(tt.percent, tt.mode)
+			if got.Amount != tt.want || got.Currency != DefaultCurrency {
+				t.Errorf("Percent = %v, want %d %s", got, tt.want, DefaultCurrency)
+			}
+		})
+	}
+}
+
+func TestMoneyDecimal(t *testing.T) {
+	tests := []struct {
+		money Money
+		want  string
+	}{
+		{NewMoney(3850, "ARS"), "38.50"},
+		{NewMoney(5, "ARS"), "0.05"},
+		{NewMoney(-120, "ARS"), "-1.20"},
+		{NewMoney(3850, "CLP"), "3850"},
+		{NewMoney(1005, "KWD"), "1.005"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Decimal(); got != tt.want {
+			t.Errorf("%d %s Decimal() = %q, want %q", tt.money.Amount, tt.money.Currency, got, tt.want)
+		}
+	}
+}
+
+func TestPipelineCharges(t *testing.T) {
+	charges, err := ParseCharges("service:service:10;tax:iva:21:compound")
+	if err != nil {
+		t.Fatal(err)
+	}
+	pricing := Pricing{Currency: DefaultCurrency, Rounding: RoundHalfUp, Charges: charges}
+
+	result, err := DefaultCalculationPipeline(nil, pricing).Run(context.Background(),
+		CalculationInput{TableNumber: 1, Guests: 2, DateTime: wednesdayEvening, MealType: MealTypeDinner, OwnerID: "7"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// 80.00 - 5% weekday = 76.00, +10% service = 7.60, +21% IVA on 83.60 = 17.556 -> 17.56
+	if result.Subtotal.Amount != 7600 {
+		t.Errorf("Subtotal = %v, want 76.00", result.Subtotal)
+	}
+	if len(result.Charges) != 2 || result.Charges[0].Amount.Amount != 760 || result.Charges[1].Amount.Amount != 1756 {
+		t.Fatalf("Charges = %+v, want service 7.60 and iva 17.56", result.Charges)
+	}
+	if result.FinalPrice.Amount != 10116 {
+		t.Errorf("FinalPrice = %v, want 101.16", result.FinalPrice)
+	}
+
+	// The persisted lines always add up to the total
+	breakdown := result.Breakdown()
+	sum := breakdown.Subtotal
+	for _, line := range breakdown.Charges {
+		sum = sum.Add(line.Amount)
+	}
+	if sum != breakdown.Total || breakdown.Base.Sub(breakdown.Discount) != breakdown.Subtotal {
+		t.Errorf("breakdown does not add up: %+v", breakdown)
+	}
+}
+
+func TestParseChargesRejectsInvalidSpecs(t *testing.T) {
+	for _, spec := range []string{
+		"vat:21",
+		"fee:vat:21",
+		"tax:vat:abc",
+		"tax:vat:0",
+		"tax::21",
+		"tax:vat:21:later",
+	} {
+		if _, err := ParseCharges(spec); err == nil {
+			t.Errorf("ParseCharges(%q) accepted an invalid spec", spec)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// Prepayment states, from what was paid ahead against the total price
+const (
+	PrepaymentNone    = "none"
+	PrepaymentPartial = "partial"
+	PrepaymentPaid    = "paid"
+)
+
+// Prepayment is what the guest paid ahead of the reservation: a deposit or the full price
+type Prepayment struct {
+	Amount Money `bson:"amount" json:"amount"`
+	// Reference identifies the payment, e.g. the card terminal or transfer receipt
+	Reference string    `bson:"reference,omitempty" json:"reference,omitempty"`
+	PaidAt    time.Time `bson:"paid_at" json:"paid_at"`
+}
+
+// RecordPrepaymentRequest DTO for recording a payment taken ahead; the amount is in
+// minor units of the reservation's currency
+type RecordPrepaymentRequest struct {
+	Amount    int64  `json:"amount" binding:"required,min=1"`
+	Reference string `json:"reference,omitempty" binding:"max=100"`
+}
+
+// PrepaymentState reports whether nothing, part or all of the total price was prepaid
+func (r *Reservation) PrepaymentState() string {
+	switch {
+	case r.Prepayment == nil || r.Prepayment.Amount.IsZero():
+		return PrepaymentNone
+	case r.Prepayment.Amount.Amount < r.TotalPrice.Amount:
+		return PrepaymentPartial
+	default:
+		return PrepaymentPaid
+	}
+}
+
+// RecordPrepayment adds a payment to what the guest prepaid. Only upcoming reservations
+// take prepayments, and never beyond the total price.
+func (r *Reservation) RecordPrepayment(req RecordPrepaymentRequest, now time.Time) error {
+	if r.Status != StatusPending && r.Status != StatusConfirmed {
+		return ConflictError("only pending or confirmed reservations take prepayments (status is %s)", r.Status)
+	}
+
+	paid := NewMoney(0, r.TotalPrice.Currency)
+	if r.Prepayment != nil {
+		paid = r.Prepayment.Amount
+	}
+	if outstanding := r.TotalPrice.Sub(paid); req.Amount > outstanding.Amount {
+		return NewValidationError("amount", "cannot exceed the outstanding "+outstanding.String())
+	}
+
+	r.Prepayment = &Prepayment{
+		Amount:    paid.Add(NewMoney(req.Amount, r.TotalPrice.Currency)),
+		Reference: strings.TrimSpace(req.Reference),
+		PaidAt:    now,
+	}
+	r.UpdatedAt = now
+	return nil
+}
@@ -0,0 +1,128 @@
+package domain
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Charge kinds
+const (
+	ChargeTax     = "tax"
+	ChargeService = "service"
+)
+
+// ChargeRule is a percentage added on top of the discounted price, such as VAT or a
+// service charge
+type ChargeRule struct {
+	Code    string
+	Kind    string
+	Percent float64
+	// Compound charges apply to the subtotal plus the charges listed before them
+	// (e.g. VAT on the service charge); the others apply to the subtotal only
+	Compound bool
+}
+
+// ChargeLine is a charge applied to a reservation's price
+type ChargeLine struct {
+	Code     string  `bson:"code" json:"code"`
+	Kind     string  `bson:"kind" json:"kind"`
+	Percent  float64 `bson:"percent" json:"percent"`
+	Compound bool    `bson:"compound,omitempty" json:"compound,omitempty"`
+	Amount   Money   `bson:"amount" json:"amount"`
+}
+
+// Pricing is the currency, rounding and charges used to price reservations
+type Pricing struct {
+	Currency string
+	Rounding RoundingMode
+	Charges  []ChargeRule
+}
+
+// DefaultPricing prices in the default currency, rounding halves up, without charges
+func DefaultPricing() Pricing {
+	return Pricing{Currency: DefaultCurrency, Rounding: RoundHalfUp}
+}
+
+// PriceBreakdown is the price of a reservation as persisted on it. Every line is rounded
+// to the minor unit on its own and the total is the exact sum of the lines:
+// Total = Subtotal + Charges, Subtotal = Base - Discount (never below zero) + ItemsTotal,
+// Discount is the sum of the discount lines and ItemsTotal the sum of the items.
+type PriceBreakdown struct {
+	Base      Money          `bson:"base" json:"base"`
+	Discounts []DiscountLine `bson:"discounts" json:"discounts"`
+	Discount  Money          `bson:"discount" json:"discount"`
+	// Items are the pre-ordered menu items (absent without a pre-order)
+	Items      []PreOrderLine `bson:"items,omitempty" json:"items,omitempty"`
+	ItemsTotal Money          `bson:"items_total,omitempty" json:"items_total"`
+	Subtotal   Money          `bson:"subtotal" json:"subtotal"`
+	Charges    []ChargeLine   `bson:"charges" json:"charges"`
+	Total      Money          `bson:"total" json:"total"`
+	Rounding   RoundingMode   `bson:"rounding" json:"rounding"`
+
+	// CalculatedAt is when the calculation pipeline ran and RulesVersion which rules
+	// (stages, promotions, charges) it applied
+	CalculatedAt time.Time `bson:"calculated_at" json:"calculated_at"`
+	RulesVersion string    `bson:"rules_version" json:"rules_version"`
+	// Trigger is what recalculated the price: create, update, confirm or pre_order
+	Trigger string `bson:"trigger" json:"trigger"`
+	// PreviousTotal is the total this calculation replaced, when it changed
+	PreviousTotal *Money `bson:"previous_total,omitempty" json:"previous_total,omitempty"`
+}
+
+// applyCharges adds the charges to a subtotal, in configuration order
+func (p Pricing) applyCharges(subtotal Money) ([]ChargeLine, Money) {
+	lines := []ChargeLine{}
+	total := subtotal
+	for _, rule := range p.Charges {
+		base := subtotal
+		if rule.Compound {
+			base = total
+		}
+		amount := base.Percent(rule.Percent, p.Rounding)
+		lines = append(lines, ChargeLine{
+			Code:     rule.Code,
+			Kind:     rule.Kind,
+			Percent:  rule.Percent,
+			Compound: rule.Compound,
+			Amount:   amount,
+		})
+		total = total.Add(amount)
+	}
+	return lines, total
+}
+
+// ParseCharges reads a spec such as "service:service:10;tax:vat:21:compound", each charge
+// being "<kind>:<code>:<percent>[:compound]" with kind tax or service, applied in order
+func ParseCharges(spec string) ([]ChargeRule, error) {
+	charges := []ChargeRule{}
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		parts := strings.Split(entry, ":")
+		if len(parts) < 3 || len(parts) > 4 || parts[1] == "" {
+			return nil, fmt.Errorf("invalid charge %q", entry)
+		}
+		if parts[0] != ChargeTax && parts[0] != ChargeService {
+			return nil, fmt.Errorf("invalid kind in charge %q", entry)
+		}
+		percent, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil || percent <= 0 || percent > 100 {
+			return nil, fmt.Errorf("invalid percent in charge %q", entry)
+		}
+		compound := false
+		if len(parts) == 4 {
+			if parts[3] != "compound" {
+				return nil, fmt.Errorf("invalid option in charge %q", entry)
+			}
+			compound = true
+		}
+
+		charges = append(charges, ChargeRule{Code: parts[1], Kind: parts[0], Percent: percent, Compound: compound})
+	}
+	return charges, nil
+}
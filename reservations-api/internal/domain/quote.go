@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// Quote is the price of a prospective reservation, computed without booking it
+type Quote struct {
+	TableNumber   int            `json:"table_number"`
+	Guests        int            `json:"guests"`
+	DateTime      time.Time      `json:"date_time"`
+	MealType      string         `json:"meal_type"`
+	Available     bool           `json:"available"`
+	BasePrice     Money          `json:"base_price"`
+	Discounts     []DiscountLine `json:"discounts"`
+	DiscountTotal Money          `json:"discount_total"`
+	Subtotal      Money          `json:"subtotal"`
+	Charges       []ChargeLine   `json:"charges"`
+	FinalPrice    Money          `json:"final_price"`
+	RulesVersion  string         `json:"rules_version"`
+	// Restrictions explain why the requested table cannot be booked
+	Restrictions []string `json:"restrictions"`
+	// Alternatives are free tables for the same date and meal type that seat the party,
+	// smallest first
+	Alternatives       []TableConfig       `json:"alternatives"`
+	CancellationPolicy *CancellationPolicy `json:"cancellation_policy,omitempty"`
+}
@@ -1,7 +1,6 @@
 package domain
 
 import (
-	"errors"
 	"time"
 
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -11,10 +10,50 @@ import (
 const (
 	StatusPending   = "pending"
 	StatusConfirmed = "confirmed"
+	StatusSeated    = "seated"
 	StatusCancelled = "cancelled"
 	StatusCompleted = "completed"
+	StatusNoShow    = "no_show"
 )
 
+// statusTransitions lists the statuses each status can move to. Seating goes through
+// check-in (or a walk-in) and completion through the host stand, never a plain update;
+// cancelled, completed and no-show reservations are final.
+var statusTransitions = map[string][]string{
+	StatusPending:   {StatusConfirmed, StatusSeated, StatusCancelled, StatusNoShow},
+	StatusConfirmed: {StatusPending, StatusSeated, StatusCancelled, StatusNoShow},
+	StatusSeated:    {StatusCompleted},
+}
+
+// CanTransition reports whether a reservation may move from one status to another
+func CanTransition(from, to string) bool {
+	for _, next := range statusTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
+
+// checkTransition returns a conflict when the reservation cannot move to status
+func (r *Reservation) checkTransition(status string) error {
+	if !CanTransition(r.Status, status) {
+		return ConflictError("a %s reservation cannot become %s", r.Status, status)
+	}
+	return nil
+}
+
+// SetStatus moves the reservation to a status an update may set (pending, confirmed or
+// no_show); cancelling goes through Cancel, which charges the cancellation fee
+func (r *Reservation) SetStatus(status string, now time.Time) error {
+	if err := r.checkTransition(status); err != nil {
+		return err
+	}
+	r.Status = status
+	r.UpdatedAt = now
+	return nil
+}
+
 // Meal types
 const (
 	MealTypeBreakfast = "breakfast"
@@ -32,10 +71,47 @@ type Reservation struct {
 	DateTime        time.Time          `bson:"date_time" json:"date_time"`
 	MealType        string             `bson:"meal_type" json:"meal_type"`
 	Status          string             `bson:"status" json:"status"`
-	TotalPrice      float64            `bson:"total_price" json:"total_price"`
+	TotalPrice      Money              `bson:"total_price" json:"total_price"`
 	SpecialRequests string             `bson:"special_requests,omitempty" json:"special_requests,omitempty"`
 	CreatedAt       time.Time          `bson:"created_at" json:"created_at"`
 	UpdatedAt       time.Time          `bson:"updated_at" json:"updated_at"`
+	// Revision counts the updates of the reservation; the repository increments it
+	Revision int64 `bson:"revision,omitempty" json:"revision"`
+
+	// Structured requests, from controlled vocabularies. Not omitted when empty, so
+	// clearing one overwrites the stored value.
+	Allergies     []string           `bson:"allergies" json:"allergies,omitempty"`
+	Dietary       []string           `bson:"dietary" json:"dietary,omitempty"`
+	Accessibility AccessibilityNeeds `bson:"accessibility" json:"accessibility"`
+	Occasion      string             `bson:"occasion" json:"occasion,omitempty"`
+
+	// Cancellation policy agreed at booking time and the outcome of a cancellation
+	CancellationPolicy *CancellationPolicy `bson:"cancellation_policy,omitempty" json:"cancellation_policy,omitempty"`
+	CancellationFee    *Money              `bson:"cancellation_fee,omitempty" json:"cancellation_fee,omitempty"`
+	CancellationReason string              `bson:"cancellation_reason,omitempty" json:"cancellation_reason,omitempty"`
+	CancelledAt        *time.Time          `bson:"cancelled_at,omitempty" json:"cancelled_at,omitempty"`
+
+	// Price is how TotalPrice was calculated (absent on reservations priced before it existed)
+	Price *PriceBreakdown `bson:"price,omitempty" json:"price,omitempty"`
+
+	// Menu items ordered ahead, editable until the pre-order cutoff. Not omitted when
+	// empty, so clearing the pre-order overwrites the stored one.
+	PreOrder []PreOrderLine `bson:"pre_order" json:"pre_order,omitempty"`
+
+	// When the guests checked in at the host stand, and when they left
+	SeatedAt    *time.Time `bson:"seated_at,omitempty" json:"seated_at,omitempty"`
+	CompletedAt *time.Time `bson:"completed_at,omitempty" json:"completed_at,omitempty"`
+
+	// Walk-ins are seated by the host without a reservation or a user account; they
+	// have no owner and only a name, if the guests gave one
+	WalkIn    bool   `bson:"walk_in,omitempty" json:"walk_in,omitempty"`
+	GuestName string `bson:"guest_name,omitempty" json:"guest_name,omitempty"`
+
+	// What the guest paid ahead, recorded by staff
+	Prepayment *Prepayment `bson:"prepayment,omitempty" json:"prepayment,omitempty"`
+
+	// Set on reservations read from the archive by the retention job
+	ArchivedAt *time.Time `bson:"archived_at,omitempty" json:"archived_at,omitempty"`
 }
 
 // CreateReservationRequest DTO for creating a reservation
@@ -46,6 +122,14 @@ type CreateReservationRequest struct {
 	DateTime        time.Time `json:"date_time" binding:"required"`
 	MealType        string    `json:"meal_type" binding:"required,oneof=breakfast lunch dinner event"`
 	SpecialRequests string    `json:"special_requests,omitempty"`
+
+	Allergies     []string           `json:"allergies,omitempty"`
+	Dietary       []string           `json:"dietary,omitempty"`
+	Accessibility AccessibilityNeeds `json:"accessibility"`
+	Occasion      string             `json:"occasion,omitempty"`
+
+	// HoldToken books a table held during checkout (POST /api/holds)
+	HoldToken string `json:"hold_token,omitempty"`
 }
 
 // UpdateReservationRequest DTO for updating a reservation
@@ -55,7 +139,29 @@ type UpdateReservationRequest struct {
 	DateTime        *time.Time `json:"date_time,omitempty"`
 	MealType        *string    `json:"meal_type,omitempty" binding:"omitempty,oneof=breakfast lunch dinner event"`
 	SpecialRequests *string    `json:"special_requests,omitempty"`
-	Status          *string    `json:"status,omitempty" binding:"omitempty,oneof=pending confirmed cancelled completed"`
+	Status          *string    `json:"status,omitempty" binding:"omitempty,oneof=pending confirmed cancelled no_show"`
+
+	Allergies     *[]string           `json:"allergies,omitempty"`
+	Dietary       *[]string           `json:"dietary,omitempty"`
+	Accessibility *AccessibilityNeeds `json:"accessibility,omitempty"`
+	Occasion      *string             `json:"occasion,omitempty"`
+}
+
+// ReservationFilter narrows reservation listings and exports (zero values are ignored)
+type ReservationFilter struct {
+	OwnerID     string
+	MealType    string
+	TableNumber int
+	Status      string
+	From        time.Time // inclusive
+	To          time.Time // exclusive
+
+	// Structured requests: reservations listing the allergy, dietary tag or occasion,
+	// or needing wheelchair access
+	Allergy    string
+	Dietary    string
+	Occasion   string
+	Wheelchair bool
 }
 
 // ConfirmReservationRequest DTO for confirming a reservation
@@ -63,27 +169,72 @@ type ConfirmReservationRequest struct {
 	ConfirmationNotes string `json:"confirmation_notes,omitempty"`
 }
 
-// Validate checks if the reservation data is valid
+// Validate checks if the reservation data is valid for booking
 func (r *Reservation) Validate() error {
-	if r.OwnerID == "" {
-		return errors.New("owner_id is required")
+	return r.validate(true)
+}
+
+// ValidateUpdate checks an updated reservation. Its time only has to be in the future
+// when the update rescheduled it.
+func (r *Reservation) ValidateUpdate(rescheduled bool) error {
+	return r.validate(rescheduled)
+}
+
+func (r *Reservation) validate(future bool) error {
+	verr := &ValidationError{}
+	if r.OwnerID == "" && !r.WalkIn {
+		verr.Add("owner_id", "is required")
 	}
 	if r.TableNumber < 1 {
-		return errors.New("table_number must be positive")
+		verr.Add("table_number", "must be positive")
 	}
 	if r.Guests < 1 || r.Guests > 20 {
-		return errors.New("guests must be between 1 and 20")
+		verr.Add("guests", "must be between 1 and 20")
 	}
-	if r.DateTime.Before(time.Now()) {
-		return errors.New("date_time must be in the future")
+	// Walk-ins are booked as they sit down
+	if future && r.DateTime.Before(time.Now()) && !r.WalkIn {
+		verr.Add("date_time", "must be in the future")
 	}
 	if !isValidMealType(r.MealType) {
-		return errors.New("invalid meal_type")
+		verr.Add("meal_type", "is invalid")
 	}
 	if !isValidStatus(r.Status) {
-		return errors.New("invalid status")
+		verr.Add("status", "is invalid")
 	}
-	return nil
+	r.validateRequests(verr)
+	return verr.OrNil()
+}
+
+// ExpectedDuration returns how long the table is expected to be used for the reservation's meal type
+func (r *Reservation) ExpectedDuration() time.Duration {
+	switch r.MealType {
+	case MealTypeBreakfast:
+		return time.Hour
+	case MealTypeLunch:
+		return 90 * time.Minute
+	case MealTypeDinner:
+		return 2 * time.Hour
+	case MealTypeEvent:
+		return 4 * time.Hour
+	}
+	return 2 * time.Hour
+}
+
+// TableSlot is the table a reservation takes for a meal on a day
+type TableSlot struct {
+	TableNumber int       `json:"table_number"`
+	MealType    string    `json:"meal_type"`
+	DateTime    time.Time `json:"date_time"`
+}
+
+// Slot returns the table, meal type and day the reservation is booked for
+func (r *Reservation) Slot() TableSlot {
+	return TableSlot{TableNumber: r.TableNumber, MealType: r.MealType, DateTime: r.DateTime}
+}
+
+// Key identifies the slot the way HoldSlot does
+func (s TableSlot) Key() string {
+	return HoldSlot(s.DateTime, s.MealType, s.TableNumber)
 }
 
 func isValidMealType(mt string) bool {
@@ -96,7 +247,7 @@ func isValidMealType(mt string) bool {
 
 func isValidStatus(s string) bool {
 	switch s {
-	case StatusPending, StatusConfirmed, StatusCancelled, StatusCompleted:
+	case StatusPending, StatusConfirmed, StatusSeated, StatusCancelled, StatusCompleted, StatusNoShow:
 		return true
 	}
 	return false
@@ -112,8 +263,12 @@ func NewReservation(req CreateReservationRequest) Reservation {
 		DateTime:        req.DateTime,
 		MealType:        req.MealType,
 		Status:          StatusPending,
-		TotalPrice:      0, // will be calculated
+		TotalPrice:      NewMoney(0, DefaultCurrency), // will be calculated
 		SpecialRequests: req.SpecialRequests,
+		Allergies:       normalizeTags(req.Allergies),
+		Dietary:         normalizeTags(req.Dietary),
+		Accessibility:   req.Accessibility,
+		Occasion:        req.Occasion,
 		CreatedAt:       now,
 		UpdatedAt:       now,
 	}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestCanTransition(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{StatusPending, StatusConfirmed, true},
+		{StatusPending, StatusCancelled, true},
+		{StatusConfirmed, StatusPending, true},
+		{StatusConfirmed, StatusSeated, true},
+		{StatusConfirmed, StatusNoShow, true},
+		{StatusSeated, StatusCompleted, true},
+		{StatusPending, StatusCompleted, false},
+		{StatusSeated, StatusConfirmed, false},
+		{StatusSeated, StatusCancelled, false},
+		{StatusSeated, StatusNoShow, false},
+		{StatusCompleted, StatusConfirmed, false},
+		{StatusNoShow, StatusConfirmed, false},
+		{StatusNoShow, StatusCancelled, false},
+		{StatusCancelled, StatusPending, false},
+		{StatusCancelled, StatusConfirmed, false},
+	}
+	for _, tt := range tests {
+		if got := CanTransition(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransition(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestSetStatus(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+
+	r := &Reservation{Status: StatusConfirmed}
+	if err := r.SetStatus(StatusNoShow, now); err != nil || r.Status != StatusNoShow || !r.UpdatedAt.Equal(now) {
+		t.Errorf("SetStatus(no_show) = %v, reservation %+v", err, r)
+	}
+	if err := r.SetStatus(StatusConfirmed, now); !errors.Is(err, ErrConflict) || r.Status != StatusNoShow {
+		t.Errorf("SetStatus(confirmed) of a no-show = %v, status %s, want a conflict", err, r.Status)
+	}
+}
+
+func TestCancelOnlyExpectedReservations(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	policy := DefaultCancellationPolicies()[MealTypeDinner]
+
+	for _, status := range []string{StatusSeated, StatusNoShow, StatusCompleted} {
+		r := &Reservation{Status: status, DateTime: now.Add(time.Hour), TotalPrice: NewMoney(5000, DefaultCurrency)}
+		if err := r.Cancel(policy, "", now); !errors.Is(err, ErrConflict) || r.Status != status || r.CancellationFee != nil {
+			t.Errorf("Cancel() of a %s reservation = %v, reservation %+v, want a conflict", status, err, r)
+		}
+	}
+}
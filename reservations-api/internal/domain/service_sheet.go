@@ -0,0 +1,143 @@
+package domain
+
+import (
+	"sort"
+	"time"
+)
+
+// UnknownGuestName stands for guests the Users API no longer knows
+const UnknownGuestName = "Sin datos del cliente"
+
+// SheetGuest is who the host stand expects for a reservation, from the Users API
+type SheetGuest struct {
+	Name  string
+	Phone string
+}
+
+// ServiceSheetEntry is one reservation as the front of house sees it
+type ServiceSheetEntry struct {
+	ReservationID   string             `json:"reservation_id"`
+	Time            string             `json:"time"`
+	TableNumber     int                `json:"table_number"`
+	Guests          int                `json:"guests"`
+	Status          string             `json:"status"`
+	WalkIn          bool               `json:"walk_in,omitempty"`
+	GuestName       string             `json:"guest_name"`
+	Phone           string             `json:"phone,omitempty"`
+	Allergies       []string           `json:"allergies,omitempty"`
+	Dietary         []string           `json:"dietary,omitempty"`
+	Accessibility   AccessibilityNeeds `json:"accessibility"`
+	Occasion        string             `json:"occasion,omitempty"`
+	SpecialRequests string             `json:"special_requests,omitempty"`
+	PreOrderItems   int                `json:"pre_order_items,omitempty"`
+	TotalPrice      Money              `json:"total_price"`
+	Prepayment      string             `json:"prepayment"`
+	Prepaid         *Money             `json:"prepaid,omitempty"`
+}
+
+// ServiceSheetSlot groups the reservations booked at the same time
+type ServiceSheetSlot struct {
+	Time    string              `json:"time"`
+	Guests  int                 `json:"guests"`
+	Entries []ServiceSheetEntry `json:"entries"`
+}
+
+// ServiceSheetMeal is one service of the day
+type ServiceSheetMeal struct {
+	MealType     string             `json:"meal_type"`
+	Reservations int                `json:"reservations"`
+	Guests       int                `json:"guests"`
+	Slots        []ServiceSheetSlot `json:"slots"`
+}
+
+// ServiceSheet is a day's service for the host stand, by meal type and time
+type ServiceSheet struct {
+	Date         string             `json:"date"`
+	GeneratedAt  time.Time          `json:"generated_at"`
+	Reservations int                `json:"reservations"`
+	Guests       int                `json:"guests"`
+	Meals        []ServiceSheetMeal `json:"meals"`
+	// GuestsUnavailable is set when the Users API could not be reached for names and phones
+	GuestsUnavailable bool `json:"guests_unavailable,omitempty"`
+}
+
+// NewServiceSheet groups a day's reservations by meal type, in service order, then by
+// booked time in loc. Cancelled reservations are left out. Owners missing from guests
+// are shown as UnknownGuestName, and walk-ins by the name they gave.
+func NewServiceSheet(date string, reservations []Reservation, guests map[string]SheetGuest, loc *time.Location, now time.Time) *ServiceSheet {
+	sorted := make([]Reservation, 0, len(reservations))
+	for _, r := range reservations {
+		if r.Status != StatusCancelled {
+			sorted = append(sorted, r)
+		}
+	}
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if mi, mj := MealOrder(sorted[i].MealType), MealOrder(sorted[j].MealType); mi != mj {
+			return mi < mj
+		}
+		if !sorted[i].DateTime.Equal(sorted[j].DateTime) {
+			return sorted[i].DateTime.Before(sorted[j].DateTime)
+		}
+		return sorted[i].TableNumber < sorted[j].TableNumber
+	})
+
+	sheet := &ServiceSheet{Date: date, GeneratedAt: now, Meals: []ServiceSheetMeal{}}
+	for _, r := range sorted {
+		entry := newServiceSheetEntry(r, guests, loc)
+
+		if n := len(sheet.Meals); n == 0 || sheet.Meals[n-1].MealType != r.MealType {
+			sheet.Meals = append(sheet.Meals, ServiceSheetMeal{MealType: r.MealType})
+		}
+		meal := &sheet.Meals[len(sheet.Meals)-1]
+		if n := len(meal.Slots); n == 0 || meal.Slots[n-1].Time != entry.Time {
+			meal.Slots = append(meal.Slots, ServiceSheetSlot{Time: entry.Time})
+		}
+		slot := &meal.Slots[len(meal.Slots)-1]
+
+		slot.Entries = append(slot.Entries, entry)
+		slot.Guests += r.Guests
+		meal.Reservations++
+		meal.Guests += r.Guests
+		sheet.Reservations++
+		sheet.Guests += r.Guests
+	}
+	return sheet
+}
+
+func newServiceSheetEntry(r Reservation, guests map[string]SheetGuest, loc *time.Location) ServiceSheetEntry {
+	guest, ok := guests[r.OwnerID]
+	switch {
+	case r.WalkIn:
+		guest = SheetGuest{Name: r.GuestName}
+		if guest.Name == "" {
+			guest.Name = WalkInGuestName
+		}
+	case !ok || guest.Name == "":
+		guest.Name = UnknownGuestName
+	}
+
+	entry := ServiceSheetEntry{
+		ReservationID:   r.ID.Hex(),
+		Time:            r.DateTime.In(loc).Format("15:04"),
+		TableNumber:     r.TableNumber,
+		Guests:          r.Guests,
+		Status:          r.Status,
+		WalkIn:          r.WalkIn,
+		GuestName:       guest.Name,
+		Phone:           guest.Phone,
+		Allergies:       r.Allergies,
+		Dietary:         r.Dietary,
+		Accessibility:   r.Accessibility,
+		Occasion:        r.Occasion,
+		SpecialRequests: r.SpecialRequests,
+		TotalPrice:      r.TotalPrice,
+		Prepayment:      r.PrepaymentState(),
+	}
+	for _, line := range r.PreOrder {
+		entry.PreOrderItems += line.Quantity
+	}
+	if r.Prepayment != nil {
+		entry.Prepaid = &r.Prepayment.Amount
+	}
+	return entry
+}
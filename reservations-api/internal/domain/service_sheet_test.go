@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestNewServiceSheetGroupsByMealAndTime(t *testing.T) {
+	day := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	at := func(hour, minute int) time.Time {
+		return day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+	}
+	reservation := func(owner string, mealType string, dateTime time.Time, table, guests int, status string) Reservation {
+		return Reservation{
+			ID: primitive.NewObjectID(), OwnerID: owner, MealType: mealType, DateTime: dateTime,
+			TableNumber: table, Guests: guests, Status: status, TotalPrice: NewMoney(10000, "ARS"),
+		}
+	}
+
+	paid := reservation("7", MealTypeDinner, at(21, 0), 3, 2, StatusConfirmed)
+	paid.Prepayment = &Prepayment{Amount: NewMoney(10000, "ARS")}
+	paid.Allergies = []string{"peanuts"}
+	reservations := []Reservation{
+		paid,
+		reservation("8", MealTypeDinner, at(21, 0), 1, 4, StatusPending),
+		reservation("9", MealTypeDinner, at(20, 30), 5, 2, StatusConfirmed),
+		reservation("7", MealTypeLunch, at(13, 0), 2, 3, StatusSeated),
+		reservation("8", MealTypeDinner, at(22, 0), 6, 2, StatusCancelled),
+	}
+	guests := map[string]SheetGuest{"7": {Name: "Ana Gómez", Phone: "+54 11 5555-0000"}, "8": {Name: "Juan"}}
+
+	sheet := NewServiceSheet("2030-03-01", reservations, guests, time.UTC, day)
+
+	if sheet.Reservations != 4 || sheet.Guests != 11 {
+		t.Errorf("totals = %d reservations, %d guests, want 4 and 11 without the cancellation", sheet.Reservations, sheet.Guests)
+	}
+	if len(sheet.Meals) != 2 || sheet.Meals[0].MealType != MealTypeLunch || sheet.Meals[1].MealType != MealTypeDinner {
+		t.Fatalf("meals = %+v, want lunch then dinner", sheet.Meals)
+	}
+
+	dinner := sheet.Meals[1]
+	if len(dinner.Slots) != 2 || dinner.Slots[0].Time != "20:30" || dinner.Slots[1].Time != "21:00" || dinner.Slots[1].Guests != 6 {
+		t.Fatalf("dinner slots = %+v, want 20:30 and 21:00 with 6 guests", dinner.Slots)
+	}
+	first, second := dinner.Slots[1].Entries[0], dinner.Slots[1].Entries[1]
+	if first.TableNumber != 1 || second.TableNumber != 3 {
+		t.Errorf("21:00 tables = %d, %d, want them in table order", first.TableNumber, second.TableNumber)
+	}
+	if second.GuestName != "Ana Gómez" || second.Phone == "" || second.Prepayment != PrepaymentPaid || len(second.Allergies) != 1 {
+		t.Errorf("entry = %+v, want Ana's details, allergies and prepayment", second)
+	}
+	if unknown := dinner.Slots[0].Entries[0]; unknown.GuestName != UnknownGuestName || unknown.Prepayment != PrepaymentNone {
+		t.Errorf("entry of an unknown owner = %+v", unknown)
+	}
+}
+
+func TestNewServiceSheetShowsWalkIns(t *testing.T) {
+	at := time.Date(2030, time.March, 1, 20, 0, 0, 0, time.UTC)
+	reservations := []Reservation{
+		{MealType: MealTypeDinner, DateTime: at, TableNumber: 1, Guests: 2, Status: StatusSeated, WalkIn: true, GuestName: "Pérez"},
+		{MealType: MealTypeDinner, DateTime: at, TableNumber: 2, Guests: 3, Status: StatusSeated, WalkIn: true},
+	}
+
+	sheet := NewServiceSheet("2030-03-01", reservations, map[string]SheetGuest{"": {Name: "Nadie"}}, time.UTC, at)
+
+	entries := sheet.Meals[0].Slots[0].Entries
+	if entries[0].GuestName != "Pérez" || !entries[0].WalkIn || entries[1].GuestName != WalkInGuestName {
+		t.Errorf("walk-in names = %q, %q, want the given name and %q", entries[0].GuestName, entries[1].GuestName, WalkInGuestName)
+	}
+}
+
+func TestRecordPrepayment(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	r := &Reservation{Status: StatusConfirmed, TotalPrice: NewMoney(10000, "ARS")}
+
+	if err := r.RecordPrepayment(RecordPrepaymentRequest{Amount: 3000, Reference: " POS 12 "}, now); err != nil {
+		t.Fatalf("deposit: error = %v", err)
+	}
+	if r.PrepaymentState() != PrepaymentPartial || r.Prepayment.Reference != "POS 12" {
+		t.Errorf("after the deposit: state = %s, prepayment = %+v", r.PrepaymentState(), r.Prepayment)
+	}
+
+	var verr *ValidationError
+	if err := r.RecordPrepayment(RecordPrepaymentRequest{Amount: 7001}, now); !errors.As(err, &verr) {
+		t.Errorf("overpayment: error = %v, want a validation error", err)
+	}
+	if err := r.RecordPrepayment(RecordPrepaymentRequest{Amount: 7000}, now); err != nil || r.PrepaymentState() != PrepaymentPaid {
+		t.Errorf("balance: error = %v, state = %s, want paid", err, r.PrepaymentState())
+	}
+
+	r.Status = StatusCancelled
+	if err := r.RecordPrepayment(RecordPrepaymentRequest{Amount: 1}, now); !errors.Is(err, ErrConflict) {
+		t.Errorf("cancelled: error = %v, want a conflict", err)
+	}
+}
@@ -5,8 +5,14 @@ type TableConfig struct {
 	TableNumber int    `json:"table_number"`
 	Capacity    int    `json:"capacity"`
 	MealType    string `json:"meal_type"`
+	// Accessible tables have step-free access and room for a wheelchair
+	Accessible bool `json:"accessible"`
 }
 
+// accessibleTables are the table numbers with step-free access; the event layout uses
+// the same floor
+var accessibleTables = map[int]bool{1: true, 3: true, 6: true, 9: true}
+
 // GetPredefinedTables returns all predefined tables for each meal type
 func GetPredefinedTables() []TableConfig {
 	tables := []TableConfig{}
@@ -18,6 +24,7 @@ func GetPredefinedTables() []TableConfig {
 			TableNumber: i + 1,
 			Capacity:    capacity,
 			MealType:    MealTypeBreakfast,
+			Accessible:  accessibleTables[i+1],
 		})
 	}
 
@@ -28,6 +35,7 @@ func GetPredefinedTables() []TableConfig {
 			TableNumber: i + 1,
 			Capacity:    capacity,
 			MealType:    MealTypeLunch,
+			Accessible:  accessibleTables[i+1],
 		})
 	}
 
@@ -38,6 +46,7 @@ func GetPredefinedTables() []TableConfig {
 			TableNumber: i + 1,
 			Capacity:    capacity,
 			MealType:    MealTypeDinner,
+			Accessible:  accessibleTables[i+1],
 		})
 	}
 
@@ -48,6 +57,7 @@ func GetPredefinedTables() []TableConfig {
 			TableNumber: i + 1,
 			Capacity:    capacity,
 			MealType:    MealTypeEvent,
+			Accessible:  accessibleTables[i+1],
 		})
 	}
 
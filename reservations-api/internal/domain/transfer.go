@@ -0,0 +1,33 @@
+package domain
+
+// Import row outcomes
+const (
+	ImportRowCreated = "created"
+	ImportRowValid   = "valid" // dry-run: the row would be created
+	ImportRowFailed  = "failed"
+)
+
+// ImportRow is one parsed row of a bulk import file
+type ImportRow struct {
+	Line    int
+	Request CreateReservationRequest
+	// Error is set when the row could not be parsed or fails request validation
+	Error string
+}
+
+// ImportRowResult reports what happened to a single import row
+type ImportRowResult struct {
+	Line          int    `json:"line"`
+	Status        string `json:"status"`
+	ReservationID string `json:"reservation_id,omitempty"`
+	Error         string `json:"error,omitempty"`
+}
+
+// ImportReport summarizes a bulk import
+type ImportReport struct {
+	DryRun    bool              `json:"dry_run"`
+	Total     int               `json:"total"`
+	Succeeded int               `json:"succeeded"`
+	Failed    int               `json:"failed"`
+	Rows      []ImportRowResult `json:"rows"`
+}
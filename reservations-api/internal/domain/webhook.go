@@ -0,0 +1,129 @@
+package domain
+
+import (
+	"strings"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Webhook event types, named after the RabbitMQ routing keys of the same lifecycle events
+const (
+	WebhookEventCreate  = "reservation.create"
+	WebhookEventUpdate  = "reservation.update"
+	WebhookEventCancel  = "reservation.cancel"
+	WebhookEventConfirm = "reservation.confirm"
+	WebhookEventCheckIn = "reservation.checkin"
+	// WebhookEventComplete is sent when seated guests leave their table
+	WebhookEventComplete = "reservation.complete"
+	// WebhookEventAll subscribes to every event type
+	WebhookEventAll = "*"
+)
+
+// WebhookEvents lists every event type a subscription can ask for
+var WebhookEvents = []string{WebhookEventCreate, WebhookEventUpdate, WebhookEventCancel, WebhookEventConfirm, WebhookEventCheckIn, WebhookEventComplete}
+
+// Webhook delivery statuses
+const (
+	DeliveryPending    = "pending"    // waiting for NextAttemptAt
+	DeliveryDelivering = "delivering" // claimed by the dispatcher
+	DeliveryDelivered  = "delivered"  // the endpoint answered 2xx
+	DeliveryFailed     = "failed"     // gave up after MaxAttempts
+)
+
+// WebhookSubscription is a partner endpoint that receives reservation events
+type WebhookSubscription struct {
+	ID          primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	URL         string             `bson:"url" json:"url"`
+	Events      []string           `bson:"events" json:"events"`
+	Description string             `bson:"description,omitempty" json:"description,omitempty"`
+	// Secret signs the deliveries; it is only returned when the subscription is created
+	Secret    string    `bson:"secret" json:"-"`
+	CreatedAt time.Time `bson:"created_at" json:"created_at"`
+}
+
+// Subscribes reports whether the subscription wants an event type
+func (s *WebhookSubscription) Subscribes(event string) bool {
+	for _, e := range s.Events {
+		if e == event || e == WebhookEventAll {
+			return true
+		}
+	}
+	return false
+}
+
+// CreateWebhookRequest DTO for registering a webhook subscription
+type CreateWebhookRequest struct {
+	URL    string   `json:"url" binding:"required,url"`
+	Events []string `json:"events" binding:"required,min=1"`
+	// Secret is generated when omitted
+	Secret      string `json:"secret" binding:"omitempty,min=16"`
+	Description string `json:"description" binding:"max=200"`
+}
+
+// Validate checks the event types and the endpoint scheme
+func (r CreateWebhookRequest) Validate() error {
+	verr := &ValidationError{}
+	if !strings.HasPrefix(r.URL, "https://") && !strings.HasPrefix(r.URL, "http://") {
+		verr.Add("url", "must be an http(s) URL")
+	}
+	for _, e := range r.Events {
+		if !isWebhookEvent(e) {
+			verr.Add("events", "must only contain "+strings.Join(WebhookEvents, ", ")+" or *")
+			break
+		}
+	}
+	return verr.OrNil()
+}
+
+func isWebhookEvent(event string) bool {
+	if event == WebhookEventAll {
+		return true
+	}
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookPayload is the JSON body POSTed to subscribers
+type WebhookPayload struct {
+	DeliveryID string       `json:"delivery_id"`
+	Event      string       `json:"event"`
+	OccurredAt time.Time    `json:"occurred_at"`
+	Data       *Reservation `json:"data"`
+}
+
+// WebhookAttempt is the outcome of one POST to a subscriber
+type WebhookAttempt struct {
+	At         time.Time `bson:"at" json:"at"`
+	StatusCode int       `bson:"status_code,omitempty" json:"status_code,omitempty"`
+	Error      string    `bson:"error,omitempty" json:"error,omitempty"`
+	DurationMs int64     `bson:"duration_ms" json:"duration_ms"`
+}
+
+// Succeeded reports whether the subscriber acknowledged the delivery
+func (a WebhookAttempt) Succeeded() bool {
+	return a.Error == "" && a.StatusCode >= 200 && a.StatusCode < 300
+}
+
+// WebhookDelivery is one event sent to one subscription, with its attempt log
+type WebhookDelivery struct {
+	ID             primitive.ObjectID `bson:"_id,omitempty" json:"id"`
+	SubscriptionID primitive.ObjectID `bson:"subscription_id" json:"subscription_id"`
+	Event          string             `bson:"event" json:"event"`
+	EntityID       string             `bson:"entity_id" json:"entity_id"`
+	// Payload is the exact body sent (and re-sent on replay)
+	Payload       string              `bson:"payload" json:"payload"`
+	Status        string              `bson:"status" json:"status"`
+	MaxAttempts   int                 `bson:"max_attempts" json:"max_attempts"`
+	Attempts      []WebhookAttempt    `bson:"attempts" json:"attempts"`
+	NextAttemptAt time.Time           `bson:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil   time.Time           `bson:"locked_until,omitempty" json:"-"`
+	ReplayOf      *primitive.ObjectID `bson:"replay_of,omitempty" json:"replay_of,omitempty"`
+	DeliveredAt   *time.Time          `bson:"delivered_at,omitempty" json:"delivered_at,omitempty"`
+	CreatedAt     time.Time           `bson:"created_at" json:"created_at"`
+	UpdatedAt     time.Time           `bson:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,174 @@
+package ical
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// RFC 5545 constants
+const (
+	prodID          = "-//Restaurant Reservas//reservations-api//EN"
+	uidDomain       = "reservations-api"
+	dateTimeLayout  = "20060102T150405Z"
+	maxLineOctets   = 75
+	lineTerminator  = "\r\n"
+	foldContinuator = "\r\n "
+)
+
+// Calendar describes the VCALENDAR wrapping one or more reservations
+type Calendar struct {
+	Name string
+	// RefreshInterval hints subscribed clients how often to poll the feed (0 omits it)
+	RefreshInterval time.Duration
+}
+
+// WriteCalendar renders the reservations as an iCalendar (RFC 5545) document
+func WriteCalendar(w io.Writer, cal Calendar, reservations []domain.Reservation, now time.Time) error {
+	bw := bufio.NewWriter(w)
+	lw := &lineWriter{w: bw}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+	lw.line("METHOD:PUBLISH")
+	if cal.Name != "" {
+		lw.line("X-WR-CALNAME:" + escapeText(cal.Name))
+	}
+	if cal.RefreshInterval > 0 {
+		minutes := int(cal.RefreshInterval.Minutes())
+		lw.line(fmt.Sprintf("REFRESH-INTERVAL;VALUE=DURATION:PT%dM", minutes))
+		lw.line(fmt.Sprintf("X-PUBLISHED-TTL:PT%dM", minutes))
+	}
+
+	for _, reservation := range reservations {
+		writeEvent(lw, reservation, now)
+	}
+
+	lw.line("END:VCALENDAR")
+
+	if lw.err != nil {
+		return lw.err
+	}
+	return bw.Flush()
+}
+
+// writeEvent renders a single reservation as a VEVENT
+func writeEvent(lw *lineWriter, r domain.Reservation, now time.Time) {
+	start := r.DateTime.UTC()
+	end := start.Add(r.ExpectedDuration())
+
+	lw.line("BEGIN:VEVENT")
+	lw.line(fmt.Sprintf("UID:%s@%s", r.ID.Hex(), uidDomain))
+	lw.line("DTSTAMP:" + formatTime(now))
+	lw.line("DTSTART:" + formatTime(start))
+	lw.line("DTEND:" + formatTime(end))
+	if !r.CreatedAt.IsZero() {
+		lw.line("CREATED:" + formatTime(r.CreatedAt))
+	}
+	if !r.UpdatedAt.IsZero() {
+		lw.line("LAST-MODIFIED:" + formatTime(r.UpdatedAt))
+	}
+	lw.line(fmt.Sprintf("SEQUENCE:%d", sequence(r)))
+	lw.line("SUMMARY:" + escapeText(summary(r)))
+	lw.line("DESCRIPTION:" + escapeText(description(r)))
+	lw.line("STATUS:" + eventStatus(r.Status))
+	lw.line("TRANSP:OPAQUE")
+	lw.line("END:VEVENT")
+}
+
+// eventStatus maps a reservation status to a VEVENT STATUS value
+func eventStatus(status string) string {
+	switch status {
+	case domain.StatusCancelled:
+		return "CANCELLED"
+	case domain.StatusPending:
+		return "TENTATIVE"
+	default:
+		return "CONFIRMED"
+	}
+}
+
+// sequence grows every time the reservation is modified so calendar clients
+// replace their copy of the event (RFC 5545 section 3.8.7.4)
+func sequence(r domain.Reservation) int64 {
+	return r.Revision
+}
+
+func summary(r domain.Reservation) string {
+	mealType := r.MealType
+	if mealType != "" {
+		mealType = strings.ToUpper(mealType[:1]) + mealType[1:]
+	}
+	return fmt.Sprintf("%s reservation - table %d (%d guests)", mealType, r.TableNumber, r.Guests)
+}
+
+func description(r domain.Reservation) string {
+	lines := []string{
+		fmt.Sprintf("Reservation %s", r.ID.Hex()),
+		fmt.Sprintf("Status: %s", r.Status),
+		fmt.Sprintf("Table: %d", r.TableNumber),
+		fmt.Sprintf("Guests: %d", r.Guests),
+	}
+	if r.SpecialRequests != "" {
+		lines = append(lines, "Special requests: "+r.SpecialRequests)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatTime(t time.Time) string {
+	return t.UTC().Format(dateTimeLayout)
+}
+
+// escapeText escapes TEXT property values (RFC 5545 section 3.3.11)
+func escapeText(s string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+		"\r", `\n`,
+	)
+	return replacer.Replace(s)
+}
+
+// lineWriter writes content lines folded at 75 octets and terminated by CRLF
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+func (lw *lineWriter) line(content string) {
+	if lw.err != nil {
+		return
+	}
+	_, lw.err = lw.w.WriteString(fold(content) + lineTerminator)
+}
+
+// fold splits a content line into 75-octet chunks without breaking UTF-8 sequences
+func fold(content string) string {
+	if len(content) <= maxLineOctets {
+		return content
+	}
+
+	var b strings.Builder
+	lineLen := 0
+	for _, r := range content {
+		size := utf8.RuneLen(r)
+		if lineLen+size > maxLineOctets {
+			b.WriteString(foldContinuator)
+			// The leading space of a continuation line counts towards its length
+			lineLen = 1
+		}
+		b.WriteRune(r)
+		lineLen += size
+	}
+	return b.String()
+}
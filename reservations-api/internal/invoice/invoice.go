@@ -0,0 +1,162 @@
+package invoice
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/pdf"
+)
+
+// Page layout in points
+const (
+	marginLeft  = 50
+	marginRight = pdf.PageWidth - 50
+	lineHeight  = 16
+)
+
+// WritePDF renders an issued invoice as a one-page A4 PDF. Dates are shown in loc.
+func WritePDF(w io.Writer, inv domain.Invoice, loc *time.Location) error {
+	p := &pdf.Page{}
+	y := float64(pdf.PageHeight - 60)
+
+	// Issuer, with the invoice number and date on the right
+	p.Text(marginLeft, y, 18, true, inv.Restaurant.Name)
+	p.TextRight(marginRight, y, 16, true, "FACTURA")
+	y -= 18
+	p.TextRight(marginRight, y, 10, false, "N° "+inv.Number)
+	p.TextRight(marginRight, y-14, 10, false, "Fecha: "+inv.IssuedAt.In(loc).Format("02/01/2006"))
+	for _, line := range issuerLines(inv.Restaurant) {
+		p.Text(marginLeft, y, 9, false, line)
+		y -= 12
+	}
+
+	// Customer and reservation
+	y -= 20
+	p.Rule(marginLeft, marginRight, y+10, 0.5)
+	y -= 8
+	p.Text(marginLeft, y, 10, true, "Cliente")
+	p.Text(pdf.PageWidth/2, y, 10, true, "Reserva")
+	y -= lineHeight
+	p.Text(marginLeft, y, 10, false, inv.Customer.Name)
+	p.Text(pdf.PageWidth/2, y, 10, false, fmt.Sprintf("%s, %s", capitalize(mealLabel(inv.MealType)), inv.DateTime.In(loc).Format("02/01/2006 15:04")))
+	y -= 14
+	if inv.Customer.Email != "" {
+		p.Text(marginLeft, y, 10, false, inv.Customer.Email)
+	}
+	p.Text(pdf.PageWidth/2, y, 10, false, fmt.Sprintf("Mesa %d, %d comensales", inv.TableNumber, inv.Guests))
+	y -= 14
+	p.Text(pdf.PageWidth/2, y, 9, false, "Ref. "+inv.ReservationID.Hex())
+
+	// Itemized price
+	y -= 36
+	p.Text(marginLeft, y, 10, true, "Concepto")
+	p.TextRight(marginRight, y, 10, true, "Importe")
+	y -= 6
+	p.Rule(marginLeft, marginRight, y, 0.5)
+	y -= lineHeight
+
+	price := inv.Price
+	item := func(label string, amount domain.Money, bold bool) {
+		p.Text(marginLeft, y, 10, bold, label)
+		p.TextRight(marginRight, y, 10, bold, formatMoney(amount))
+		y -= lineHeight
+	}
+
+	item(fmt.Sprintf("%s, %d comensales", capitalize(mealLabel(inv.MealType)), inv.Guests), price.Base, false)
+	for _, d := range price.Discounts {
+		item(fmt.Sprintf("Descuento: %s (%s%%)", discountLabel(d), formatPercent(d.Percent)), negate(d.Amount), false)
+	}
+	for _, line := range price.Items {
+		item(fmt.Sprintf("%d x %s", line.Quantity, line.Name), line.Amount, false)
+	}
+	if len(price.Discounts) > 0 || len(price.Items) > 0 || len(price.Charges) > 0 {
+		p.Rule(pdf.PageWidth/2, marginRight, y+lineHeight-4, 0.3)
+		item("Subtotal", price.Subtotal, false)
+	}
+	for _, c := range price.Charges {
+		item(fmt.Sprintf("%s %s (%s%%)", chargeLabel(c.Kind), strings.ToUpper(c.Code), formatPercent(c.Percent)), c.Amount, false)
+	}
+	p.Rule(pdf.PageWidth/2, marginRight, y+lineHeight-4, 0.8)
+	item("Total", price.Total, true)
+
+	// Footer
+	footer := "Importes en " + price.Total.Currency
+	if price.RulesVersion != "" {
+		footer += ". Reglas de precio " + price.RulesVersion
+	}
+	p.Text(marginLeft, 50, 8, false, footer)
+
+	return pdf.Write(w, "Factura "+inv.Number, p)
+}
+
+// issuerLines lists the restaurant details under its name
+func issuerLines(r domain.RestaurantDetails) []string {
+	lines := []string{}
+	if r.LegalID != "" {
+		lines = append(lines, "CUIT "+r.LegalID)
+	}
+	if r.Address != "" {
+		lines = append(lines, r.Address)
+	}
+	contact := []string{}
+	for _, c := range []string{r.Phone, r.Email} {
+		if c != "" {
+			contact = append(contact, c)
+		}
+	}
+	if len(contact) > 0 {
+		lines = append(lines, strings.Join(contact, " - "))
+	}
+	return lines
+}
+
+func formatMoney(m domain.Money) string {
+	return "$ " + m.Decimal()
+}
+
+func negate(m domain.Money) domain.Money {
+	return domain.Money{Amount: -m.Amount, Currency: m.Currency}
+}
+
+// formatPercent drops the decimals of whole percentages
+func formatPercent(v float64) string {
+	return strings.TrimSuffix(strings.TrimRight(fmt.Sprintf("%.2f", v), "0"), ".")
+}
+
+func discountLabel(d domain.DiscountLine) string {
+	if d.Description != "" {
+		return d.Description
+	}
+	return d.Code
+}
+
+func chargeLabel(kind string) string {
+	if kind == domain.ChargeTax {
+		return "Impuesto"
+	}
+	return "Servicio"
+}
+
+func mealLabel(mealType string) string {
+	switch mealType {
+	case domain.MealTypeBreakfast:
+		return "desayuno"
+	case domain.MealTypeLunch:
+		return "almuerzo"
+	case domain.MealTypeDinner:
+		return "cena"
+	case domain.MealTypeEvent:
+		return "evento"
+	}
+	return mealType
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
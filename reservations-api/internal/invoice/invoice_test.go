@@ -0,0 +1,89 @@
+package invoice
+
+import (
+	"bytes"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func sampleInvoice() domain.Invoice {
+	ars := func(amount int64) domain.Money { return domain.NewMoney(amount, "ARS") }
+	return domain.Invoice{
+		ReservationID: primitive.NewObjectID(),
+		Sequence:      42,
+		Number:        domain.FormatInvoiceNumber("A-", 42),
+		IssuedAt:      time.Date(2030, time.March, 2, 15, 0, 0, 0, time.UTC),
+		Restaurant:    domain.RestaurantDetails{Name: "La Parrilla (Centro)", LegalID: "30-12345678-9", Address: "Av. Siempreviva 742"},
+		Customer:      domain.InvoiceCustomer{UserID: "7", Name: "José Pérez", Email: "jose@example.com"},
+		DateTime:      time.Date(2030, time.March, 1, 23, 0, 0, 0, time.UTC),
+		MealType:      domain.MealTypeDinner,
+		TableNumber:   4,
+		Guests:        2,
+		Price: domain.PriceBreakdown{
+			Base:      ars(8000),
+			Discounts: []domain.DiscountLine{{Rule: domain.StageDiscount, Code: "weekday", Description: "Monday to Thursday", Percent: 5, Amount: ars(400)}},
+			Discount:  ars(400),
+			Items: []domain.PreOrderLine{{
+				MenuItemID: primitive.NewObjectID(), Name: "Provoleta", UnitPrice: ars(1200), Quantity: 2, Amount: ars(2400),
+			}},
+			ItemsTotal: ars(2400),
+			Subtotal:   ars(10000),
+			Charges:    []domain.ChargeLine{{Code: "iva", Kind: domain.ChargeTax, Percent: 21, Amount: ars(2100)}},
+			Total:      ars(12100),
+		},
+	}
+}
+
+func TestWritePDF(t *testing.T) {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		t.Skip("no zoneinfo database")
+	}
+
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, sampleInvoice(), loc); err != nil {
+		t.Fatalf("WritePDF() error = %v", err)
+	}
+	doc := buf.String()
+
+	if !strings.HasPrefix(doc, "%PDF-1.4\n") || !strings.HasSuffix(doc, "%%EOF\n") {
+		t.Fatal("not a PDF document")
+	}
+	for _, want := range []string{
+		"(N\xb0 A-00000042)",       // WinAnsi degree sign
+		`(La Parrilla \(Centro\))`, // escaped delimiters
+		"(Jos\xe9 P\xe9rez)",       // WinAnsi accents
+		"(Cena, 01/03/2030 20:00)", // shown in the restaurant's time zone
+		`(Descuento: Monday to Thursday \(5%\))`,
+		`(Impuesto IVA \(21%\))`,
+		"($ -4.00)",
+		"(2 x Provoleta)",
+		"($ 121.00)",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+
+	// Every cross-reference entry points at its object
+	xref := regexp.MustCompile(`(?m)^(\d{10}) 00000 n $`).FindAllStringSubmatch(doc, -1)
+	if len(xref) == 0 {
+		t.Fatal("no cross-reference entries")
+	}
+	for i, entry := range xref {
+		offset, _ := strconv.Atoi(entry[1])
+		if want := strconv.Itoa(i+1) + " 0 obj"; !strings.HasPrefix(doc[offset:], want) {
+			t.Errorf("xref entry %d points at %q, want %q", i+1, doc[offset:offset+len(want)], want)
+		}
+	}
+	startxref := regexp.MustCompile(`startxref\n(\d+)\n`).FindStringSubmatch(doc)
+	if offset, _ := strconv.Atoi(startxref[1]); !strings.HasPrefix(doc[offset:], "xref\n") {
+		t.Error("startxref does not point at the cross-reference table")
+	}
+}
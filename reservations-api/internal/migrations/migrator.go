@@ -0,0 +1,186 @@
+// Package migrations manages the MongoDB schema of reservations-api: indexes and
+// data backfills are versioned steps, applied in order and recorded once applied.
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collection that records the applied migrations
+const historyCollection = "schema_migrations"
+
+// Target holds what migration steps operate on
+type Target struct {
+	Database *mongo.Database
+	// Reservations is the configured reservations collection (MONGO_COLLECTION)
+	Reservations *mongo.Collection
+	// Currency the reservations are priced in (CURRENCY)
+	Currency string
+}
+
+// Migration is one versioned schema step. Up must be idempotent: replicas starting
+// together may run the same step concurrently, and a step interrupted midway is re-run.
+type Migration struct {
+	Version     int
+	Description string
+	Up          func(ctx context.Context, t Target) error
+}
+
+// Record is an applied migration
+type Record struct {
+	Version     int       `bson:"_id" json:"version"`
+	Description string    `bson:"description" json:"description"`
+	AppliedAt   time.Time `bson:"applied_at" json:"applied_at"`
+	DurationMs  int64     `bson:"duration_ms" json:"duration_ms"`
+}
+
+// Status describes a migration and whether it has been applied
+type Status struct {
+	Version     int
+	Description string
+	Applied     *Record
+}
+
+// History stores the applied migrations
+type History interface {
+	Applied(ctx context.Context) (map[int]Record, error)
+	Record(ctx context.Context, rec Record) error
+}
+
+// Migrator applies pending migrations in version order
+type Migrator struct {
+	target     Target
+	history    History
+	migrations []Migration
+	now        func() time.Time
+}
+
+// NewMigrator creates a migrator that records applied versions next to the reservations
+func NewMigrator(target Target, migrations []Migration) *Migrator {
+	return newMigrator(target, &mongoHistory{collection: target.Database.Collection(historyCollection)}, migrations)
+}
+
+func newMigrator(target Target, history History, migrations []Migration) *Migrator {
+	sorted := append([]Migration(nil), migrations...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	return &Migrator{target: target, history: history, migrations: sorted, now: time.Now}
+}
+
+// Up applies every pending migration in order and stops at the first failure
+func (m *Migrator) Up(ctx context.Context) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+
+	applied, err := m.history.Applied(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.Version]; ok {
+			continue
+		}
+
+		start := m.now()
+		log.Printf("Applying migration %d: %s", mig.Version, mig.Description)
+		if err := mig.Up(ctx, m.target); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %w", mig.Version, mig.Description, err)
+		}
+
+		finished := m.now()
+		rec := Record{
+			Version:     mig.Version,
+			Description: mig.Description,
+			AppliedAt:   finished,
+			DurationMs:  finished.Sub(start).Milliseconds(),
+		}
+		if err := m.history.Record(ctx, rec); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status lists every known migration in order with its applied record, if any
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	applied, err := m.history.Applied(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]Status, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		st := Status{Version: mig.Version, Description: mig.Description}
+		if rec, ok := applied[mig.Version]; ok {
+			st.Applied = &rec
+		}
+		statuses = append(statuses, st)
+	}
+	return statuses, nil
+}
+
+// validate rejects non-positive or duplicate versions
+func (m *Migrator) validate() error {
+	for i, mig := range m.migrations {
+		if mig.Version <= 0 {
+			return fmt.Errorf("migration %q has invalid version %d", mig.Description, mig.Version)
+		}
+		if i > 0 && m.migrations[i-1].Version == mig.Version {
+			return fmt.Errorf("duplicate migration version %d", mig.Version)
+		}
+	}
+	return nil
+}
+
+// mongoHistory implements History with one document per applied version
+type mongoHistory struct {
+	collection *mongo.Collection
+}
+
+func (h *mongoHistory) Applied(ctx context.Context) (map[int]Record, error) {
+	cursor, err := h.collection.Find(ctx, bson.M{}, options.Find().SetSort(bson.D{{Key: "_id", Value: 1}}))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read applied migrations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	var records []Record
+	if err := cursor.All(ctx, &records); err != nil {
+		return nil, fmt.Errorf("failed to decode applied migrations: %w", err)
+	}
+
+	applied := make(map[int]Record, len(records))
+	for _, rec := range records {
+		applied[rec.Version] = rec
+	}
+	return applied, nil
+}
+
+func (h *mongoHistory) Record(ctx context.Context, rec Record) error {
+	_, err := h.collection.InsertOne(ctx, rec)
+	// Another replica applied it at the same time
+	if mongo.IsDuplicateKeyError(err) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to record migration %d: %w", rec.Version, err)
+	}
+	return nil
+}
+
+// ensureIndexes creates indexes; existing identical indexes are left untouched
+func ensureIndexes(ctx context.Context, collection *mongo.Collection, models ...mongo.IndexModel) error {
+	if _, err := collection.Indexes().CreateMany(ctx, models); err != nil {
+		return fmt.Errorf("failed to create indexes on %s: %w", collection.Name(), err)
+	}
+	return nil
+}
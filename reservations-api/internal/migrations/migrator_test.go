@@ -0,0 +1,113 @@
+package migrations
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// memoryHistory is an in-memory History
+type memoryHistory struct {
+	records map[int]Record
+}
+
+func (h *memoryHistory) Applied(ctx context.Context) (map[int]Record, error) {
+	applied := make(map[int]Record, len(h.records))
+	for v, rec := range h.records {
+		applied[v] = rec
+	}
+	return applied, nil
+}
+
+func (h *memoryHistory) Record(ctx context.Context, rec Record) error {
+	h.records[rec.Version] = rec
+	return nil
+}
+
+// recorder returns migrations that log their version when applied
+func recorder(ran *[]int, versions ...int) []Migration {
+	var migs []Migration
+	for _, v := range versions {
+		v := v
+		migs = append(migs, Migration{Version: v, Description: "step", Up: func(ctx context.Context, t Target) error {
+			*ran = append(*ran, v)
+			return nil
+		}})
+	}
+	return migs
+}
+
+func TestMigratorAppliesPendingInOrderOnce(t *testing.T) {
+	var ran []int
+	history := &memoryHistory{records: map[int]Record{2: {Version: 2}}}
+	m := newMigrator(Target{}, history, recorder(&ran, 3, 1, 2, 4))
+
+	if err := m.Up(context.Background()); err != nil {
+		t.Fatalf("Up: %v", err)
+	}
+	if want := []int{1, 3, 4}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+
+	// A second run finds nothing pending
+	ran = nil
+	if err := m.Up(context.Background()); err != nil {
+		t.Fatalf("second Up: %v", err)
+	}
+	if len(ran) != 0 {
+		t.Errorf("second run applied %v, want nothing", ran)
+	}
+
+	statuses, err := m.Status(context.Background())
+	if err != nil {
+		t.Fatalf("Status: %v", err)
+	}
+	for _, st := range statuses {
+		if st.Applied == nil {
+			t.Errorf("migration %d not recorded as applied", st.Version)
+		}
+	}
+}
+
+func TestMigratorStopsAtFirstFailure(t *testing.T) {
+	var ran []int
+	boom := errors.New("boom")
+	migs := recorder(&ran, 1, 3)
+	migs = append(migs, Migration{Version: 2, Description: "broken", Up: func(ctx context.Context, t Target) error {
+		return boom
+	}})
+	history := &memoryHistory{records: map[int]Record{}}
+
+	err := newMigrator(Target{}, history, migs).Up(context.Background())
+	if !errors.Is(err, boom) {
+		t.Fatalf("err = %v, want boom", err)
+	}
+	if want := []int{1}; !reflect.DeepEqual(ran, want) {
+		t.Errorf("ran %v, want %v", ran, want)
+	}
+	if _, ok := history.records[2]; ok {
+		t.Error("failed migration was recorded as applied")
+	}
+}
+
+func TestMigratorRejectsInvalidVersions(t *testing.T) {
+	var ran []int
+	for name, versions := range map[string][]int{"duplicate": {1, 2, 2}, "zero": {0, 1}} {
+		t.Run(name, func(t *testing.T) {
+			err := newMigrator(Target{}, &memoryHistory{records: map[int]Record{}}, recorder(&ran, versions...)).Up(context.Background())
+			if err == nil {
+				t.Fatal("expected an error")
+			}
+			if len(ran) != 0 {
+				t.Errorf("ran %v before validating", ran)
+			}
+		})
+	}
+}
+
+func TestAllMigrationsHaveUniqueVersions(t *testing.T) {
+	if err := newMigrator(Target{}, nil, All()).validate(); err != nil {
+		t.Fatal(err)
+	}
+}
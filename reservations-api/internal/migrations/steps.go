@@ -0,0 +1,242 @@
+package migrations
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// All returns the schema migrations of reservations-api. Append new steps with the next
+// version; never renumber or edit a step that has shipped.
+func All() []Migration {
+	return []Migration{
+		{
+			Version:     1,
+			Description: "index reservations by owner, date, meal type and status",
+			Up: func(ctx context.Context, t Target) error {
+				return ensureIndexes(ctx, t.Reservations,
+					// A user's reservations, newest first
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("owner_id_date_time"),
+					},
+					// Table availability: one meal type over a date range, cancelled excluded
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "meal_type", Value: 1}, {Key: "date_time", Value: 1}, {Key: "status", Value: 1}},
+						Options: options.Index().SetName("meal_type_date_time_status"),
+					},
+					// Listings, exports, calendars and analytics over a date range
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("date_time"),
+					},
+					// Admin listings filtered by status
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "status", Value: 1}, {Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("status_date_time"),
+					},
+				)
+			},
+		},
+		{
+			Version:     2,
+			Description: "backfill missing reservation status and updated_at",
+			Up: func(ctx context.Context, t Target) error {
+				if _, err := t.Reservations.UpdateMany(ctx,
+					bson.M{"status": bson.M{"$exists": false}},
+					bson.M{"$set": bson.M{"status": domain.StatusPending}},
+				); err != nil {
+					return fmt.Errorf("failed to backfill status: %w", err)
+				}
+
+				// Pipeline update: copy created_at into the missing updated_at
+				if _, err := t.Reservations.UpdateMany(ctx,
+					bson.M{"updated_at": bson.M{"$exists": false}},
+					mongo.Pipeline{{{Key: "$set", Value: bson.M{"updated_at": "$created_at"}}}},
+				); err != nil {
+					return fmt.Errorf("failed to backfill updated_at: %w", err)
+				}
+				return nil
+			},
+		},
+		{
+			Version:     3,
+			Description: "index webhook subscriptions and deliveries",
+			Up: func(ctx context.Context, t Target) error {
+				subscriptions := t.Database.Collection(repository.WebhooksCollection)
+				if err := ensureIndexes(ctx, subscriptions, mongo.IndexModel{Keys: bson.D{{Key: "events", Value: 1}}}); err != nil {
+					return err
+				}
+
+				deliveries := t.Database.Collection(repository.WebhookDeliveriesCollection)
+				return ensureIndexes(ctx, deliveries,
+					// Dispatcher: the next due delivery
+					mongo.IndexModel{Keys: bson.D{{Key: "status", Value: 1}, {Key: "next_attempt_at", Value: 1}}},
+					// Delivery log of a subscription
+					mongo.IndexModel{Keys: bson.D{{Key: "subscription_id", Value: 1}, {Key: "created_at", Value: -1}}},
+				)
+			},
+		},
+		{
+			Version:     4,
+			Description: "index archived reservations by owner and date",
+			Up: func(ctx context.Context, t Target) error {
+				archive := t.Database.Collection(repository.ReservationsArchiveCollection)
+				return ensureIndexes(ctx, archive,
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "owner_id", Value: 1}, {Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("owner_id_date_time"),
+					},
+					// Analytics $unionWith over a date range
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("date_time"),
+					},
+				)
+			},
+		},
+		{
+			Version:     5,
+			Description: "one erasure receipt per user",
+			Up: func(ctx context.Context, t Target) error {
+				receipts := t.Database.Collection(repository.ErasureReceiptsCollection)
+				return ensureIndexes(ctx, receipts, mongo.IndexModel{
+					Keys:    bson.D{{Key: "user_id", Value: 1}},
+					Options: options.Index().SetName("user_id").SetUnique(true),
+				})
+			},
+		},
+		{
+			Version:     6,
+			Description: "convert float prices and cancellation fees to integer money with currency",
+			Up: func(ctx context.Context, t Target) error {
+				if !domain.IsValidCurrency(t.Currency) {
+					return fmt.Errorf("invalid currency %q", t.Currency)
+				}
+				archive := t.Database.Collection(repository.ReservationsArchiveCollection)
+				for _, coll := range []*mongo.Collection{t.Reservations, archive} {
+					for _, field := range []string{"total_price", "cancellation_fee"} {
+						// Only plain numbers are converted, so a re-run leaves converted documents alone
+						if _, err := coll.UpdateMany(ctx,
+							bson.M{field: bson.M{"$type": "number"}},
+							mongo.Pipeline{{{Key: "$set", Value: bson.M{field: toMoneyExpr("$"+field, t.Currency)}}}},
+						); err != nil {
+							return fmt.Errorf("failed to convert %s in %s: %w", field, coll.Name(), err)
+						}
+					}
+				}
+				return nil
+			},
+		},
+		{
+			Version:     7,
+			Description: "unique invoice numbers, one invoice per reservation",
+			Up: func(ctx context.Context, t Target) error {
+				invoices := t.Database.Collection(repository.InvoicesCollection)
+				return ensureIndexes(ctx, invoices,
+					// Gap-free numbering relies on the insert failing for a taken number
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "sequence", Value: 1}},
+						Options: options.Index().SetName("sequence").SetUnique(true),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "reservation_id", Value: 1}},
+						Options: options.Index().SetName("reservation_id").SetUnique(true),
+					},
+				)
+			},
+		},
+		{
+			Version:     8,
+			Description: "index the menu catalog by meal type",
+			Up: func(ctx context.Context, t Target) error {
+				items := t.Database.Collection(repository.MenuItemsCollection)
+				return ensureIndexes(ctx, items,
+					// Menu listings for one meal type, in category and name order
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "meal_types", Value: 1}, {Key: "category", Value: 1}, {Key: "name", Value: 1}},
+						Options: options.Index().SetName("meal_types_category_name"),
+					},
+				)
+			},
+		},
+		{
+			Version:     9,
+			Description: "index reservations by structured requests",
+			Up: func(ctx context.Context, t Target) error {
+				return ensureIndexes(ctx, t.Reservations,
+					// Listings of the reservations with an allergy, dietary tag or occasion
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "allergies", Value: 1}, {Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("allergies_date_time"),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "dietary", Value: 1}, {Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("dietary_date_time"),
+					},
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "occasion", Value: 1}, {Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("occasion_date_time"),
+					},
+					// Only the few reservations needing wheelchair access are indexed
+					mongo.IndexModel{
+						Keys: bson.D{{Key: "date_time", Value: -1}},
+						Options: options.Index().SetName("wheelchair_date_time").
+							SetPartialFilterExpression(bson.M{"accessibility.wheelchair": true}),
+					},
+				)
+			},
+		},
+		{
+			Version:     10,
+			Description: "one hold per table slot, purged after expiring",
+			Up: func(ctx context.Context, t Target) error {
+				holds := t.Database.Collection(repository.HoldsCollection)
+				return ensureIndexes(ctx, holds,
+					// Two guests holding the same table race on this index
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "slot", Value: 1}},
+						Options: options.Index().SetName("slot").SetUnique(true),
+					},
+					// The TTL monitor deletes holds a while after they expire; the hold reaper
+					// announces the release before that
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "expires_at", Value: 1}},
+						Options: options.Index().SetName("expires_at_ttl").SetExpireAfterSeconds(int32(domain.HoldPurgeAfter.Seconds())),
+					},
+					// Held tables when listing availability
+					mongo.IndexModel{
+						Keys:    bson.D{{Key: "date_time", Value: 1}, {Key: "meal_type", Value: 1}},
+						Options: options.Index().SetName("date_time_meal_type"),
+					},
+				)
+			},
+		},
+		{
+			Version:     11,
+			Description: "list admin bulk jobs newest first",
+			Up: func(ctx context.Context, t Target) error {
+				jobs := t.Database.Collection(repository.BulkJobsCollection)
+				return ensureIndexes(ctx, jobs, mongo.IndexModel{
+					Keys:    bson.D{{Key: "created_at", Value: -1}},
+					Options: options.Index().SetName("created_at"),
+				})
+			},
+		},
+	}
+}
+
+// toMoneyExpr converts a decimal amount in the major unit into a domain.Money document
+func toMoneyExpr(field, currency string) bson.M {
+	factor := math.Pow10(domain.CurrencyExponent(currency))
+	return bson.M{
+		"amount":   bson.M{"$toLong": bson.M{"$round": bson.A{bson.M{"$multiply": bson.A{field, factor}}, 0}}},
+		"currency": currency,
+	}
+}
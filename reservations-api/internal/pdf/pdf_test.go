@@ -0,0 +1,35 @@
+package pdf
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWriteListsEveryPage(t *testing.T) {
+	first, second := &Page{}, &Page{}
+	first.Text(50, 800, 10, true, "Uno")
+	second.Text(50, 800, 10, false, "Dos")
+
+	var buf bytes.Buffer
+	if err := Write(&buf, "Prueba", first, second); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{"/Kids [6 0 R 8 0 R] /Count 2", "/Contents 7 0 R", "/Contents 9 0 R", "(Uno)", "(Dos)"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
+
+func TestTextWidth(t *testing.T) {
+	// "Total" in Helvetica-Bold: T 611 + o 611 + t 333 + a 556 + l 278 = 2389 units
+	if got := TextWidth("Total", 10, true); got != 23.89 {
+		t.Errorf("TextWidth = %v, want 23.89", got)
+	}
+	if regular, bold := TextWidth("Factura", 10, false), TextWidth("Factura", 10, true); regular >= bold {
+		t.Errorf("bold text (%v) should be wider than regular (%v)", bold, regular)
+	}
+}
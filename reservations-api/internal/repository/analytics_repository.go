@@ -0,0 +1,237 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"math"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// AnalyticsRepository defines the aggregated reporting queries over reservations
+type AnalyticsRepository interface {
+	Covers(ctx context.Context, r domain.AnalyticsRange, period string) ([]domain.CoversBucket, error)
+	Usage(ctx context.Context, r domain.AnalyticsRange) ([]domain.MealTypeUsage, error)
+	Revenue(ctx context.Context, r domain.AnalyticsRange) ([]domain.MealTypeRevenue, error)
+	StatusCounts(ctx context.Context, r domain.AnalyticsRange) (domain.StatusCounts, error)
+	LeadTime(ctx context.Context, r domain.AnalyticsRange) ([]domain.LeadTimeBucket, float64, error)
+}
+
+// MongoAnalyticsRepository implements AnalyticsRepository with aggregation pipelines
+type MongoAnalyticsRepository struct {
+	collection *mongo.Collection
+	// archive holds reservations moved out by the retention job (same database)
+	archive *mongo.Collection
+	// currency the reservations are priced in, for the list prices
+	currency string
+}
+
+// NewMongoAnalyticsRepository creates a new MongoDB analytics repository reporting
+// over both live and archived reservations priced in currency
+func NewMongoAnalyticsRepository(collection, archive *mongo.Collection, currency string) *MongoAnalyticsRepository {
+	return &MongoAnalyticsRepository{collection: collection, archive: archive, currency: currency}
+}
+
+// periodFormats maps a reporting period to its $dateToString format
+var periodFormats = map[string]string{
+	domain.PeriodDay:   "%Y-%m-%d",
+	domain.PeriodWeek:  "%G-W%V", // ISO week
+	domain.PeriodMonth: "%Y-%m",
+}
+
+// notCancelled counts a reservation only when it was not cancelled
+var notCancelled = bson.M{"$ne": bson.A{"$status", domain.StatusCancelled}}
+
+// Covers returns reservations and guests per day, week or month (cancellations excluded)
+func (r *MongoAnalyticsRepository) Covers(ctx context.Context, rng domain.AnalyticsRange, period string) ([]domain.CoversBucket, error) {
+	format, ok := periodFormats[period]
+	if !ok {
+		return nil, fmt.Errorf("invalid period %q", period)
+	}
+
+	match := rangeMatch(rng)
+	match["status"] = bson.M{"$ne": domain.StatusCancelled}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          bson.M{"$dateToString": bson.M{"format": format, "date": "$date_time"}},
+			"reservations": bson.M{"$sum": 1},
+			"covers":       bson.M{"$sum": "$guests"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	buckets := []domain.CoversBucket{}
+	if err := r.aggregate(ctx, pipeline, &buckets); err != nil {
+		return nil, err
+	}
+	return buckets, nil
+}
+
+// Usage returns booked tables and covers per meal type (cancellations excluded)
+func (r *MongoAnalyticsRepository) Usage(ctx context.Context, rng domain.AnalyticsRange) ([]domain.MealTypeUsage, error) {
+	match := rangeMatch(rng)
+	match["status"] = bson.M{"$ne": domain.StatusCancelled}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id":           "$meal_type",
+			"tables_booked": bson.M{"$sum": 1},
+			"covers":        bson.M{"$sum": "$guests"},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	usage := []domain.MealTypeUsage{}
+	if err := r.aggregate(ctx, pipeline, &usage); err != nil {
+		return nil, err
+	}
+	return usage, nil
+}
+
+// Revenue returns revenue, cancellation fees and average discount per meal type, in
+// minor units. The discount is the one recorded in the price breakdown; reservations
+// priced before it existed fall back to the list price (guests x per-person price)
+// minus what was charged.
+func (r *MongoAnalyticsRepository) Revenue(ctx context.Context, rng domain.AnalyticsRange) ([]domain.MealTypeRevenue, error) {
+	listPrice := bson.M{"$multiply": bson.A{"$guests", perPersonPriceExpr(r.currency)}}
+	discount := bson.M{"$ifNull": bson.A{"$price.discount.amount", bson.M{"$subtract": bson.A{listPrice, "$total_price.amount"}}}}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: rangeMatch(rng)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":               "$meal_type",
+			"reservations":      bson.M{"$sum": bson.M{"$cond": bson.A{notCancelled, 1, 0}}},
+			"revenue":           bson.M{"$sum": bson.M{"$cond": bson.A{notCancelled, "$total_price.amount", 0}}},
+			"cancellation_fees": bson.M{"$sum": bson.M{"$ifNull": bson.A{"$cancellation_fee.amount", 0}}},
+			"average_discount":  bson.M{"$avg": bson.M{"$cond": bson.A{notCancelled, discount, nil}}},
+			"average_ticket":    bson.M{"$avg": bson.M{"$cond": bson.A{notCancelled, "$total_price.amount", nil}}},
+		}}},
+		{{Key: "$sort", Value: bson.M{"_id": 1}}},
+	}
+
+	revenue := []domain.MealTypeRevenue{}
+	if err := r.aggregate(ctx, pipeline, &revenue); err != nil {
+		return nil, err
+	}
+	return revenue, nil
+}
+
+// StatusCounts returns how many reservations were cancelled, no-shows or completed
+func (r *MongoAnalyticsRepository) StatusCounts(ctx context.Context, rng domain.AnalyticsRange) (domain.StatusCounts, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: rangeMatch(rng)}},
+		{{Key: "$group", Value: bson.M{
+			"_id":          "$status",
+			"reservations": bson.M{"$sum": 1},
+		}}},
+	}
+
+	totals := []domain.StatusTotal{}
+	if err := r.aggregate(ctx, pipeline, &totals); err != nil {
+		return domain.StatusCounts{}, err
+	}
+	return domain.CountStatuses(totals), nil
+}
+
+// LeadTime returns the distribution of hours between booking and the reservation, and its average
+func (r *MongoAnalyticsRepository) LeadTime(ctx context.Context, rng domain.AnalyticsRange) ([]domain.LeadTimeBucket, float64, error) {
+	boundaries := bson.A{}
+	for _, hours := range domain.LeadTimeBucketsHours {
+		boundaries = append(boundaries, hours)
+	}
+	boundaries = append(boundaries, math.MaxInt32)
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: rangeMatch(rng)}},
+		{{Key: "$project", Value: bson.M{
+			"lead_hours": bson.M{"$divide": bson.A{
+				bson.M{"$subtract": bson.A{"$date_time", "$created_at"}}, 3600 * 1000,
+			}},
+		}}},
+		// Reservations recorded after the fact (e.g. imports) have no meaningful lead time
+		{{Key: "$match", Value: bson.M{"lead_hours": bson.M{"$gte": 0}}}},
+		{{Key: "$facet", Value: bson.M{
+			"buckets": bson.A{
+				bson.M{"$bucket": bson.M{
+					"groupBy":    "$lead_hours",
+					"boundaries": boundaries,
+					"output":     bson.M{"reservations": bson.M{"$sum": 1}},
+				}},
+			},
+			"average": bson.A{
+				bson.M{"$group": bson.M{"_id": nil, "hours": bson.M{"$avg": "$lead_hours"}}},
+			},
+		}}},
+	}
+
+	var result []struct {
+		Buckets []domain.LeadTimeBucket `bson:"buckets"`
+		Average []struct {
+			Hours float64 `bson:"hours"`
+		} `bson:"average"`
+	}
+	if err := r.aggregate(ctx, pipeline, &result); err != nil {
+		return nil, 0, err
+	}
+	if len(result) == 0 {
+		return []domain.LeadTimeBucket{}, 0, nil
+	}
+
+	average := 0.0
+	if len(result[0].Average) > 0 {
+		average = result[0].Average[0].Hours
+	}
+	return result[0].Buckets, average, nil
+}
+
+// aggregate runs a pipeline and decodes every resulting document into out. Archived
+// reservations matching the leading $match are appended right after it, so reports
+// over old periods do not change when the retention job archives them.
+func (r *MongoAnalyticsRepository) aggregate(ctx context.Context, pipeline mongo.Pipeline, out interface{}) error {
+	if r.archive != nil && len(pipeline) > 0 && pipeline[0][0].Key == "$match" {
+		union := bson.D{{Key: "$unionWith", Value: bson.M{
+			"coll":     r.archive.Name(),
+			"pipeline": bson.A{pipeline[0]},
+		}}}
+		pipeline = append(mongo.Pipeline{pipeline[0], union}, pipeline[1:]...)
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return fmt.Errorf("failed to aggregate reservations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	if err := cursor.All(ctx, out); err != nil {
+		return fmt.Errorf("failed to decode aggregation: %w", err)
+	}
+	return nil
+}
+
+// rangeMatch builds the $match stage shared by every report
+func rangeMatch(rng domain.AnalyticsRange) bson.M {
+	match := bson.M{
+		"date_time": bson.M{"$gte": rng.From, "$lt": rng.To},
+	}
+	if rng.MealType != "" {
+		match["meal_type"] = rng.MealType
+	}
+	return match
+}
+
+// perPersonPriceExpr mirrors domain.BasePricePerPerson as an aggregation expression
+func perPersonPriceExpr(currency string) bson.M {
+	branches := bson.A{}
+	for _, mealType := range domain.MealTypes {
+		branches = append(branches, bson.M{
+			"case": bson.M{"$eq": bson.A{"$meal_type", mealType}},
+			"then": domain.BasePricePerPerson(mealType, currency),
+		})
+	}
+	return bson.M{"$switch": bson.M{"branches": branches, "default": domain.BasePricePerPerson("", currency)}}
+}
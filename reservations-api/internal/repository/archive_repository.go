@@ -0,0 +1,93 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// ReservationsArchiveCollection holds reservations moved out by the retention job
+const ReservationsArchiveCollection = "reservations_archive"
+
+// ArchiveRepository moves old reservations out of the live collection and reads them back
+type ArchiveRepository interface {
+	// ArchiveBefore moves up to limit reservations booked before cutoff to the archive
+	// and returns how many were moved
+	ArchiveBefore(ctx context.Context, cutoff time.Time, limit int, now time.Time) (int, error)
+	GetByUserID(ctx context.Context, userID string) ([]domain.Reservation, error)
+}
+
+// MongoArchiveRepository implements ArchiveRepository with an archive collection
+type MongoArchiveRepository struct {
+	live    *mongo.Collection
+	archive *mongo.Collection
+}
+
+// NewMongoArchiveRepository creates a new MongoDB archive repository
+func NewMongoArchiveRepository(live, archive *mongo.Collection) *MongoArchiveRepository {
+	return &MongoArchiveRepository{live: live, archive: archive}
+}
+
+// ArchiveBefore copies a batch into the archive before deleting it from the live
+// collection, so an interrupted run loses nothing and the next run finishes it
+func (r *MongoArchiveRepository) ArchiveBefore(ctx context.Context, cutoff time.Time, limit int, now time.Time) (int, error) {
+	filter := bson.M{"date_time": bson.M{"$lt": cutoff}}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date_time", Value: 1}}).
+		SetLimit(int64(limit))
+
+	cursor, err := r.live.Find(ctx, filter, opts)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find reservations to archive: %w", err)
+	}
+	// Raw documents: fields the current model does not know about are archived too
+	var batch []bson.M
+	if err := cursor.All(ctx, &batch); err != nil {
+		return 0, fmt.Errorf("failed to decode reservations to archive: %w", err)
+	}
+	if len(batch) == 0 {
+		return 0, nil
+	}
+
+	models := make([]mongo.WriteModel, 0, len(batch))
+	ids := make(bson.A, 0, len(batch))
+	for _, doc := range batch {
+		doc["archived_at"] = now
+		models = append(models, mongo.NewReplaceOneModel().
+			SetFilter(bson.M{"_id": doc["_id"]}).
+			SetReplacement(doc).
+			SetUpsert(true))
+		ids = append(ids, doc["_id"])
+	}
+	if _, err := r.archive.BulkWrite(ctx, models, options.BulkWrite().SetOrdered(false)); err != nil {
+		return 0, fmt.Errorf("failed to write archive: %w", err)
+	}
+
+	// A reservation rescheduled past the cutoff since it was read stays live
+	result, err := r.live.DeleteMany(ctx, bson.M{"_id": bson.M{"$in": ids}, "date_time": bson.M{"$lt": cutoff}})
+	if err != nil {
+		return 0, fmt.Errorf("failed to remove archived reservations: %w", err)
+	}
+	return int(result.DeletedCount), nil
+}
+
+// GetByUserID retrieves the archived reservations of a user, newest first
+func (r *MongoArchiveRepository) GetByUserID(ctx context.Context, userID string) ([]domain.Reservation, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "date_time", Value: -1}})
+	cursor, err := r.archive.Find(ctx, bson.M{"owner_id": userID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get archived reservations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	reservations := []domain.Reservation{}
+	if err := cursor.All(ctx, &reservations); err != nil {
+		return nil, fmt.Errorf("failed to decode archived reservations: %w", err)
+	}
+	return reservations, nil
+}
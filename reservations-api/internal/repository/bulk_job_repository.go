@@ -0,0 +1,90 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// BulkJobsCollection keeps the admin bulk runs with their per-reservation results
+const BulkJobsCollection = "bulk_jobs"
+
+// BulkJobRepository persists bulk jobs
+type BulkJobRepository interface {
+	Create(ctx context.Context, job *domain.BulkJob) error
+	// Save stores the progress of a running job
+	Save(ctx context.Context, job *domain.BulkJob) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.BulkJob, error)
+	// List returns the latest jobs first
+	List(ctx context.Context, limit int) ([]domain.BulkJob, error)
+}
+
+// MongoBulkJobRepository implements BulkJobRepository for MongoDB
+type MongoBulkJobRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoBulkJobRepository creates a new MongoDB bulk job repository
+func NewMongoBulkJobRepository(collection *mongo.Collection) *MongoBulkJobRepository {
+	return &MongoBulkJobRepository{collection: collection}
+}
+
+// Create inserts a new job
+func (r *MongoBulkJobRepository) Create(ctx context.Context, job *domain.BulkJob) error {
+	result, err := r.collection.InsertOne(ctx, job)
+	if err != nil {
+		return fmt.Errorf("failed to create bulk job: %w", err)
+	}
+	job.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// Save replaces the stored job
+func (r *MongoBulkJobRepository) Save(ctx context.Context, job *domain.BulkJob) error {
+	if _, err := r.collection.ReplaceOne(ctx, bson.M{"_id": job.ID}, job); err != nil {
+		return fmt.Errorf("failed to save bulk job: %w", err)
+	}
+	return nil
+}
+
+// GetByID retrieves a job by ID
+func (r *MongoBulkJobRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.BulkJob, error) {
+	var job domain.BulkJob
+	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&job)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFoundError("bulk job not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bulk job: %w", err)
+	}
+	return &job, nil
+}
+
+// List retrieves the latest jobs, without their per-reservation results
+func (r *MongoBulkJobRepository) List(ctx context.Context, limit int) ([]domain.BulkJob, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit)).
+		SetProjection(bson.M{"results": 0})
+
+	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bulk jobs: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	jobs := []domain.BulkJob{}
+	if err := cursor.All(ctx, &jobs); err != nil {
+		return nil, fmt.Errorf("failed to decode bulk jobs: %w", err)
+	}
+	return jobs, nil
+}
@@ -0,0 +1,83 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+// ErasureReceiptsCollection records the users whose reservations were erased
+const ErasureReceiptsCollection = "erasure_receipts"
+
+// ErasureRepository anonymizes a deleted user's reservations and keeps the receipts
+type ErasureRepository interface {
+	// GetReceipt returns the receipt of a user, or nil if the user was not erased
+	GetReceipt(ctx context.Context, userID string) (*domain.ErasureReceipt, error)
+	// Anonymize replaces the owner of the user's live and archived reservations with the
+	// tombstone and clears their special requests, returning how many of each it changed
+	Anonymize(ctx context.Context, userID, tombstone string, now time.Time) (live, archived int64, err error)
+	SaveReceipt(ctx context.Context, receipt *domain.ErasureReceipt) error
+}
+
+// MongoErasureRepository implements ErasureRepository for MongoDB
+type MongoErasureRepository struct {
+	live     *mongo.Collection
+	archive  *mongo.Collection
+	receipts *mongo.Collection
+}
+
+// NewMongoErasureRepository creates a new MongoDB erasure repository
+func NewMongoErasureRepository(live, archive, receipts *mongo.Collection) *MongoErasureRepository {
+	return &MongoErasureRepository{live: live, archive: archive, receipts: receipts}
+}
+
+// GetReceipt retrieves the receipt of a user
+func (r *MongoErasureRepository) GetReceipt(ctx context.Context, userID string) (*domain.ErasureReceipt, error) {
+	var receipt domain.ErasureReceipt
+	err := r.receipts.FindOne(ctx, bson.M{"user_id": userID}).Decode(&receipt)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get erasure receipt: %w", err)
+	}
+	return &receipt, nil
+}
+
+// Anonymize updates the live collection first: the retention job only ever moves
+// reservations from there to the archive
+func (r *MongoErasureRepository) Anonymize(ctx context.Context, userID, tombstone string, now time.Time) (int64, int64, error) {
+	filter := bson.M{"owner_id": userID}
+	update := bson.M{
+		"$set":   bson.M{"owner_id": tombstone, "updated_at": now},
+		"$unset": bson.M{"special_requests": "", "allergies": "", "dietary": ""},
+		"$inc":   bson.M{"revision": 1},
+	}
+
+	live, err := r.live.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to anonymize reservations: %w", err)
+	}
+	archived, err := r.archive.UpdateMany(ctx, filter, update)
+	if err != nil {
+		return live.ModifiedCount, 0, fmt.Errorf("failed to anonymize archived reservations: %w", err)
+	}
+	return live.ModifiedCount, archived.ModifiedCount, nil
+}
+
+// SaveReceipt inserts a receipt; the unique user_id index keeps one per user
+func (r *MongoErasureRepository) SaveReceipt(ctx context.Context, receipt *domain.ErasureReceipt) error {
+	if _, err := r.receipts.InsertOne(ctx, receipt); err != nil {
+		// Another replica erased the same user concurrently
+		if mongo.IsDuplicateKeyError(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to save erasure receipt: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// HoldsCollection holds the tables kept during checkout; a TTL index purges them
+const HoldsCollection = "holds"
+
+// HoldRepository persists table holds
+type HoldRepository interface {
+	// Create inserts the hold, taking over an expired hold of the same slot. It fails
+	// with a conflict while another hold of the slot is active.
+	Create(ctx context.Context, hold *domain.Hold, now time.Time) error
+	// GetBySlot returns the hold of a slot, active or not
+	GetBySlot(ctx context.Context, slot string) (*domain.Hold, error)
+	// ListActive returns the holds booked in [from, to) that have not expired. An empty
+	// meal type covers every meal type.
+	ListActive(ctx context.Context, from, to time.Time, mealType string, now time.Time) ([]domain.Hold, error)
+	// Consume deletes the hold of slot if tokenHash is its token's
+	Consume(ctx context.Context, slot, tokenHash string) error
+	// ClaimExpired marks one expired hold as released and returns it, or nil when every
+	// expired hold was already released
+	ClaimExpired(ctx context.Context, now time.Time) (*domain.Hold, error)
+}
+
+// MongoHoldRepository implements HoldRepository for MongoDB
+type MongoHoldRepository struct {
+	collection *mongo.Collection
+}
+
+// NewMongoHoldRepository creates a new MongoDB hold repository
+func NewMongoHoldRepository(collection *mongo.Collection) *MongoHoldRepository {
+	return &MongoHoldRepository{collection: collection}
+}
+
+// Create relies on the unique slot index: the insert fails while the slot is held
+func (r *MongoHoldRepository) Create(ctx context.Context, hold *domain.Hold, now time.Time) error {
+	var expired domain.Hold
+	err := r.collection.FindOneAndReplace(ctx,
+		bson.M{"slot": hold.Slot, "expires_at": bson.M{"$lte": now}},
+		hold,
+	).Decode(&expired)
+	if err == nil {
+		hold.ID = expired.ID
+		return nil
+	}
+	if !errors.Is(err, mongo.ErrNoDocuments) {
+		return fmt.Errorf("failed to replace expired hold: %w", err)
+	}
+
+	result, err := r.collection.InsertOne(ctx, hold)
+	if mongo.IsDuplicateKeyError(err) {
+		active, getErr := r.GetBySlot(ctx, hold.Slot)
+		if getErr != nil {
+			return domain.ConflictError("table %d is already held", hold.TableNumber)
+		}
+		return domain.ConflictError("table %d is already held until %s", hold.TableNumber, active.ExpiresAt.Format(time.RFC3339))
+	}
+	if err != nil {
+		return fmt.Errorf("failed to create hold: %w", err)
+	}
+	hold.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetBySlot returns the hold of a slot
+func (r *MongoHoldRepository) GetBySlot(ctx context.Context, slot string) (*domain.Hold, error) {
+	var hold domain.Hold
+	err := r.collection.FindOne(ctx, bson.M{"slot": slot}).Decode(&hold)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFoundError("hold not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get hold: %w", err)
+	}
+	return &hold, nil
+}
+
+// ListActive returns the unexpired holds booked in [from, to)
+func (r *MongoHoldRepository) ListActive(ctx context.Context, from, to time.Time, mealType string, now time.Time) ([]domain.Hold, error) {
+	filter := bson.M{
+		"date_time":  bson.M{"$gte": from, "$lt": to},
+		"expires_at": bson.M{"$gt": now},
+	}
+	if mealType != "" {
+		filter["meal_type"] = mealType
+	}
+
+	cursor, err := r.collection.Find(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holds: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	holds := []domain.Hold{}
+	if err := cursor.All(ctx, &holds); err != nil {
+		return nil, fmt.Errorf("failed to decode holds: %w", err)
+	}
+	return holds, nil
+}
+
+// Consume deletes a hold once its table is booked
+func (r *MongoHoldRepository) Consume(ctx context.Context, slot, tokenHash string) error {
+	if _, err := r.collection.DeleteOne(ctx, bson.M{"slot": slot, "token_hash": tokenHash}); err != nil {
+		return fmt.Errorf("failed to consume hold: %w", err)
+	}
+	return nil
+}
+
+// ClaimExpired flags one expired hold as released, so a single replica announces it
+func (r *MongoHoldRepository) ClaimExpired(ctx context.Context, now time.Time) (*domain.Hold, error) {
+	var hold domain.Hold
+	err := r.collection.FindOneAndUpdate(ctx,
+		bson.M{"expires_at": bson.M{"$lte": now}, "released": bson.M{"$ne": true}},
+		bson.M{"$set": bson.M{"released": true}},
+		options.FindOneAndUpdate().SetReturnDocument(options.After).SetSort(bson.D{{Key: "expires_at", Value: 1}}),
+	).Decode(&hold)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim expired hold: %w", err)
+	}
+	return &hold, nil
+}
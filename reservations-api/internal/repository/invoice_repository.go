@@ -0,0 +1,149 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Invoice collections
+const (
+	InvoicesCollection = "invoices"
+	CountersCollection = "counters"
+)
+
+// invoiceCounterID is the counters document holding the last invoice sequence
+const invoiceCounterID = "invoices"
+
+// maxNumberingAttempts bounds the retries when concurrent issuers race for a number
+const maxNumberingAttempts = 20
+
+// InvoiceRepository stores issued invoices
+type InvoiceRepository interface {
+	// GetByReservation returns the invoice of a reservation, or nil if none was issued
+	GetByReservation(ctx context.Context, reservationID primitive.ObjectID) (*domain.Invoice, error)
+	// Issue numbers and stores an invoice. If the reservation was invoiced concurrently,
+	// the existing invoice is returned instead.
+	Issue(ctx context.Context, invoice *domain.Invoice, prefix string) (*domain.Invoice, error)
+}
+
+// MongoInvoiceRepository implements InvoiceRepository for MongoDB
+type MongoInvoiceRepository struct {
+	invoices *mongo.Collection
+	counters *mongo.Collection
+}
+
+// NewMongoInvoiceRepository creates a new MongoDB invoice repository
+func NewMongoInvoiceRepository(invoices, counters *mongo.Collection) *MongoInvoiceRepository {
+	return &MongoInvoiceRepository{invoices: invoices, counters: counters}
+}
+
+// GetByReservation retrieves the invoice of a reservation
+func (r *MongoInvoiceRepository) GetByReservation(ctx context.Context, reservationID primitive.ObjectID) (*domain.Invoice, error) {
+	var invoice domain.Invoice
+	err := r.invoices.FindOne(ctx, bson.M{"reservation_id": reservationID}).Decode(&invoice)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+	return &invoice, nil
+}
+
+// Issue takes the number after the counter and inserts the invoice with it. The unique
+// sequence index makes the insert the point where a number is consumed, so a failed
+// insert leaves no gap: the next attempt takes the same number. The counter only
+// advances past numbers that exist, and a racing issuer that loses retries with the next.
+func (r *MongoInvoiceRepository) Issue(ctx context.Context, invoice *domain.Invoice, prefix string) (*domain.Invoice, error) {
+	for attempt := 0; attempt < maxNumberingAttempts; attempt++ {
+		last, err := r.lastSequence(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		invoice.ID = primitive.NewObjectID()
+		invoice.Sequence = last + 1
+		invoice.Number = domain.FormatInvoiceNumber(prefix, invoice.Sequence)
+
+		_, err = r.invoices.InsertOne(ctx, invoice)
+		switch {
+		case err == nil:
+			// A counter left behind is caught up by the next issue, on the duplicate number
+			if err := r.advance(ctx, invoice.Sequence); err != nil {
+				log.Printf("invoice %s issued but %v", invoice.Number, err)
+			}
+			return invoice, nil
+		case isDuplicateKeyOn(err, "reservation_id"):
+			// Another request invoiced the same reservation
+			return r.GetByReservation(ctx, invoice.ReservationID)
+		case mongo.IsDuplicateKeyError(err):
+			// The number was taken: catch the counter up and try the next one
+			if err := r.advance(ctx, invoice.Sequence); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("failed to insert invoice: %w", err)
+		}
+	}
+	return nil, fmt.Errorf("failed to number invoice after %d attempts", maxNumberingAttempts)
+}
+
+// lastSequence reads the counter (0 before the first invoice)
+func (r *MongoInvoiceRepository) lastSequence(ctx context.Context) (int64, error) {
+	var counter struct {
+		Seq int64 `bson:"seq"`
+	}
+	err := r.counters.FindOne(ctx, bson.M{"_id": invoiceCounterID}).Decode(&counter)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read invoice counter: %w", err)
+	}
+	return counter.Seq, nil
+}
+
+// advance moves the counter forward to seq; it never moves it back
+func (r *MongoInvoiceRepository) advance(ctx context.Context, seq int64) error {
+	update := func() error {
+		_, err := r.counters.UpdateOne(ctx,
+			bson.M{"_id": invoiceCounterID},
+			bson.M{"$max": bson.M{"seq": seq}},
+			options.Update().SetUpsert(true),
+		)
+		return err
+	}
+
+	err := update()
+	if mongo.IsDuplicateKeyError(err) {
+		// Two first upserts raced to create the counter: it exists now
+		err = update()
+	}
+	if err != nil {
+		return fmt.Errorf("failed to advance invoice counter: %w", err)
+	}
+	return nil
+}
+
+// isDuplicateKeyOn reports a duplicate key error on an index over field
+func isDuplicateKeyOn(err error, field string) bool {
+	var we mongo.WriteException
+	if !errors.As(err, &we) {
+		return false
+	}
+	for _, e := range we.WriteErrors {
+		if e.Code == 11000 && strings.Contains(e.Message, field) {
+			return true
+		}
+	}
+	return false
+}
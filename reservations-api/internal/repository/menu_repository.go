@@ -0,0 +1,168 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// MenuItemsCollection holds the menu catalog, next to the reservations
+const MenuItemsCollection = "menu_items"
+
+// MenuRepository persists the menu catalog and reads the pre-orders of reservations
+type MenuRepository interface {
+	Create(ctx context.Context, item *domain.MenuItem) error
+	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MenuItem, error)
+	// GetByIDs returns the items found among ids, keyed by ID
+	GetByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]domain.MenuItem, error)
+	List(ctx context.Context, filter domain.MenuFilter) ([]domain.MenuItem, error)
+	// Replace overwrites an existing item
+	Replace(ctx context.Context, item *domain.MenuItem) error
+	// PrepList sums the pre-ordered portions per day (in loc), meal and item
+	PrepList(ctx context.Context, rng domain.AnalyticsRange, loc *time.Location) ([]domain.PrepLine, error)
+}
+
+// MongoMenuRepository implements MenuRepository for MongoDB
+type MongoMenuRepository struct {
+	items        *mongo.Collection
+	reservations *mongo.Collection
+}
+
+// NewMongoMenuRepository creates a new MongoDB menu repository
+func NewMongoMenuRepository(items, reservations *mongo.Collection) *MongoMenuRepository {
+	return &MongoMenuRepository{items: items, reservations: reservations}
+}
+
+// Create inserts a menu item
+func (r *MongoMenuRepository) Create(ctx context.Context, item *domain.MenuItem) error {
+	result, err := r.items.InsertOne(ctx, item)
+	if err != nil {
+		return fmt.Errorf("failed to create menu item: %w", err)
+	}
+	item.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetByID retrieves a menu item by ID
+func (r *MongoMenuRepository) GetByID(ctx context.Context, id primitive.ObjectID) (*domain.MenuItem, error) {
+	var item domain.MenuItem
+	err := r.items.FindOne(ctx, bson.M{"_id": id}).Decode(&item)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFoundError("menu item not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu item: %w", err)
+	}
+	return &item, nil
+}
+
+// GetByIDs retrieves the menu items with the given IDs
+func (r *MongoMenuRepository) GetByIDs(ctx context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]domain.MenuItem, error) {
+	items, err := r.find(ctx, bson.M{"_id": bson.M{"$in": ids}})
+	if err != nil {
+		return nil, err
+	}
+
+	byID := make(map[primitive.ObjectID]domain.MenuItem, len(items))
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	return byID, nil
+}
+
+// List returns the menu items matching the filter by category, then name
+func (r *MongoMenuRepository) List(ctx context.Context, filter domain.MenuFilter) ([]domain.MenuItem, error) {
+	query := bson.M{}
+	if filter.MealType != "" {
+		query["meal_types"] = filter.MealType
+	}
+	if !filter.IncludeInactive {
+		query["active"] = true
+	}
+	return r.find(ctx, query)
+}
+
+func (r *MongoMenuRepository) find(ctx context.Context, filter bson.M) ([]domain.MenuItem, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "category", Value: 1}, {Key: "name", Value: 1}})
+	cursor, err := r.items.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu items: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	items := []domain.MenuItem{}
+	if err := cursor.All(ctx, &items); err != nil {
+		return nil, fmt.Errorf("failed to decode menu items: %w", err)
+	}
+	return items, nil
+}
+
+// Replace overwrites a menu item
+func (r *MongoMenuRepository) Replace(ctx context.Context, item *domain.MenuItem) error {
+	result, err := r.items.ReplaceOne(ctx, bson.M{"_id": item.ID}, item)
+	if err != nil {
+		return fmt.Errorf("failed to update menu item: %w", err)
+	}
+	if result.MatchedCount == 0 {
+		return domain.NotFoundError("menu item not found")
+	}
+	return nil
+}
+
+// PrepList unwinds the pre-orders of the reservations in the range that were not
+// cancelled and sums them per local day, meal type and menu item. Each reservation
+// orders an item at most once, so the line count is the number of reservations.
+func (r *MongoMenuRepository) PrepList(ctx context.Context, rng domain.AnalyticsRange, loc *time.Location) ([]domain.PrepLine, error) {
+	match := rangeMatch(rng)
+	match["status"] = bson.M{"$ne": domain.StatusCancelled}
+	match["pre_order.0"] = bson.M{"$exists": true}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$unwind", Value: "$pre_order"}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"date":         bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$date_time", "timezone": loc.String()}},
+				"meal_type":    "$meal_type",
+				"menu_item_id": "$pre_order.menu_item_id",
+			},
+			"name":         bson.M{"$last": "$pre_order.name"},
+			"allergens":    bson.M{"$last": "$pre_order.allergens"},
+			"quantity":     bson.M{"$sum": "$pre_order.quantity"},
+			"reservations": bson.M{"$sum": 1},
+			"notes":        bson.M{"$push": "$pre_order.notes"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":          0,
+			"date":         "$_id.date",
+			"meal_type":    "$_id.meal_type",
+			"menu_item_id": "$_id.menu_item_id",
+			"name":         1,
+			"allergens":    bson.M{"$ifNull": bson.A{"$allergens", bson.A{}}},
+			"quantity":     1,
+			"reservations": 1,
+			// Only the lines that carry a note
+			"notes": bson.M{"$filter": bson.M{"input": "$notes", "cond": bson.M{"$gt": bson.A{"$$this", ""}}}},
+		}}},
+		{{Key: "$sort", Value: bson.D{{Key: "date", Value: 1}, {Key: "name", Value: 1}}}},
+	}
+
+	cursor, err := r.reservations.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate pre-orders: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	lines := []domain.PrepLine{}
+	if err := cursor.All(ctx, &lines); err != nil {
+		return nil, fmt.Errorf("failed to decode pre-orders: %w", err)
+	}
+	return lines, nil
+}
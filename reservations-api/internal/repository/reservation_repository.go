@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -16,11 +17,14 @@ import (
 type ReservationRepository interface {
 	Create(ctx context.Context, reservation *domain.Reservation) error
 	GetByID(ctx context.Context, id primitive.ObjectID) (*domain.Reservation, error)
-	GetAll(ctx context.Context, limit, offset int) ([]domain.Reservation, error)
+	GetAll(ctx context.Context, filter domain.ReservationFilter, limit, offset int) ([]domain.Reservation, error)
+	Stream(ctx context.Context, filter domain.ReservationFilter, fn func(domain.Reservation) error) error
 	GetByUserID(ctx context.Context, userID string) ([]domain.Reservation, error)
 	Update(ctx context.Context, id primitive.ObjectID, reservation *domain.Reservation) error
+	UpdateIfStatus(ctx context.Context, id primitive.ObjectID, status string, reservation *domain.Reservation) error
 	Delete(ctx context.Context, id primitive.ObjectID) error
 	GetReservedTableNumbers(ctx context.Context, date string, mealType string) ([]int, error)
+	GetReservedSlots(ctx context.Context, from, to time.Time, mealType string) ([]domain.ReservedSlot, error)
 }
 
 // MongoReservationRepository implements ReservationRepository using MongoDB
@@ -53,8 +57,8 @@ func (r *MongoReservationRepository) GetByID(ctx context.Context, id primitive.O
 
 	err := r.collection.FindOne(ctx, bson.M{"_id": id}).Decode(&reservation)
 	if err != nil {
-		if err == mongo.ErrNoDocuments {
-			return nil, fmt.Errorf("reservation not found")
+		if errors.Is(err, mongo.ErrNoDocuments) {
+			return nil, domain.NotFoundError("reservation not found")
 		}
 		return nil, fmt.Errorf("failed to get reservation: %w", err)
 	}
@@ -62,8 +66,8 @@ func (r *MongoReservationRepository) GetByID(ctx context.Context, id primitive.O
 	return &reservation, nil
 }
 
-// GetAll retrieves all reservations with pagination
-func (r *MongoReservationRepository) GetAll(ctx context.Context, limit, offset int) ([]domain.Reservation, error) {
+// GetAll retrieves the reservations matching the filter with pagination
+func (r *MongoReservationRepository) GetAll(ctx context.Context, filter domain.ReservationFilter, limit, offset int) ([]domain.Reservation, error) {
 	if limit <= 0 {
 		limit = 50 // default limit
 	}
@@ -73,7 +77,7 @@ func (r *MongoReservationRepository) GetAll(ctx context.Context, limit, offset i
 		SetSkip(int64(offset)).
 		SetSort(bson.D{{Key: "date_time", Value: -1}}) // Sort by date descending
 
-	cursor, err := r.collection.Find(ctx, bson.M{}, opts)
+	cursor, err := r.collection.Find(ctx, buildFilter(filter), opts)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get reservations: %w", err)
 	}
@@ -87,6 +91,74 @@ func (r *MongoReservationRepository) GetAll(ctx context.Context, limit, offset i
 	return reservations, nil
 }
 
+// Stream calls fn for every reservation matching the filter, one document at a time,
+// so large exports never hold the whole result set in memory
+func (r *MongoReservationRepository) Stream(ctx context.Context, filter domain.ReservationFilter, fn func(domain.Reservation) error) error {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "date_time", Value: -1}}).
+		SetBatchSize(500)
+
+	cursor, err := r.collection.Find(ctx, buildFilter(filter), opts)
+	if err != nil {
+		return fmt.Errorf("failed to stream reservations: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	for cursor.Next(ctx) {
+		var reservation domain.Reservation
+		if err := cursor.Decode(&reservation); err != nil {
+			return fmt.Errorf("failed to decode reservation: %w", err)
+		}
+		if err := fn(reservation); err != nil {
+			return err
+		}
+	}
+
+	return cursor.Err()
+}
+
+// buildFilter translates a listing filter into a MongoDB query
+func buildFilter(filter domain.ReservationFilter) bson.M {
+	query := bson.M{}
+	if filter.OwnerID != "" {
+		query["owner_id"] = filter.OwnerID
+	}
+	if filter.MealType != "" {
+		query["meal_type"] = filter.MealType
+	}
+	if filter.TableNumber != 0 {
+		query["table_number"] = filter.TableNumber
+	}
+	if filter.Status != "" {
+		query["status"] = filter.Status
+	}
+	if filter.Allergy != "" {
+		query["allergies"] = filter.Allergy
+	}
+	if filter.Dietary != "" {
+		query["dietary"] = filter.Dietary
+	}
+	if filter.Occasion != "" {
+		query["occasion"] = filter.Occasion
+	}
+	if filter.Wheelchair {
+		query["accessibility.wheelchair"] = true
+	}
+
+	dateRange := bson.M{}
+	if !filter.From.IsZero() {
+		dateRange["$gte"] = filter.From
+	}
+	if !filter.To.IsZero() {
+		dateRange["$lt"] = filter.To
+	}
+	if len(dateRange) > 0 {
+		query["date_time"] = dateRange
+	}
+
+	return query
+}
+
 // GetByUserID retrieves all reservations for a specific user
 func (r *MongoReservationRepository) GetByUserID(ctx context.Context, userID string) ([]domain.Reservation, error) {
 	filter := bson.M{"owner_id": userID}
@@ -111,20 +183,46 @@ func (r *MongoReservationRepository) Update(ctx context.Context, id primitive.Ob
 	reservation.UpdatedAt = time.Now()
 
 	filter := bson.M{"_id": id}
-	update := bson.M{"$set": reservation}
+	result, err := r.collection.UpdateOne(ctx, filter, revisionUpdate(reservation))
+	if err != nil {
+		return fmt.Errorf("failed to update reservation: %w", err)
+	}
+
+	if result.MatchedCount == 0 {
+		return domain.NotFoundError("reservation not found")
+	}
+	reservation.Revision++
+
+	return nil
+}
+
+// UpdateIfStatus updates a reservation only while it still has the given status, so two
+// concurrent transitions out of the same status cannot both succeed
+func (r *MongoReservationRepository) UpdateIfStatus(ctx context.Context, id primitive.ObjectID, status string, reservation *domain.Reservation) error {
+	reservation.UpdatedAt = time.Now()
 
-	result, err := r.collection.UpdateOne(ctx, filter, update)
+	filter := bson.M{"_id": id, "status": status}
+	result, err := r.collection.UpdateOne(ctx, filter, revisionUpdate(reservation))
 	if err != nil {
 		return fmt.Errorf("failed to update reservation: %w", err)
 	}
 
 	if result.MatchedCount == 0 {
-		return fmt.Errorf("reservation not found")
+		return domain.ConflictError("reservation is no longer %s", status)
 	}
+	reservation.Revision++
 
 	return nil
 }
 
+// revisionUpdate writes every field of the reservation and increments its revision in
+// the database, so two updates in a row never store the same revision
+func revisionUpdate(reservation *domain.Reservation) bson.M {
+	fields := *reservation
+	fields.Revision = 0 // omitted: $inc owns the field
+	return bson.M{"$set": fields, "$inc": bson.M{"revision": 1}}
+}
+
 // Delete removes a reservation
 func (r *MongoReservationRepository) Delete(ctx context.Context, id primitive.ObjectID) error {
 	result, err := r.collection.DeleteOne(ctx, bson.M{"_id": id})
@@ -133,34 +231,40 @@ func (r *MongoReservationRepository) Delete(ctx context.Context, id primitive.Ob
 	}
 
 	if result.DeletedCount == 0 {
-		return fmt.Errorf("reservation not found")
+		return domain.NotFoundError("reservation not found")
 	}
 
 	return nil
 }
 
+// releasedTables matches the reservations that no longer hold their table for the day
+// and meal: cancelled ones, and walk-ins that have left, since they were never booked
+// ahead and their table can be booked again the same day
+var releasedTables = bson.A{
+	bson.M{"status": domain.StatusCancelled},
+	bson.M{"walk_in": true, "status": domain.StatusCompleted},
+}
+
 // GetReservedTableNumbers returns table numbers that are reserved for a given date and meal type
 func (r *MongoReservationRepository) GetReservedTableNumbers(ctx context.Context, date string, mealType string) ([]int, error) {
 	// Parse date to get start and end of day
 	parsedDate, err := time.Parse("2006-01-02", date)
 	if err != nil {
-		return nil, fmt.Errorf("invalid date format: %w", err)
+		return nil, domain.NewValidationError("date", "must be YYYY-MM-DD")
 	}
 
 	startOfDay := time.Date(parsedDate.Year(), parsedDate.Month(), parsedDate.Day(), 0, 0, 0, 0, parsedDate.Location())
 	endOfDay := startOfDay.Add(24 * time.Hour)
 
 	// Query for reservations on that date with that meal type
-	// Only count non-cancelled reservations
+	// Only count reservations that still hold their table
 	filter := bson.M{
 		"meal_type": mealType,
 		"date_time": bson.M{
 			"$gte": startOfDay,
 			"$lt":  endOfDay,
 		},
-		"status": bson.M{
-			"$ne": domain.StatusCancelled, // Exclude cancelled reservations
-		},
+		"$nor": releasedTables,
 	}
 
 	cursor, err := r.collection.Find(ctx, filter)
@@ -182,3 +286,44 @@ func (r *MongoReservationRepository) GetReservedTableNumbers(ctx context.Context
 
 	return tableNumbers, nil
 }
+
+// GetReservedSlots returns, in a single aggregation, the tables taken per day and meal type
+// in [from, to). An empty meal type covers every meal type.
+func (r *MongoReservationRepository) GetReservedSlots(ctx context.Context, from, to time.Time, mealType string) ([]domain.ReservedSlot, error) {
+	match := bson.M{
+		"date_time": bson.M{"$gte": from, "$lt": to},
+		"$nor":      releasedTables,
+	}
+	if mealType != "" {
+		match["meal_type"] = mealType
+	}
+
+	pipeline := mongo.Pipeline{
+		{{Key: "$match", Value: match}},
+		{{Key: "$group", Value: bson.M{
+			"_id": bson.M{
+				"date":      bson.M{"$dateToString": bson.M{"format": "%Y-%m-%d", "date": "$date_time"}},
+				"meal_type": "$meal_type",
+			},
+			"tables": bson.M{"$addToSet": "$table_number"},
+		}}},
+		{{Key: "$project", Value: bson.M{
+			"_id":       0,
+			"date":      "$_id.date",
+			"meal_type": "$_id.meal_type",
+			"tables":    1,
+		}}},
+	}
+
+	cursor, err := r.collection.Aggregate(ctx, pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("failed to aggregate reserved tables: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	slots := []domain.ReservedSlot{}
+	if err := cursor.All(ctx, &slots); err != nil {
+		return nil, fmt.Errorf("failed to decode reserved tables: %w", err)
+	}
+	return slots, nil
+}
@@ -0,0 +1,201 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+// Collections holding webhook subscriptions and deliveries, next to the reservations
+const (
+	WebhooksCollection          = "webhooks"
+	WebhookDeliveriesCollection = "webhook_deliveries"
+)
+
+// WebhookRepository persists webhook subscriptions and their delivery log
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error
+	GetSubscription(ctx context.Context, id primitive.ObjectID) (*domain.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	// SubscriptionsFor returns the subscriptions that want an event type
+	SubscriptionsFor(ctx context.Context, event string) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id primitive.ObjectID) error
+	CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error
+	GetDelivery(ctx context.Context, id primitive.ObjectID) (*domain.WebhookDelivery, error)
+	// ListDeliveries returns the latest deliveries of a subscription, newest first
+	ListDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, limit int) ([]domain.WebhookDelivery, error)
+	// ClaimDue leases the next delivery due for an attempt, or returns nil if there is none
+	ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error)
+	// RecordAttempt appends an attempt and moves the delivery to status; a pending
+	// delivery is retried at nextAttemptAt
+	RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error
+}
+
+// MongoWebhookRepository implements WebhookRepository for MongoDB
+type MongoWebhookRepository struct {
+	subscriptions *mongo.Collection
+	deliveries    *mongo.Collection
+}
+
+// NewMongoWebhookRepository creates a new MongoDB webhook repository
+func NewMongoWebhookRepository(subscriptions, deliveries *mongo.Collection) *MongoWebhookRepository {
+	return &MongoWebhookRepository{subscriptions: subscriptions, deliveries: deliveries}
+}
+
+// CreateSubscription inserts a subscription
+func (r *MongoWebhookRepository) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	result, err := r.subscriptions.InsertOne(ctx, sub)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook: %w", err)
+	}
+	sub.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetSubscription retrieves a subscription by ID
+func (r *MongoWebhookRepository) GetSubscription(ctx context.Context, id primitive.ObjectID) (*domain.WebhookSubscription, error) {
+	var sub domain.WebhookSubscription
+	err := r.subscriptions.FindOne(ctx, bson.M{"_id": id}).Decode(&sub)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFoundError("webhook not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+	return &sub, nil
+}
+
+// ListSubscriptions returns every subscription, oldest first
+func (r *MongoWebhookRepository) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.findSubscriptions(ctx, bson.M{})
+}
+
+// SubscriptionsFor returns the subscriptions listing the event or the wildcard
+func (r *MongoWebhookRepository) SubscriptionsFor(ctx context.Context, event string) ([]domain.WebhookSubscription, error) {
+	return r.findSubscriptions(ctx, bson.M{"events": bson.M{"$in": bson.A{event, domain.WebhookEventAll}}})
+}
+
+func (r *MongoWebhookRepository) findSubscriptions(ctx context.Context, filter bson.M) ([]domain.WebhookSubscription, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "created_at", Value: 1}})
+	cursor, err := r.subscriptions.Find(ctx, filter, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	subs := []domain.WebhookSubscription{}
+	if err := cursor.All(ctx, &subs); err != nil {
+		return nil, fmt.Errorf("failed to decode webhooks: %w", err)
+	}
+	return subs, nil
+}
+
+// DeleteSubscription removes a subscription; its delivery log is kept
+func (r *MongoWebhookRepository) DeleteSubscription(ctx context.Context, id primitive.ObjectID) error {
+	result, err := r.subscriptions.DeleteOne(ctx, bson.M{"_id": id})
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+	if result.DeletedCount == 0 {
+		return domain.NotFoundError("webhook not found")
+	}
+	return nil
+}
+
+// CreateDelivery inserts a delivery
+func (r *MongoWebhookRepository) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	result, err := r.deliveries.InsertOne(ctx, d)
+	if err != nil {
+		return fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+	d.ID = result.InsertedID.(primitive.ObjectID)
+	return nil
+}
+
+// GetDelivery retrieves a delivery by ID
+func (r *MongoWebhookRepository) GetDelivery(ctx context.Context, id primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	var d domain.WebhookDelivery
+	err := r.deliveries.FindOne(ctx, bson.M{"_id": id}).Decode(&d)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, domain.NotFoundError("webhook delivery not found")
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// ListDeliveries returns the latest deliveries of a subscription
+func (r *MongoWebhookRepository) ListDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, limit int) ([]domain.WebhookDelivery, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}}).
+		SetLimit(int64(limit))
+	cursor, err := r.deliveries.Find(ctx, bson.M{"subscription_id": subscriptionID}, opts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer cursor.Close(ctx)
+
+	deliveries := []domain.WebhookDelivery{}
+	if err := cursor.All(ctx, &deliveries); err != nil {
+		return nil, fmt.Errorf("failed to decode webhook deliveries: %w", err)
+	}
+	return deliveries, nil
+}
+
+// ClaimDue atomically moves the next due delivery to "delivering". Deliveries
+// whose lease expired (e.g. the dispatcher crashed mid-request) are claimed again.
+func (r *MongoWebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	filter := bson.M{
+		"$or": bson.A{
+			bson.M{"status": domain.DeliveryPending, "next_attempt_at": bson.M{"$lte": now}},
+			bson.M{"status": domain.DeliveryDelivering, "locked_until": bson.M{"$lt": now}},
+		},
+	}
+	update := bson.M{"$set": bson.M{
+		"status":       domain.DeliveryDelivering,
+		"locked_until": now.Add(lease),
+		"updated_at":   now,
+	}}
+	opts := options.FindOneAndUpdate().
+		SetSort(bson.D{{Key: "next_attempt_at", Value: 1}}).
+		SetReturnDocument(options.After)
+
+	var d domain.WebhookDelivery
+	err := r.deliveries.FindOneAndUpdate(ctx, filter, update, opts).Decode(&d)
+	if errors.Is(err, mongo.ErrNoDocuments) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim webhook delivery: %w", err)
+	}
+	return &d, nil
+}
+
+// RecordAttempt appends an attempt to the delivery log and releases the lease
+func (r *MongoWebhookRepository) RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	set := bson.M{"status": status, "updated_at": attempt.At}
+	switch status {
+	case domain.DeliveryPending:
+		set["next_attempt_at"] = nextAttemptAt
+	case domain.DeliveryDelivered:
+		set["delivered_at"] = attempt.At
+	}
+
+	_, err := r.deliveries.UpdateByID(ctx, id, bson.M{
+		"$set":   set,
+		"$push":  bson.M{"attempts": attempt},
+		"$unset": bson.M{"locked_until": ""},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to record webhook attempt: %w", err)
+	}
+	return nil
+}
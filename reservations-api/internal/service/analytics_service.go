@@ -0,0 +1,149 @@
+package service
+
+import (
+	"context"
+	"math"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// AnalyticsService defines the management reports over reservations
+type AnalyticsService interface {
+	Covers(ctx context.Context, r domain.AnalyticsRange, period string) (*domain.CoversReport, error)
+	Utilisation(ctx context.Context, r domain.AnalyticsRange) (*domain.UtilisationReport, error)
+	Revenue(ctx context.Context, r domain.AnalyticsRange) (*domain.RevenueReport, error)
+	Rates(ctx context.Context, r domain.AnalyticsRange) (*domain.RatesReport, error)
+	LeadTime(ctx context.Context, r domain.AnalyticsRange) (*domain.LeadTimeReport, error)
+}
+
+// analyticsService implements AnalyticsService
+type analyticsService struct {
+	repo repository.AnalyticsRepository
+	// currency the reservations are priced in; amounts are reported in its major unit
+	currency string
+}
+
+// NewAnalyticsService creates a new analytics service
+func NewAnalyticsService(repo repository.AnalyticsRepository, currency string) AnalyticsService {
+	return &analyticsService{repo: repo, currency: currency}
+}
+
+// Covers returns covers per day, week or month
+func (s *analyticsService) Covers(ctx context.Context, r domain.AnalyticsRange, period string) (*domain.CoversReport, error) {
+	buckets, err := s.repo.Covers(ctx, r, period)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.CoversReport{Range: r, Period: period, Buckets: buckets}, nil
+}
+
+// Utilisation compares booked tables and covers with the capacity of the table catalog
+func (s *analyticsService) Utilisation(ctx context.Context, r domain.AnalyticsRange) (*domain.UtilisationReport, error) {
+	usage, err := s.repo.Usage(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	booked := map[string]domain.MealTypeUsage{}
+	for _, u := range usage {
+		booked[u.MealType] = u
+	}
+
+	mealTypes := []string{domain.MealTypeBreakfast, domain.MealTypeLunch, domain.MealTypeDinner, domain.MealTypeEvent}
+	if r.MealType != "" {
+		mealTypes = []string{r.MealType}
+	}
+
+	days := r.Days()
+	report := &domain.UtilisationReport{Range: r, MealTypes: []domain.TableUtilisation{}}
+	for _, mealType := range mealTypes {
+		tables := domain.GetTablesForMealType(mealType)
+		seats := 0
+		for _, table := range tables {
+			seats += table.Capacity
+		}
+
+		u := booked[mealType]
+		item := domain.TableUtilisation{
+			MealType:     mealType,
+			TablesBooked: u.TablesBooked,
+			TableSlots:   len(tables) * days,
+			Covers:       u.Covers,
+			SeatCapacity: seats * days,
+		}
+		item.TableUtilisation = ratio(item.TablesBooked, item.TableSlots)
+		item.SeatUtilisation = ratio(item.Covers, item.SeatCapacity)
+		item.AveragePartySize = ratio(item.Covers, item.TablesBooked)
+		report.MealTypes = append(report.MealTypes, item)
+	}
+
+	return report, nil
+}
+
+// Revenue returns revenue and average discount per meal type
+func (s *analyticsService) Revenue(ctx context.Context, r domain.AnalyticsRange) (*domain.RevenueReport, error) {
+	revenue, err := s.repo.Revenue(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	for i := range revenue {
+		revenue[i].Revenue = s.majorUnits(revenue[i].Revenue)
+		revenue[i].CancellationFee = s.majorUnits(revenue[i].CancellationFee)
+		revenue[i].AverageDiscount = s.majorUnits(revenue[i].AverageDiscount)
+		revenue[i].AverageTicket = s.majorUnits(revenue[i].AverageTicket)
+	}
+	return &domain.RevenueReport{Range: r, Currency: s.currency, MealTypes: revenue}, nil
+}
+
+// Rates returns cancellation and no-show rates. The no-show rate is relative to
+// the reservations that were not cancelled.
+func (s *analyticsService) Rates(ctx context.Context, r domain.AnalyticsRange) (*domain.RatesReport, error) {
+	counts, err := s.repo.StatusCounts(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.RatesReport{
+		Range:            r,
+		Counts:           counts,
+		CancellationRate: ratio(counts.Cancelled, counts.Total),
+		NoShowRate:       ratio(counts.NoShow, counts.Total-counts.Cancelled),
+	}, nil
+}
+
+// LeadTime returns the distribution of how far in advance reservations are made
+func (s *analyticsService) LeadTime(ctx context.Context, r domain.AnalyticsRange) (*domain.LeadTimeReport, error) {
+	buckets, average, err := s.repo.LeadTime(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	// Report every bucket, including the empty ones, with its upper bound
+	counts := map[int]int{}
+	for _, b := range buckets {
+		counts[b.FromHours] = b.Reservations
+	}
+	bounds := domain.LeadTimeBucketsHours
+	report := &domain.LeadTimeReport{Range: r, AverageHours: math.Round(average*10) / 10}
+	for i, from := range bounds {
+		bucket := domain.LeadTimeBucket{FromHours: from, Reservations: counts[from]}
+		if i+1 < len(bounds) {
+			bucket.ToHours = bounds[i+1]
+		}
+		report.Buckets = append(report.Buckets, bucket)
+	}
+
+	return report, nil
+}
+
+func ratio(part, total int) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return math.Round(float64(part)/float64(total)*10000) / 10000
+}
+
+// majorUnits converts an aggregated amount in minor units, rounded to the minor unit
+func (s *analyticsService) majorUnits(v float64) float64 {
+	return math.Round(v) / math.Pow10(domain.CurrencyExponent(s.currency))
+}
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// fakeAnalyticsRepo reports fixed status counts; the rest of the repository is not used
+type fakeAnalyticsRepo struct {
+	repository.AnalyticsRepository
+	totals []domain.StatusTotal
+}
+
+func (r fakeAnalyticsRepo) StatusCounts(context.Context, domain.AnalyticsRange) (domain.StatusCounts, error) {
+	return domain.CountStatuses(r.totals), nil
+}
+
+func TestRatesCountNoShows(t *testing.T) {
+	svc := NewAnalyticsService(fakeAnalyticsRepo{totals: []domain.StatusTotal{
+		{Status: domain.StatusCancelled, Reservations: 2},
+		{Status: domain.StatusNoShow, Reservations: 1},
+		{Status: domain.StatusCompleted, Reservations: 5},
+	}}, domain.DefaultCurrency)
+
+	report, err := svc.Rates(context.Background(), domain.AnalyticsRange{})
+	if err != nil {
+		t.Fatalf("Rates() error = %v", err)
+	}
+	// 2 of 8 cancelled; 1 of the 6 others did not show up
+	if report.CancellationRate != 0.25 || report.NoShowRate != 0.1667 {
+		t.Errorf("rates = %v cancelled, %v no-show, want 0.25 and 0.1667", report.CancellationRate, report.NoShowRate)
+	}
+}
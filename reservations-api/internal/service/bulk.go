@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StartBulkJob selects the reservations of a service and starts cancelling or moving
+// them in the background. The returned job is saved with its selection; its results are
+// filled in as the job runs, each reservation publishing its own cancel or update event.
+// A job interrupted by a restart stays running with the results it saved.
+func (s *reservationService) StartBulkJob(ctx context.Context, createdBy string, req domain.BulkJobRequest) (*domain.BulkJob, error) {
+	job, err := domain.NewBulkJob(req, createdBy, s.now())
+	if err != nil {
+		return nil, err
+	}
+
+	filter, err := req.Select.Filter()
+	if err != nil {
+		return nil, err
+	}
+	var selected []domain.Reservation
+	err = s.repo.Stream(ctx, filter, func(r domain.Reservation) error {
+		if domain.BulkSelectable(&r) {
+			selected = append(selected, r)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to select reservations: %w", err)
+	}
+	job.Total = len(selected)
+
+	if err := s.bulkJobs.Create(ctx, job); err != nil {
+		return nil, err
+	}
+
+	running := *job
+	running.Results = []domain.BulkResult{}
+	go s.runBulkJob(context.WithoutCancel(ctx), &running, selected)
+
+	return job, nil
+}
+
+// runBulkJob applies the job's action to each reservation, saving the job as it goes
+func (s *reservationService) runBulkJob(ctx context.Context, job *domain.BulkJob, reservations []domain.Reservation) {
+	for i := range reservations {
+		reservation := &reservations[i]
+		result := domain.BulkResult{ReservationID: reservation.ID.Hex()}
+
+		var err error
+		switch job.Action {
+		case domain.BulkActionCancel:
+			err = s.bulkCancel(ctx, reservation, job.Reason)
+			result.Status = domain.BulkResultCancelled
+		case domain.BulkActionMove:
+			err = s.bulkMove(ctx, reservation, *job.MoveTo)
+			result.Status = domain.BulkResultMoved
+			result.TableNumber = reservation.TableNumber
+			result.DateTime = &reservation.DateTime
+		}
+		if err != nil {
+			result = domain.BulkResult{ReservationID: result.ReservationID, Status: domain.BulkResultFailed, Error: err.Error()}
+		}
+		job.Record(result)
+
+		if err := s.bulkJobs.Save(ctx, job); err != nil {
+			log.Printf("Warning: failed to save progress of bulk job %s: %v", job.ID.Hex(), err)
+		}
+	}
+
+	job.Complete(s.now())
+	if err := s.bulkJobs.Save(ctx, job); err != nil {
+		log.Printf("Warning: failed to complete bulk job %s: %v", job.ID.Hex(), err)
+	}
+	log.Printf("Bulk job %s (%s): %d succeeded, %d failed", job.ID.Hex(), job.Action, job.Succeeded, job.Failed)
+}
+
+// bulkCancel cancels one reservation of a closed service, free of charge
+func (s *reservationService) bulkCancel(ctx context.Context, reservation *domain.Reservation, reason string) error {
+	status := reservation.Status
+	if err := reservation.CancelByRestaurant(reason, s.now()); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateIfStatus(ctx, reservation.ID, status, reservation); err != nil {
+		return bulkUpdateError(err)
+	}
+
+	// The cancel event tells the guest why, through the webhook subscribers
+	s.publishEvent("cancel", reservation)
+	return nil
+}
+
+// bulkMove rebooks one reservation at the target, which must be free and not held
+func (s *reservationService) bulkMove(ctx context.Context, reservation *domain.Reservation, target domain.BulkMoveTarget) error {
+	status, dateTime, previous := reservation.Status, reservation.DateTime, reservation.Slot()
+	if err := reservation.MoveTo(target, s.floor.Location, s.now()); err != nil {
+		return err
+	}
+
+	if err := s.checkTableFree(ctx, reservation); err != nil {
+		return err
+	}
+
+	// Another day may be priced differently, or not open at all
+	if !reservation.DateTime.Equal(dateTime) {
+		calcResult, err := s.calculator.Run(ctx, reservation.CalculationInput())
+		if err != nil {
+			return fmt.Errorf("calculation failed: %w", err)
+		}
+		if !calcResult.Available {
+			return domain.ConflictError("reservation not available: %s", strings.Join(calcResult.Restrictions, "; "))
+		}
+		reservation.ApplyPrice(calcResult, domain.PriceTriggerUpdate)
+	}
+
+	if err := reservation.Validate(); err != nil {
+		return err
+	}
+	if err := s.repo.UpdateIfStatus(ctx, reservation.ID, status, reservation); err != nil {
+		return bulkUpdateError(err)
+	}
+
+	s.publishMove(reservation, previous)
+	return nil
+}
+
+// bulkUpdateError explains a reservation changed by someone else while the job ran
+func bulkUpdateError(err error) error {
+	if errors.Is(err, domain.ErrConflict) {
+		return domain.ConflictError("reservation changed while the job ran")
+	}
+	return err
+}
+
+// GetBulkJob returns a bulk job with its per-reservation results
+func (s *reservationService) GetBulkJob(ctx context.Context, id string) (*domain.BulkJob, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.NewValidationError("id", "is not a valid bulk job id")
+	}
+	return s.bulkJobs.GetByID(ctx, objectID)
+}
+
+// ListBulkJobs returns the latest bulk jobs, without their results
+func (s *reservationService) ListBulkJobs(ctx context.Context, limit int) ([]domain.BulkJob, error) {
+	return s.bulkJobs.List(ctx, limit)
+}
@@ -0,0 +1,56 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// CalendarFeed issues and verifies the secret tokens that protect per-user calendar feeds
+type CalendarFeed struct {
+	secret  []byte
+	baseURL string
+}
+
+// CalendarFeedLinks holds the subscription URLs of a user's calendar feed
+type CalendarFeedLinks struct {
+	WebcalURL string `json:"webcal_url"`
+	HTTPSURL  string `json:"https_url"`
+}
+
+// NewCalendarFeed creates a new calendar feed token issuer
+func NewCalendarFeed(secret, publicBaseURL string) *CalendarFeed {
+	return &CalendarFeed{
+		secret:  []byte(secret),
+		baseURL: strings.TrimRight(publicBaseURL, "/"),
+	}
+}
+
+// Token returns the feed token for a user; it is stable so subscriptions keep working
+func (f *CalendarFeed) Token(userID string) string {
+	mac := hmac.New(sha256.New, f.secret)
+	mac.Write([]byte("calendar-feed:" + userID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a feed token in constant time
+func (f *CalendarFeed) Verify(userID, token string) bool {
+	return hmac.Equal([]byte(f.Token(userID)), []byte(token))
+}
+
+// Links returns the subscription URLs for a user's feed
+func (f *CalendarFeed) Links(userID string) CalendarFeedLinks {
+	feedURL := fmt.Sprintf("%s/api/reservations/user/%s/calendar.ics?token=%s",
+		f.baseURL, url.PathEscape(userID), f.Token(userID))
+
+	// webcal:// tells calendar clients to subscribe instead of importing once
+	webcalURL := feedURL
+	if i := strings.Index(feedURL, "://"); i >= 0 {
+		webcalURL = "webcal" + feedURL[i:]
+	}
+
+	return CalendarFeedLinks{WebcalURL: webcalURL, HTTPSURL: feedURL}
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// CheckInTokens issues and verifies the signed tokens guests show as a QR code at the host stand
+type CheckInTokens struct {
+	secret []byte
+	window domain.CheckInWindow
+	now    func() time.Time
+}
+
+// CheckInToken is a reservation's check-in token and when it stops being accepted
+type CheckInToken struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// checkInClaims is the signed payload of a check-in token
+type checkInClaims struct {
+	ReservationID string `json:"rid"`
+	// Booked time in Unix milliseconds: rescheduling the reservation invalidates older tokens
+	ScheduledAt int64 `json:"at"`
+	ExpiresAt   int64 `json:"exp"`
+}
+
+// NewCheckInTokens creates a new check-in token issuer
+func NewCheckInTokens(secret string, window domain.CheckInWindow) *CheckInTokens {
+	return &CheckInTokens{secret: []byte(secret), window: window, now: time.Now}
+}
+
+// Window returns the check-in window applied to every reservation
+func (t *CheckInTokens) Window() domain.CheckInWindow {
+	return t.window
+}
+
+// Issue returns the check-in token of a confirmed reservation; it expires when the window closes
+func (t *CheckInTokens) Issue(r *domain.Reservation) (*CheckInToken, error) {
+	if r.Status != domain.StatusConfirmed {
+		return nil, domain.ConflictError("only confirmed reservations have a check-in code")
+	}
+
+	expiresAt := t.window.Closes(r)
+	if t.now().After(expiresAt) {
+		return nil, domain.ConflictError("check-in window has closed")
+	}
+
+	payload, err := json.Marshal(checkInClaims{
+		ReservationID: r.ID.Hex(),
+		ScheduledAt:   r.DateTime.UnixMilli(),
+		ExpiresAt:     expiresAt.Unix(),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	return &CheckInToken{Token: encoded + "." + t.sign(encoded), ExpiresAt: expiresAt}, nil
+}
+
+// verify checks the signature and expiry of a token and returns its claims
+func (t *CheckInTokens) verify(token string) (checkInClaims, error) {
+	var claims checkInClaims
+
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok || !hmac.Equal([]byte(t.sign(encoded)), []byte(signature)) {
+		return claims, domain.ForbiddenError("invalid check-in code")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil || json.Unmarshal(payload, &claims) != nil {
+		return claims, domain.ForbiddenError("invalid check-in code")
+	}
+
+	if t.now().Unix() > claims.ExpiresAt {
+		return claims, domain.ForbiddenError("check-in code has expired")
+	}
+	return claims, nil
+}
+
+func (t *CheckInTokens) sign(encoded string) string {
+	mac := hmac.New(sha256.New, t.secret)
+	mac.Write([]byte("checkin:" + encoded))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// GetCheckInToken returns the check-in token of a confirmed reservation
+func (s *reservationService) GetCheckInToken(r *domain.Reservation) (*CheckInToken, error) {
+	return s.checkIn.Issue(r)
+}
+
+// CheckIn redeems a check-in token and moves the reservation to seated.
+// Forged, expired or outdated tokens are rejected, and a token can only be redeemed once.
+func (s *reservationService) CheckIn(ctx context.Context, token string) (*domain.Reservation, error) {
+	claims, err := s.checkIn.verify(token)
+	if err != nil {
+		return nil, err
+	}
+
+	objectID, err := primitive.ObjectIDFromHex(claims.ReservationID)
+	if err != nil {
+		return nil, domain.ForbiddenError("invalid check-in code")
+	}
+
+	reservation, err := s.repo.GetByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+
+	if reservation.DateTime.UnixMilli() != claims.ScheduledAt {
+		return nil, domain.ForbiddenError("check-in code is outdated: the reservation was rescheduled")
+	}
+
+	if err := reservation.Seat(s.checkIn.Window(), s.checkIn.now()); err != nil {
+		return nil, err
+	}
+
+	// Only one of two concurrent scans of the same code can move it out of confirmed
+	if err := s.repo.UpdateIfStatus(ctx, objectID, domain.StatusConfirmed, reservation); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.ConflictError("reservation already checked in")
+		}
+		return nil, fmt.Errorf("failed to check in reservation: %w", err)
+	}
+
+	s.publishEvent("checkin", reservation)
+
+	return reservation, nil
+}
@@ -0,0 +1,126 @@
+package service
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var testCheckInWindow = domain.CheckInWindow{OpensBefore: time.Hour, ClosesAfter: 90 * time.Minute}
+
+func newTestCheckInTokens(secret string, now time.Time) *CheckInTokens {
+	tokens := NewCheckInTokens(secret, testCheckInWindow)
+	tokens.now = func() time.Time { return now }
+	return tokens
+}
+
+func confirmedReservation(at time.Time) *domain.Reservation {
+	return &domain.Reservation{ID: primitive.NewObjectID(), Status: domain.StatusConfirmed, DateTime: at}
+}
+
+func TestCheckInTokenRoundTrip(t *testing.T) {
+	at := time.Date(2026, 5, 1, 21, 0, 0, 0, time.UTC)
+	tokens := newTestCheckInTokens("secret", at.Add(-3*time.Hour))
+	r := confirmedReservation(at)
+
+	token, err := tokens.Issue(r)
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	if !token.ExpiresAt.Equal(at.Add(90 * time.Minute)) {
+		t.Errorf("ExpiresAt = %v, want %v", token.ExpiresAt, at.Add(90*time.Minute))
+	}
+
+	claims, err := tokens.verify(token.Token)
+	if err != nil {
+		t.Fatalf("verify: %v", err)
+	}
+	if claims.ReservationID != r.ID.Hex() || claims.ScheduledAt != at.UnixMilli() {
+		t.Errorf("claims = %+v, want reservation %s at %d", claims, r.ID.Hex(), at.UnixMilli())
+	}
+}
+
+func TestCheckInTokenRejected(t *testing.T) {
+	at := time.Date(2026, 5, 1, 21, 0, 0, 0, time.UTC)
+	tokens := newTestCheckInTokens("secret", at)
+	token, err := tokens.Issue(confirmedReservation(at))
+	if err != nil {
+		t.Fatalf("Issue: %v", err)
+	}
+	payload, signature, _ := strings.Cut(token.Token, ".")
+
+	tests := []struct {
+		name    string
+		tokens  *CheckInTokens
+		token   string
+		message string
+	}{
+		{"other secret", newTestCheckInTokens("other", at), token.Token, "invalid check-in code"},
+		{"tampered payload", tokens, "x" + payload + "." + signature, "invalid check-in code"},
+		{"missing signature", tokens, payload, "invalid check-in code"},
+		{"garbage", tokens, "not-a-token", "invalid check-in code"},
+		{"expired", newTestCheckInTokens("secret", at.Add(2*time.Hour)), token.Token, "check-in code has expired"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.tokens.verify(tt.token)
+			if !errors.Is(err, domain.ErrForbidden) {
+				t.Fatalf("err = %v, want ErrForbidden", err)
+			}
+			if !strings.Contains(err.Error(), tt.message) {
+				t.Errorf("err = %q, want %q", err, tt.message)
+			}
+		})
+	}
+}
+
+func TestCheckInTokenIssueRequiresOpenConfirmedReservation(t *testing.T) {
+	at := time.Date(2026, 5, 1, 21, 0, 0, 0, time.UTC)
+
+	pending := confirmedReservation(at)
+	pending.Status = domain.StatusPending
+	if _, err := newTestCheckInTokens("secret", at).Issue(pending); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("pending reservation: err = %v, want ErrConflict", err)
+	}
+
+	late := newTestCheckInTokens("secret", at.Add(2*time.Hour))
+	if _, err := late.Issue(confirmedReservation(at)); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("closed window: err = %v, want ErrConflict", err)
+	}
+}
+
+func TestReservationSeat(t *testing.T) {
+	at := time.Date(2026, 5, 1, 21, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		status  string
+		now     time.Time
+		wantErr bool
+	}{
+		{"within window", domain.StatusConfirmed, at.Add(-30 * time.Minute), false},
+		{"too early", domain.StatusConfirmed, at.Add(-2 * time.Hour), true},
+		{"too late", domain.StatusConfirmed, at.Add(2 * time.Hour), true},
+		{"already seated", domain.StatusSeated, at, true},
+		{"cancelled", domain.StatusCancelled, at, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := confirmedReservation(at)
+			r.Status = tt.status
+			err := r.Seat(testCheckInWindow, tt.now)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Seat err = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && (r.Status != domain.StatusSeated || r.SeatedAt == nil) {
+				t.Errorf("reservation not seated: %+v", r)
+			}
+		})
+	}
+}
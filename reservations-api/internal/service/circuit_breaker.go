@@ -0,0 +1,159 @@
+package service
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Circuit breaker states
+const (
+	BreakerClosed   = "closed"    // calls flow normally
+	BreakerOpen     = "open"      // calls are rejected until the cooldown elapses
+	BreakerHalfOpen = "half_open" // a single probe call decides whether to close again
+)
+
+// ErrCircuitOpen is returned when the breaker rejects a call
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// BreakerMetrics is a snapshot of the breaker state and counters
+type BreakerMetrics struct {
+	State               string     `json:"state"`
+	ConsecutiveFailures int        `json:"consecutive_failures"`
+	Successes           uint64     `json:"successes"`
+	Failures            uint64     `json:"failures"`
+	Rejected            uint64     `json:"rejected"`
+	Opened              uint64     `json:"opened"`
+	LastStateChange     time.Time  `json:"last_state_change"`
+	OpenUntil           *time.Time `json:"open_until,omitempty"`
+}
+
+// CircuitBreaker opens after a number of consecutive failures and lets a
+// single probe through once the cooldown has elapsed
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	cooldown         time.Duration
+	now              func() time.Time
+
+	state               string
+	consecutiveFailures int
+	openedAt            time.Time
+	probing             bool
+	lastStateChange     time.Time
+
+	successes uint64
+	failures  uint64
+	rejected  uint64
+	opened    uint64
+}
+
+// NewCircuitBreaker creates a closed breaker
+func NewCircuitBreaker(failureThreshold int, cooldown time.Duration) *CircuitBreaker {
+	if failureThreshold < 1 {
+		failureThreshold = 1
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		cooldown:         cooldown,
+		now:              time.Now,
+		state:            BreakerClosed,
+		lastStateChange:  time.Now(),
+	}
+}
+
+// Allow reports whether a call may proceed. Every allowed call must be
+// followed by exactly one Success or Failure.
+func (b *CircuitBreaker) Allow() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case BreakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			b.rejected++
+			return ErrCircuitOpen
+		}
+		b.setState(BreakerHalfOpen)
+		b.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if b.probing {
+			b.rejected++
+			return ErrCircuitOpen
+		}
+		b.probing = true
+		return nil
+	}
+	return nil
+}
+
+// Success records a successful call and closes the breaker
+func (b *CircuitBreaker) Success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.successes++
+	b.consecutiveFailures = 0
+	b.probing = false
+	if b.state != BreakerClosed {
+		b.setState(BreakerClosed)
+	}
+}
+
+// Failure records a failed call; the breaker opens when the threshold is
+// reached or when the half-open probe fails
+func (b *CircuitBreaker) Failure() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	b.consecutiveFailures++
+	b.probing = false
+	if b.state == BreakerHalfOpen || (b.state == BreakerClosed && b.consecutiveFailures >= b.failureThreshold) {
+		b.openedAt = b.now()
+		b.opened++
+		b.setState(BreakerOpen)
+	}
+}
+
+// Release ends an allowed call without judging the dependency (e.g. the caller
+// cancelled it); a half-open breaker lets the next call probe instead
+func (b *CircuitBreaker) Release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// State returns the current state
+func (b *CircuitBreaker) State() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// Metrics returns a snapshot of the breaker counters
+func (b *CircuitBreaker) Metrics() BreakerMetrics {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	m := BreakerMetrics{
+		State:               b.state,
+		ConsecutiveFailures: b.consecutiveFailures,
+		Successes:           b.successes,
+		Failures:            b.failures,
+		Rejected:            b.rejected,
+		Opened:              b.opened,
+		LastStateChange:     b.lastStateChange,
+	}
+	if b.state == BreakerOpen {
+		until := b.openedAt.Add(b.cooldown)
+		m.OpenUntil = &until
+	}
+	return m
+}
+
+func (b *CircuitBreaker) setState(state string) {
+	b.state = state
+	b.lastStateChange = b.now()
+}
@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EraseUser carries out a user.deleted event. Upcoming reservations are cancelled first,
+// publishing cancel events that free their tables in search-api; then every live and
+// archived reservation of the user is anonymized and a receipt is written. A redelivered
+// event returns the existing receipt, and an interrupted run is finished by the next one.
+func (s *reservationService) EraseUser(ctx context.Context, userID string, requestedAt time.Time) (*domain.ErasureReceipt, error) {
+	if receipt, err := s.erasures.GetReceipt(ctx, userID); err != nil || receipt != nil {
+		return receipt, err
+	}
+
+	receipt := &domain.ErasureReceipt{ID: primitive.NewObjectID(), UserID: userID, RequestedAt: requestedAt}
+	receipt.Tombstone = domain.ErasedOwnerID(receipt.ID)
+
+	reservations, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for i := range reservations {
+		reservation := &reservations[i]
+		status := reservation.Status
+		if !reservation.CancelForErasure(now) {
+			continue
+		}
+		if err := s.repo.UpdateIfStatus(ctx, reservation.ID, status, reservation); err != nil {
+			return nil, err
+		}
+		receipt.Cancelled++
+
+		// Subscribers never see the erased owner, even in the cancel event
+		reservation.Anonymize(receipt.Tombstone)
+		s.publishEvent("cancel", reservation)
+	}
+
+	receipt.Anonymized, receipt.ArchivedAnonymized, err = s.erasures.Anonymize(ctx, userID, receipt.Tombstone, now)
+	if err != nil {
+		return nil, err
+	}
+
+	receipt.CompletedAt = time.Now()
+	if err := s.erasures.SaveReceipt(ctx, receipt); err != nil {
+		return nil, err
+	}
+
+	// A cached lookup would let the deleted user book again until it expired
+	s.userClient.Forget(userID)
+
+	return receipt, nil
+}
+
+// GetErasureReceipt returns the receipt of an erased user
+func (s *reservationService) GetErasureReceipt(ctx context.Context, userID string) (*domain.ErasureReceipt, error) {
+	receipt, err := s.erasures.GetReceipt(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if receipt == nil {
+		return nil, domain.NotFoundError("no erasure receipt for user %s", userID)
+	}
+	return receipt, nil
+}
+
+// RunUserErasure erases the reservations of every user deleted in users-api until the
+// context is cancelled, resubscribing after RabbitMQ errors and failed erasures
+func RunUserErasure(ctx context.Context, publisher *RabbitMQPublisher, queue string, svc ReservationService) {
+	handle := func(ctx context.Context, msg EventMessage) error {
+		if msg.EntityType != "user" || msg.Operation != "deleted" || msg.EntityID == "" {
+			log.Printf("Warning: ignoring user event %s %s", msg.Operation, msg.EntityID)
+			return nil
+		}
+
+		receipt, err := svc.EraseUser(ctx, msg.EntityID, msg.Timestamp)
+		if err != nil {
+			return err
+		}
+		log.Printf("Erased user %s: %d reservations cancelled, %d anonymized, %d archived anonymized",
+			msg.EntityID, receipt.Cancelled, receipt.Anonymized, receipt.ArchivedAnonymized)
+		return nil
+	}
+
+	for ctx.Err() == nil {
+		if err := publisher.ConsumeUserEvents(ctx, queue, handle); err != nil {
+			log.Printf("Warning: user event consumer interrupted: %v; resubscribing", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+)
+
+// Buffered events per live subscriber before it is considered too slow and dropped
+const subscriberBuffer = 64
+
+// EventFilter narrows a live stream to reservations on one date and/or meal type
+type EventFilter struct {
+	Date     string // YYYY-MM-DD, empty for every date
+	MealType string // empty for every meal type
+}
+
+// Matches reports whether an event concerns the filtered reservations
+func (f EventFilter) Matches(msg EventMessage) bool {
+	if f.Date == "" && f.MealType == "" {
+		return true
+	}
+	if msg.Data == nil {
+		return false
+	}
+	if f.Date != "" && msg.Data.DateTime.Format("2006-01-02") != f.Date {
+		return false
+	}
+	return f.MealType == "" || msg.Data.MealType == f.MealType
+}
+
+// EventHub fans reservation events out to live stream subscribers and keeps the
+// latest ones so that reconnecting clients can resume where they left off
+type EventHub struct {
+	mu     sync.Mutex
+	buffer []EventMessage // oldest first, at most size events
+	size   int
+	subs   map[*EventSubscription]struct{}
+}
+
+// EventSubscription is one live stream; Events is closed when the subscriber falls too
+// far behind, and the client is expected to reconnect with its Last-Event-ID
+type EventSubscription struct {
+	Events <-chan EventMessage
+	events chan EventMessage
+	filter EventFilter
+	hub    *EventHub
+}
+
+// NewEventHub creates a hub that remembers the last size events
+func NewEventHub(size int) *EventHub {
+	return &EventHub{size: size, subs: make(map[*EventSubscription]struct{})}
+}
+
+// Subscribe registers a live subscriber and returns the buffered events after
+// lastEventID that match its filter. resumed is false when lastEventID is no longer
+// buffered: events were missed and the client must reload its data.
+func (h *EventHub) Subscribe(filter EventFilter, lastEventID string) (sub *EventSubscription, backlog []EventMessage, resumed bool) {
+	events := make(chan EventMessage, subscriberBuffer)
+	sub = &EventSubscription{Events: events, events: events, filter: filter, hub: h}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.subs[sub] = struct{}{}
+
+	if lastEventID == "" {
+		return sub, nil, true
+	}
+	for i, msg := range h.buffer {
+		if msg.ID == lastEventID {
+			for _, missed := range h.buffer[i+1:] {
+				if filter.Matches(missed) {
+					backlog = append(backlog, missed)
+				}
+			}
+			return sub, backlog, true
+		}
+	}
+	return sub, nil, false
+}
+
+// Publish buffers an event and sends it to every matching subscriber
+func (h *EventHub) Publish(msg EventMessage) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(h.buffer) == h.size {
+		copy(h.buffer, h.buffer[1:])
+		h.buffer = h.buffer[:h.size-1]
+	}
+	h.buffer = append(h.buffer, msg)
+
+	for sub := range h.subs {
+		if !sub.filter.Matches(msg) {
+			continue
+		}
+		select {
+		case sub.events <- msg:
+		default:
+			// Never block the fan-out on a slow client: drop it so it resumes from the buffer
+			h.remove(sub)
+		}
+	}
+}
+
+// Close unregisters the subscription
+func (s *EventSubscription) Close() {
+	s.hub.mu.Lock()
+	defer s.hub.mu.Unlock()
+	s.hub.remove(s)
+}
+
+// remove must be called with the hub lock held
+func (h *EventHub) remove(sub *EventSubscription) {
+	if _, ok := h.subs[sub]; ok {
+		delete(h.subs, sub)
+		close(sub.events)
+	}
+}
+
+// RunEventFanout feeds the hub with the events published by every replica until the
+// context is cancelled, resubscribing after RabbitMQ errors
+func RunEventFanout(ctx context.Context, publisher *RabbitMQPublisher, hub *EventHub) {
+	for ctx.Err() == nil {
+		if err := publisher.ConsumeEvents(ctx, hub.Publish); err != nil {
+			log.Printf("Warning: reservation event stream interrupted: %v; resubscribing", err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(5 * time.Second):
+			}
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+func streamEvent(id int, date, mealType string) EventMessage {
+	at, _ := time.Parse("2006-01-02", date)
+	return EventMessage{
+		ID:        fmt.Sprintf("%03d", id),
+		Operation: "update",
+		Data:      &domain.Reservation{DateTime: at.Add(21 * time.Hour), MealType: mealType},
+	}
+}
+
+func eventIDs(events []EventMessage) []string {
+	ids := []string{}
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func TestEventHubResumesFromLastEventID(t *testing.T) {
+	hub := NewEventHub(3)
+	for i := 1; i <= 4; i++ {
+		hub.Publish(streamEvent(i, "2026-05-01", domain.MealTypeDinner))
+	}
+
+	tests := []struct {
+		name        string
+		lastEventID string
+		wantResumed bool
+		wantBacklog []string
+	}{
+		{"fresh subscriber", "", true, nil},
+		{"buffered id", "002", true, []string{"003", "004"}},
+		{"latest id", "004", true, nil},
+		{"evicted id", "001", false, nil},
+		{"unknown id", "999", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sub, backlog, resumed := hub.Subscribe(EventFilter{}, tt.lastEventID)
+			defer sub.Close()
+			if resumed != tt.wantResumed {
+				t.Errorf("resumed = %v, want %v", resumed, tt.wantResumed)
+			}
+			if got, want := fmt.Sprint(eventIDs(backlog)), fmt.Sprint(tt.wantBacklog); got != want {
+				t.Errorf("backlog = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEventHubFiltersByDateAndMealType(t *testing.T) {
+	hub := NewEventHub(10)
+	hub.Publish(streamEvent(1, "2026-05-01", domain.MealTypeDinner))
+
+	sub, backlog, _ := hub.Subscribe(EventFilter{Date: "2026-05-02", MealType: domain.MealTypeDinner}, "001")
+	defer sub.Close()
+
+	hub.Publish(streamEvent(2, "2026-05-02", domain.MealTypeLunch))
+	hub.Publish(streamEvent(3, "2026-05-01", domain.MealTypeDinner))
+	hub.Publish(streamEvent(4, "2026-05-02", domain.MealTypeDinner))
+	hub.Publish(EventMessage{ID: "005", Operation: "update"})
+
+	if len(backlog) != 0 {
+		t.Errorf("backlog = %v, want none", eventIDs(backlog))
+	}
+	select {
+	case msg := <-sub.Events:
+		if msg.ID != "004" {
+			t.Errorf("received %s, want 004", msg.ID)
+		}
+	default:
+		t.Fatal("matching event was not delivered")
+	}
+	select {
+	case msg := <-sub.Events:
+		t.Errorf("received unexpected event %s", msg.ID)
+	default:
+	}
+}
+
+func TestEventHubDropsSlowSubscribers(t *testing.T) {
+	hub := NewEventHub(subscriberBuffer * 2)
+	slow, _, _ := hub.Subscribe(EventFilter{}, "")
+	defer slow.Close()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		hub.Publish(streamEvent(i, "2026-05-01", domain.MealTypeDinner))
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != subscriberBuffer {
+		t.Errorf("received %d events before being dropped, want %d", received, subscriberBuffer)
+	}
+
+	// The dropped client resumes from the buffer with nothing lost
+	resumed, backlog, ok := hub.Subscribe(EventFilter{}, fmt.Sprintf("%03d", received-1))
+	defer resumed.Close()
+	if !ok || len(backlog) != 1 {
+		t.Errorf("resume: ok=%v backlog=%v, want the one missed event", ok, eventIDs(backlog))
+	}
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// GetFloorStatus returns the live status of every table of a meal type today
+func (s *reservationService) GetFloorStatus(ctx context.Context, mealType string) (*domain.FloorStatus, error) {
+	return s.floorStatus(ctx, mealType, s.now().In(s.floor.Location))
+}
+
+func (s *reservationService) floorStatus(ctx context.Context, mealType string, now time.Time) (*domain.FloorStatus, error) {
+	today := s.floor.Today(now)
+	filter := domain.ReservationFilter{MealType: mealType, From: today, To: today.AddDate(0, 0, 1)}
+
+	var reservations []domain.Reservation
+	if err := s.repo.Stream(ctx, filter, func(r domain.Reservation) error {
+		reservations = append(reservations, r)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed to get today's reservations: %w", err)
+	}
+
+	return domain.NewFloorStatus(mealType, reservations, s.floor, now), nil
+}
+
+// SeatWalkIn seats guests without a reservation at a free table. The walk-in is priced
+// like a reservation, but the booking restrictions are not applied: the host has already
+// chosen to seat them.
+func (s *reservationService) SeatWalkIn(ctx context.Context, req domain.WalkInRequest) (*domain.Reservation, error) {
+	now := s.now().In(s.floor.Location)
+	floor, err := s.floorStatus(ctx, req.MealType, now)
+	if err != nil {
+		return nil, err
+	}
+
+	walkIn, err := floor.SeatWalkIn(req, now)
+	if err != nil {
+		return nil, err
+	}
+	// The floor only knows about reservations; a guest may be holding the table online
+	if err := s.checkHold(ctx, walkIn, ""); err != nil {
+		return nil, err
+	}
+
+	calcResult, err := s.calculator.Run(ctx, walkIn.CalculationInput())
+	if err != nil {
+		return nil, fmt.Errorf("calculation failed: %w", err)
+	}
+	walkIn.ApplyPrice(calcResult, domain.PriceTriggerCreate)
+
+	if err := walkIn.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.saveNewReservation(ctx, walkIn); err != nil {
+		return nil, err
+	}
+
+	return walkIn, nil
+}
+
+// CompleteReservation frees the table of seated guests who have left; it goes into
+// cleaning before it shows as free again
+func (s *reservationService) CompleteReservation(ctx context.Context, id string) (*domain.Reservation, error) {
+	objectID, err := parseReservationID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.GetByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := reservation.Complete(s.now()); err != nil {
+		return nil, err
+	}
+
+	// Only one of two hosts clearing the same table at once moves it out of seated
+	if err := s.repo.UpdateIfStatus(ctx, objectID, domain.StatusSeated, reservation); err != nil {
+		if errors.Is(err, domain.ErrConflict) {
+			return nil, domain.ConflictError("reservation already completed")
+		}
+		return nil, fmt.Errorf("failed to complete reservation: %w", err)
+	}
+
+	s.publishEvent("complete", reservation)
+
+	return reservation, nil
+}
@@ -0,0 +1,156 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// HoldTable holds a free table for the guest while they finish booking it. The returned
+// token books the table through CreateReservation until the hold expires.
+func (s *reservationService) HoldTable(ctx context.Context, ownerID string, req domain.HoldRequest) (*domain.HoldGrant, error) {
+	now := s.now()
+	grant, err := domain.NewHold(req, ownerID, s.holdTTL, now)
+	if err != nil {
+		return nil, err
+	}
+
+	date := req.DateTime.Format("2006-01-02")
+	reservedTables, err := s.repo.GetReservedTableNumbers(ctx, date, req.MealType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check table availability: %w", err)
+	}
+	for _, reservedNum := range reservedTables {
+		if reservedNum == req.TableNumber {
+			return nil, domain.ConflictError("table %d is already reserved for %s on %s", req.TableNumber, req.MealType, date)
+		}
+	}
+
+	if err := s.holds.Create(ctx, grant.Hold, now); err != nil {
+		return nil, err
+	}
+	s.publishHold("hold", grant.Hold)
+
+	return grant, nil
+}
+
+// checkHold rejects booking a table someone else is holding; the holder's token passes,
+// for the holder's own reservations only
+func (s *reservationService) checkHold(ctx context.Context, reservation *domain.Reservation, token string) error {
+	hold, err := s.holds.GetBySlot(ctx, domain.HoldSlot(reservation.DateTime, reservation.MealType, reservation.TableNumber))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("failed to check table holds: %w", err)
+	}
+
+	if hold.Active(s.now()) && !hold.Allows(reservation.OwnerID, token) {
+		return domain.ConflictError("table %d is held until %s", reservation.TableNumber, hold.ExpiresAt.Format(time.RFC3339))
+	}
+	return nil
+}
+
+// checkTableFree fails when the table a reservation moves to is booked by another
+// reservation or held for someone else's checkout
+func (s *reservationService) checkTableFree(ctx context.Context, reservation *domain.Reservation) error {
+	date := reservation.DateTime.Format("2006-01-02")
+	reservedTables, err := s.repo.GetReservedTableNumbers(ctx, date, reservation.MealType)
+	if err != nil {
+		return fmt.Errorf("failed to check table availability: %w", err)
+	}
+	for _, reservedNum := range reservedTables {
+		if reservedNum == reservation.TableNumber {
+			return domain.ConflictError("table %d is already reserved for %s on %s", reservation.TableNumber, reservation.MealType, date)
+		}
+	}
+	return s.checkHold(ctx, reservation, "")
+}
+
+// consumeHold deletes the hold a new reservation booked its table with. The reservation's
+// create event takes the place of the hold in search-api.
+func (s *reservationService) consumeHold(ctx context.Context, reservation *domain.Reservation, token string) {
+	if token == "" {
+		return
+	}
+	slot := domain.HoldSlot(reservation.DateTime, reservation.MealType, reservation.TableNumber)
+	if err := s.holds.Consume(ctx, slot, domain.HashHoldToken(token)); err != nil {
+		log.Printf("Warning: reservation %s booked but its hold was kept: %v", reservation.ID.Hex(), err)
+	}
+}
+
+// publishHold announces a hold on RabbitMQ without blocking the request
+func (s *reservationService) publishHold(operation string, hold *domain.Hold) {
+	snapshot := *hold
+	go func() {
+		if err := s.rmqPublisher.PublishHold(operation, &snapshot); err != nil {
+			log.Printf("Warning: failed to publish %s event: %v", operation, err)
+		}
+	}()
+}
+
+// holdPublisher announces holds (RabbitMQPublisher in production)
+type holdPublisher interface {
+	PublishHold(operation string, hold *domain.Hold) error
+}
+
+// HoldReaper announces the holds that expired. Expired holds stop counting as soon as
+// they expire and the TTL index deletes them later; the reaper publishes their release
+// in between, so search-api shows the table as free again.
+type HoldReaper struct {
+	repo      repository.HoldRepository
+	publisher holdPublisher
+	interval  time.Duration
+	now       func() time.Time
+}
+
+// NewHoldReaper creates a new hold reaper
+func NewHoldReaper(repo repository.HoldRepository, publisher *RabbitMQPublisher, interval time.Duration) *HoldReaper {
+	return &HoldReaper{repo: repo, publisher: publisher, interval: interval, now: time.Now}
+}
+
+// Run releases expired holds on every interval until the context is cancelled
+func (j *HoldReaper) Run(ctx context.Context) {
+	ticker := time.NewTicker(j.interval)
+	defer ticker.Stop()
+
+	for {
+		if n, err := j.RunOnce(ctx); err != nil {
+			log.Printf("hold reaper: %v", err)
+		} else if n > 0 {
+			log.Printf("hold reaper: released %d expired holds", n)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce releases every expired hold not released yet. A hold is claimed before it is
+// announced, so each is released by one replica; a failed announcement is not retried,
+// as the table is free either way and the next reindex of search-api catches up.
+func (j *HoldReaper) RunOnce(ctx context.Context) (int, error) {
+	released := 0
+	for ctx.Err() == nil {
+		hold, err := j.repo.ClaimExpired(ctx, j.now())
+		if err != nil {
+			return released, err
+		}
+		if hold == nil {
+			break
+		}
+		if err := j.publisher.PublishHold("release", hold); err != nil {
+			log.Printf("Warning: failed to publish release of hold %s: %v", hold.ID.Hex(), err)
+		}
+		released++
+	}
+	return released, ctx.Err()
+}
@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeHoldRepo keeps holds by slot; the rest of the repository is not used
+type fakeHoldRepo struct {
+	repository.HoldRepository
+	holds map[string]*domain.Hold
+}
+
+func (r *fakeHoldRepo) GetBySlot(_ context.Context, slot string) (*domain.Hold, error) {
+	if hold, ok := r.holds[slot]; ok {
+		return hold, nil
+	}
+	return nil, domain.NotFoundError("hold not found")
+}
+
+func (r *fakeHoldRepo) ClaimExpired(_ context.Context, now time.Time) (*domain.Hold, error) {
+	for _, hold := range r.holds {
+		if !hold.Active(now) && !hold.Released {
+			hold.Released = true
+			return hold, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *fakeHoldRepo) ListActive(_ context.Context, from, to time.Time, mealType string, now time.Time) ([]domain.Hold, error) {
+	var holds []domain.Hold
+	for _, hold := range r.holds {
+		if hold.Active(now) && hold.MealType == mealType && !hold.DateTime.Before(from) && hold.DateTime.Before(to) {
+			holds = append(holds, *hold)
+		}
+	}
+	return holds, nil
+}
+
+// fakeHoldPublisher records the announced holds
+type fakeHoldPublisher struct {
+	published []string
+}
+
+func (p *fakeHoldPublisher) PublishHold(operation string, hold *domain.Hold) error {
+	p.published = append(p.published, operation+" "+hold.Slot)
+	return nil
+}
+
+func TestSeatWalkInRespectsHolds(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 19, 0, 0, 0, time.UTC)
+	grant, err := domain.NewHold(domain.HoldRequest{TableNumber: 3, Guests: 2, DateTime: now.Add(2 * time.Hour), MealType: domain.MealTypeDinner}, "42", 10*time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	svc := newImportService(t, &fakeTableRepo{})
+	svc.holds = &fakeHoldRepo{holds: map[string]*domain.Hold{grant.Slot: grant.Hold}}
+	svc.now = func() time.Time { return now }
+
+	_, err = svc.SeatWalkIn(context.Background(), domain.WalkInRequest{TableNumber: 3, Guests: 2, MealType: domain.MealTypeDinner})
+	if !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("walk-in at a held table: error = %v, want conflict", err)
+	}
+}
+
+func TestCheckHold(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	grant, err := domain.NewHold(domain.HoldRequest{TableNumber: 3, Guests: 2, DateTime: now.Add(8 * time.Hour), MealType: domain.MealTypeDinner}, "42", 10*time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	repo := &fakeHoldRepo{holds: map[string]*domain.Hold{grant.Slot: grant.Hold}}
+	svc := &reservationService{holds: repo, now: func() time.Time { return now }}
+	held := &domain.Reservation{OwnerID: "42", TableNumber: 3, MealType: domain.MealTypeDinner, DateTime: now.Add(8 * time.Hour)}
+
+	if err := svc.checkHold(context.Background(), held, ""); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("without the token: error = %v, want conflict", err)
+	}
+	if err := svc.checkHold(context.Background(), held, grant.Token); err != nil {
+		t.Errorf("with the token: error = %v", err)
+	}
+	leaked := &domain.Reservation{OwnerID: "43", TableNumber: 3, MealType: domain.MealTypeDinner, DateTime: now.Add(8 * time.Hour)}
+	if err := svc.checkHold(context.Background(), leaked, grant.Token); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("another guest with the token: error = %v, want conflict", err)
+	}
+	free := &domain.Reservation{TableNumber: 4, MealType: domain.MealTypeDinner, DateTime: now.Add(8 * time.Hour)}
+	if err := svc.checkHold(context.Background(), free, ""); err != nil {
+		t.Errorf("another table: error = %v", err)
+	}
+
+	svc.now = func() time.Time { return now.Add(10 * time.Minute) }
+	if err := svc.checkHold(context.Background(), held, ""); err != nil {
+		t.Errorf("after the hold expired: error = %v", err)
+	}
+}
+
+func TestHoldReaperReleasesExpiredHoldsOnce(t *testing.T) {
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeHoldRepo{holds: map[string]*domain.Hold{
+		"expired": {ID: primitive.NewObjectID(), Slot: "expired", ExpiresAt: now.Add(-time.Minute)},
+		"active":  {ID: primitive.NewObjectID(), Slot: "active", ExpiresAt: now.Add(time.Minute)},
+	}}
+	publisher := &fakeHoldPublisher{}
+	reaper := &HoldReaper{repo: repo, publisher: publisher, now: func() time.Time { return now }}
+
+	for run := 0; run < 2; run++ {
+		if _, err := reaper.RunOnce(context.Background()); err != nil {
+			t.Fatalf("RunOnce() error = %v", err)
+		}
+	}
+
+	if len(publisher.published) != 1 || publisher.published[0] != "release expired" {
+		t.Errorf("published = %v, want the expired hold released once", publisher.published)
+	}
+}
+
+func TestQuoteReservationSkipsHeldTables(t *testing.T) {
+	at := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour).Add(21 * time.Hour)
+	hold := func(table int) *domain.HoldGrant {
+		grant, err := domain.NewHold(domain.HoldRequest{TableNumber: table, Guests: 2, DateTime: at, MealType: domain.MealTypeDinner}, "42", 10*time.Minute, time.Now())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return grant
+	}
+	others, mine := hold(3), hold(4)
+
+	svc := newImportService(t, &fakeTableRepo{})
+	svc.holds = &fakeHoldRepo{holds: map[string]*domain.Hold{others.Slot: others.Hold, mine.Slot: mine.Hold}}
+	quote := func(table int, token string) *domain.Quote {
+		q, err := svc.QuoteReservation(context.Background(), domain.CreateReservationRequest{
+			OwnerID: "42", TableNumber: table, Guests: 2, DateTime: at, MealType: domain.MealTypeDinner, HoldToken: token,
+		})
+		if err != nil {
+			t.Fatalf("QuoteReservation() error = %v", err)
+		}
+		return q
+	}
+
+	q := quote(4, mine.Token)
+	if !q.Available {
+		t.Errorf("own held table: restrictions = %v, want it available", q.Restrictions)
+	}
+	for _, table := range q.Alternatives {
+		if table.TableNumber == 3 {
+			t.Errorf("alternatives = %+v, want table 3 left out while held", q.Alternatives)
+		}
+	}
+
+	if q := quote(3, mine.Token); q.Available || len(q.Restrictions) == 0 || !strings.Contains(q.Restrictions[0], "held") {
+		t.Errorf("someone else's held table: restrictions = %v, want it held", q.Restrictions)
+	}
+	if q := quote(4, ""); q.Available {
+		t.Error("own held table without the token: want it held")
+	}
+}
+
+func TestGetAvailableTablesSkipsHeldTables(t *testing.T) {
+	loc, err := time.LoadLocation("America/Argentina/Buenos_Aires")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2030, time.March, 1, 12, 0, 0, 0, loc)
+	// A late dinner is already the next day in UTC
+	grant, err := domain.NewHold(domain.HoldRequest{TableNumber: 4, Guests: 2, DateTime: time.Date(2030, time.March, 1, 22, 30, 0, 0, loc), MealType: domain.MealTypeDinner}, "42", 10*time.Minute, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	svc := newImportService(t, &fakeTableRepo{reserved: []int{1}})
+	svc.holds = &fakeHoldRepo{holds: map[string]*domain.Hold{grant.Slot: grant.Hold}}
+	svc.floor.Location = loc
+	svc.now = func() time.Time { return now }
+
+	tables, err := svc.GetAvailableTables(context.Background(), "2030-03-01", domain.MealTypeDinner)
+	if err != nil {
+		t.Fatalf("GetAvailableTables() error = %v", err)
+	}
+	for _, table := range tables {
+		if table.TableNumber == 1 || table.TableNumber == 4 {
+			t.Errorf("tables = %+v, want the reserved table 1 and the held table 4 left out", tables)
+		}
+	}
+	if len(tables) != len(domain.GetTablesForMealType(domain.MealTypeDinner))-2 {
+		t.Errorf("got %d free tables, want all but 2", len(tables))
+	}
+
+	var verr *domain.ValidationError
+	if _, err := svc.GetAvailableTables(context.Background(), "01/03/2030", domain.MealTypeDinner); !errors.As(err, &verr) || verr.Fields["date"] == "" {
+		t.Errorf("bad date: error = %v, want a date validation error", err)
+	}
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// ImportReservations validates every row through the same rules as CreateReservation and,
+// unless dryRun is set, creates the valid ones. Rows are independent: a failing row never
+// stops the import.
+func (s *reservationService) ImportReservations(ctx context.Context, rows []domain.ImportRow, dryRun bool) domain.ImportReport {
+	report := domain.ImportReport{
+		DryRun: dryRun,
+		Total:  len(rows),
+		Rows:   make([]domain.ImportRowResult, 0, len(rows)),
+	}
+
+	// Tables claimed by earlier rows of this file; in dry-run mode they are not
+	// in the database yet, so conflicts inside the file are tracked here
+	claimed := map[string]int{}
+
+	for _, row := range rows {
+		result := domain.ImportRowResult{Line: row.Line}
+
+		reservation, err := s.importRow(ctx, row, claimed, dryRun)
+
+		switch {
+		case err != nil:
+			result.Status = domain.ImportRowFailed
+			result.Error = err.Error()
+			report.Failed++
+		case dryRun:
+			result.Status = domain.ImportRowValid
+			report.Succeeded++
+		default:
+			result.Status = domain.ImportRowCreated
+			result.ReservationID = reservation.ID.Hex()
+			report.Succeeded++
+		}
+
+		report.Rows = append(report.Rows, result)
+	}
+
+	return report
+}
+
+// importRow builds (and unless dryRun, saves) a reservation from an import row,
+// rejecting tables already claimed earlier in the file
+func (s *reservationService) importRow(ctx context.Context, row domain.ImportRow, claimed map[string]int, dryRun bool) (*domain.Reservation, error) {
+	if row.Error != "" {
+		return nil, errors.New(row.Error)
+	}
+
+	reservation, err := s.buildReservation(ctx, row.Request)
+	if err != nil {
+		return nil, err
+	}
+
+	slot := domain.HoldSlot(reservation.DateTime, reservation.MealType, reservation.TableNumber)
+	if line, taken := claimed[slot]; taken {
+		return nil, domain.ConflictError("table %d is already reserved for %s on %s by line %d",
+			reservation.TableNumber, reservation.MealType, reservation.DateTime.Format("2006-01-02"), line)
+	}
+
+	if !dryRun {
+		if err := s.saveNewReservation(ctx, reservation); err != nil {
+			return nil, err
+		}
+	}
+	claimed[slot] = row.Line
+
+	return reservation, nil
+}
+
+// ExportReservations streams every reservation matching the filter to fn
+func (s *reservationService) ExportReservations(ctx context.Context, filter domain.ReservationFilter, fn func(domain.Reservation) error) error {
+	return s.repo.Stream(ctx, filter, fn)
+}
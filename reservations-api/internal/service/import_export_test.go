@@ -0,0 +1,118 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/transfer"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeTableRepo reports a fixed set of booked tables and streams canned reservations
+type fakeTableRepo struct {
+	repository.ReservationRepository
+	reserved     []int
+	reservations []domain.Reservation
+}
+
+func (r *fakeTableRepo) GetReservedTableNumbers(context.Context, string, string) ([]int, error) {
+	return r.reserved, nil
+}
+
+func (r *fakeTableRepo) Stream(_ context.Context, _ domain.ReservationFilter, fn func(domain.Reservation) error) error {
+	for _, reservation := range r.reservations {
+		if err := fn(reservation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func newImportService(t *testing.T, repo repository.ReservationRepository) *reservationService {
+	users := newFlakyUsersAPI(t, 0, 0)
+	return &reservationService{
+		repo:       repo,
+		userClient: NewUserClient(users.server.URL, testUserClientConfig()),
+		holds:      &fakeHoldRepo{holds: map[string]*domain.Hold{}},
+		calculator: domain.DefaultCalculationPipeline(nil, domain.DefaultPricing()),
+		policies:   domain.DefaultCancellationPolicies(),
+		floor:      domain.FloorPolicy{Location: time.UTC},
+		now:        time.Now,
+	}
+}
+
+func TestImportReservationsDryRun(t *testing.T) {
+	at := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour).Add(21 * time.Hour)
+	row := func(line, table int) domain.ImportRow {
+		return domain.ImportRow{Line: line, Request: domain.CreateReservationRequest{
+			OwnerID: "7", TableNumber: table, Guests: 2, DateTime: at, MealType: domain.MealTypeDinner,
+		}}
+	}
+	otherMeal := row(5, 3)
+	otherMeal.Request.MealType = domain.MealTypeLunch
+
+	svc := newImportService(t, &fakeTableRepo{reserved: []int{4}})
+	report := svc.ImportReservations(context.Background(), []domain.ImportRow{
+		row(2, 3),
+		row(3, 3), // same table as line 2
+		row(4, 4), // already booked
+		otherMeal, // same table, another meal
+		{Line: 6, Error: "invalid guests \"x\""},
+	}, true)
+
+	want := []struct {
+		status string
+		error  string
+	}{
+		{domain.ImportRowValid, ""},
+		{domain.ImportRowFailed, "by line 2"},
+		{domain.ImportRowFailed, "already reserved"},
+		{domain.ImportRowValid, ""},
+		{domain.ImportRowFailed, "invalid guests"},
+	}
+	if report.Total != 5 || report.Succeeded != 2 || report.Failed != 3 || !report.DryRun {
+		t.Errorf("report = %+v", report)
+	}
+	for i, result := range report.Rows {
+		if result.Status != want[i].status || !strings.Contains(result.Error, want[i].error) {
+			t.Errorf("line %d = %s %q, want %s %q", result.Line, result.Status, result.Error, want[i].status, want[i].error)
+		}
+	}
+}
+
+func TestExportReservationsStreams(t *testing.T) {
+	reservations := make([]domain.Reservation, 3)
+	for i := range reservations {
+		reservations[i] = domain.Reservation{ID: primitive.NewObjectID(), OwnerID: "7", TableNumber: i + 1, Guests: 2, MealType: domain.MealTypeDinner}
+	}
+	svc := &reservationService{repo: &fakeTableRepo{reservations: reservations}}
+
+	var buf bytes.Buffer
+	w, err := transfer.NewWriter(&buf, transfer.FormatNDJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := svc.ExportReservations(context.Background(), domain.ReservationFilter{}, w.Write); err != nil {
+		t.Fatalf("ExportReservations() error = %v", err)
+	}
+	if lines := strings.Count(buf.String(), "\n"); lines != len(reservations) {
+		t.Errorf("exported %d records, want %d", lines, len(reservations))
+	}
+
+	// A failing writer (e.g. the client went away) stops the export
+	written := 0
+	stop := errors.New("client gone")
+	err = svc.ExportReservations(context.Background(), domain.ReservationFilter{}, func(domain.Reservation) error {
+		written++
+		return stop
+	})
+	if !errors.Is(err, stop) || written != 1 {
+		t.Errorf("ExportReservations() = %v after %d records, want the writer's error after 1", err, written)
+	}
+}
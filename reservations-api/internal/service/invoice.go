@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// InvoiceConfig describes the issuer and numbering of invoices
+type InvoiceConfig struct {
+	Restaurant domain.RestaurantDetails
+	// NumberPrefix is printed before the sequence number, e.g. "A-"
+	NumberPrefix string
+}
+
+// InvoiceService issues the invoices of reservations
+type InvoiceService interface {
+	// GetInvoice returns the invoice of a reservation, issuing it on first request
+	GetInvoice(ctx context.Context, reservation *domain.Reservation) (*domain.Invoice, error)
+}
+
+// customerDirectory looks up the billed customer (UserClient in production)
+type customerDirectory interface {
+	GetUser(ctx context.Context, userID string) (*UserResponse, error)
+}
+
+// invoiceService implements InvoiceService
+type invoiceService struct {
+	repo  repository.InvoiceRepository
+	users customerDirectory
+	cfg   InvoiceConfig
+	now   func() time.Time
+}
+
+// NewInvoiceService creates a new invoice service
+func NewInvoiceService(repo repository.InvoiceRepository, userClient *UserClient, cfg InvoiceConfig) InvoiceService {
+	return &invoiceService{repo: repo, users: userClient, cfg: cfg, now: time.Now}
+}
+
+// GetInvoice returns the issued invoice unchanged, so downloading it again always yields
+// the same document. Otherwise it snapshots the reservation price and the customer and
+// numbers the invoice.
+func (s *invoiceService) GetInvoice(ctx context.Context, reservation *domain.Reservation) (*domain.Invoice, error) {
+	if invoice, err := s.repo.GetByReservation(ctx, reservation.ID); err != nil || invoice != nil {
+		return invoice, err
+	}
+	if err := reservation.CanInvoice(); err != nil {
+		return nil, err
+	}
+
+	customer, err := s.customer(ctx, reservation.OwnerID)
+	if err != nil {
+		return nil, err
+	}
+
+	invoice := domain.NewInvoice(reservation, s.cfg.Restaurant, customer, s.now())
+	return s.repo.Issue(ctx, invoice, s.cfg.NumberPrefix)
+}
+
+// customer fetches the billed customer through the Users API. Users it no longer knows
+// (deleted accounts) are billed as an unnamed final consumer.
+func (s *invoiceService) customer(ctx context.Context, userID string) (domain.InvoiceCustomer, error) {
+	user, err := s.users.GetUser(ctx, userID)
+	if errors.Is(err, ErrUserNotFound) {
+		return domain.InvoiceCustomer{UserID: userID, Name: domain.UnknownCustomerName}, nil
+	}
+	if err != nil {
+		return domain.InvoiceCustomer{}, userLookupError(err)
+	}
+
+	name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+	if name == "" {
+		name = user.Username
+	}
+	return domain.InvoiceCustomer{UserID: userID, Name: name, Email: user.Email}, nil
+}
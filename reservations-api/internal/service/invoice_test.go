@@ -0,0 +1,121 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeInvoiceRepo numbers invoices from an in-memory sequence
+type fakeInvoiceRepo struct {
+	byReservation map[primitive.ObjectID]*domain.Invoice
+	last          int64
+}
+
+func (r *fakeInvoiceRepo) GetByReservation(_ context.Context, id primitive.ObjectID) (*domain.Invoice, error) {
+	return r.byReservation[id], nil
+}
+
+func (r *fakeInvoiceRepo) Issue(_ context.Context, invoice *domain.Invoice, prefix string) (*domain.Invoice, error) {
+	r.last++
+	invoice.Sequence = r.last
+	invoice.Number = domain.FormatInvoiceNumber(prefix, r.last)
+	r.byReservation[invoice.ReservationID] = invoice
+	return invoice, nil
+}
+
+// fakeDirectory serves users by ID; a nil entry means the Users API is down
+type fakeDirectory map[string]*UserResponse
+
+func (d fakeDirectory) GetUser(_ context.Context, userID string) (*UserResponse, error) {
+	user, ok := d[userID]
+	switch {
+	case !ok:
+		return nil, ErrUserNotFound
+	case user == nil:
+		return nil, ErrUsersAPIUnavailable
+	}
+	return user, nil
+}
+
+func newTestInvoiceService(users fakeDirectory) (*invoiceService, *fakeInvoiceRepo) {
+	repo := &fakeInvoiceRepo{byReservation: map[primitive.ObjectID]*domain.Invoice{}}
+	return &invoiceService{
+		repo:  repo,
+		users: users,
+		cfg:   InvoiceConfig{Restaurant: domain.RestaurantDetails{Name: "Restaurante"}, NumberPrefix: "A-"},
+		now:   func() time.Time { return time.Date(2030, time.March, 2, 12, 0, 0, 0, time.UTC) },
+	}, repo
+}
+
+func invoicedReservation(ownerID string) *domain.Reservation {
+	return &domain.Reservation{
+		ID:         primitive.NewObjectID(),
+		OwnerID:    ownerID,
+		Status:     domain.StatusConfirmed,
+		MealType:   domain.MealTypeDinner,
+		Guests:     2,
+		TotalPrice: domain.NewMoney(7600, "ARS"),
+	}
+}
+
+func TestGetInvoiceIssuesOnceWithSequentialNumbers(t *testing.T) {
+	svc, _ := newTestInvoiceService(fakeDirectory{"7": {FirstName: "Ana", LastName: "Gómez", Email: "ana@example.com"}})
+	first, second := invoicedReservation("7"), invoicedReservation("7")
+
+	a, err := svc.GetInvoice(context.Background(), first)
+	if err != nil {
+		t.Fatalf("GetInvoice() error = %v", err)
+	}
+	b, err := svc.GetInvoice(context.Background(), second)
+	if err != nil {
+		t.Fatalf("GetInvoice() error = %v", err)
+	}
+	if a.Number != "A-00000001" || b.Number != "A-00000002" {
+		t.Errorf("numbers = %s, %s, want A-00000001, A-00000002", a.Number, b.Number)
+	}
+	if a.Customer.Name != "Ana Gómez" || a.Customer.Email != "ana@example.com" {
+		t.Errorf("customer = %+v", a.Customer)
+	}
+	if a.Price.Total != first.TotalPrice {
+		t.Errorf("invoice total = %v, want %v", a.Price.Total, first.TotalPrice)
+	}
+
+	// Downloading again returns the issued invoice, even after the reservation changed
+	first.TotalPrice = domain.NewMoney(9900, "ARS")
+	again, err := svc.GetInvoice(context.Background(), first)
+	if err != nil {
+		t.Fatalf("GetInvoice() error = %v", err)
+	}
+	if again.Number != a.Number || again.Price.Total.Amount != 7600 {
+		t.Errorf("re-download = %s %v, want the issued %s 76.00", again.Number, again.Price.Total, a.Number)
+	}
+}
+
+func TestGetInvoiceRules(t *testing.T) {
+	svc, repo := newTestInvoiceService(fakeDirectory{"down": nil})
+
+	pending := invoicedReservation("7")
+	pending.Status = domain.StatusPending
+	if _, err := svc.GetInvoice(context.Background(), pending); !errors.Is(err, domain.ErrConflict) {
+		t.Errorf("pending reservation: error = %v, want a conflict", err)
+	}
+
+	// Deleted accounts are billed as a final consumer
+	inv, err := svc.GetInvoice(context.Background(), invoicedReservation("erased-1"))
+	if err != nil || inv.Customer.Name != domain.UnknownCustomerName {
+		t.Errorf("unknown customer: invoice %+v, error %v", inv, err)
+	}
+
+	// No number is taken when the customer cannot be looked up
+	if _, err := svc.GetInvoice(context.Background(), invoicedReservation("down")); !errors.Is(err, domain.ErrUpstream) {
+		t.Errorf("users API down: error = %v, want an upstream error", err)
+	}
+	if repo.last != 1 {
+		t.Errorf("%d numbers taken, want 1", repo.last)
+	}
+}
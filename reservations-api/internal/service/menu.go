@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// MenuService manages the menu catalog and the kitchen prep list of the pre-orders
+type MenuService interface {
+	ListMenu(ctx context.Context, filter domain.MenuFilter) ([]domain.MenuItem, error)
+	CreateMenuItem(ctx context.Context, req domain.MenuItemRequest) (*domain.MenuItem, error)
+	UpdateMenuItem(ctx context.Context, id string, req domain.MenuItemRequest) (*domain.MenuItem, error)
+	// DeactivateMenuItem withdraws an item from the menu; existing pre-orders keep it
+	DeactivateMenuItem(ctx context.Context, id string) error
+	PrepReport(ctx context.Context, rng domain.AnalyticsRange) (*domain.PrepReport, error)
+}
+
+// menuService implements MenuService
+type menuService struct {
+	repo repository.MenuRepository
+	// currency the menu is priced in, the pricing currency
+	currency string
+	// location whose days the prep list is grouped by
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewMenuService creates a new menu service
+func NewMenuService(repo repository.MenuRepository, currency string, location *time.Location) MenuService {
+	return &menuService{repo: repo, currency: currency, location: location, now: time.Now}
+}
+
+// ListMenu returns the menu items matching the filter
+func (s *menuService) ListMenu(ctx context.Context, filter domain.MenuFilter) ([]domain.MenuItem, error) {
+	return s.repo.List(ctx, filter)
+}
+
+// CreateMenuItem adds an item to the menu
+func (s *menuService) CreateMenuItem(ctx context.Context, req domain.MenuItemRequest) (*domain.MenuItem, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	item := &domain.MenuItem{}
+	req.Apply(item, s.currency, s.now())
+	if err := s.repo.Create(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// UpdateMenuItem replaces a menu item. Reservations already pre-ordering it keep the
+// name and price they ordered at.
+func (s *menuService) UpdateMenuItem(ctx context.Context, id string, req domain.MenuItemRequest) (*domain.MenuItem, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	item, err := s.getMenuItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	req.Apply(item, s.currency, s.now())
+	if err := s.repo.Replace(ctx, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// DeactivateMenuItem marks a menu item inactive
+func (s *menuService) DeactivateMenuItem(ctx context.Context, id string) error {
+	item, err := s.getMenuItem(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !item.Active {
+		return nil
+	}
+
+	item.Active = false
+	item.UpdatedAt = s.now()
+	return s.repo.Replace(ctx, item)
+}
+
+func (s *menuService) getMenuItem(ctx context.Context, id string) (*domain.MenuItem, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return nil, domain.NotFoundError("menu item not found")
+	}
+	return s.repo.GetByID(ctx, objectID)
+}
+
+// PrepReport returns the pre-ordered portions per day, meal and item, with the meals of
+// a day in service order
+func (s *menuService) PrepReport(ctx context.Context, rng domain.AnalyticsRange) (*domain.PrepReport, error) {
+	lines, err := s.repo.PrepList(ctx, rng, s.location)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.SliceStable(lines, func(i, j int) bool {
+		a, b := lines[i], lines[j]
+		if a.Date != b.Date {
+			return a.Date < b.Date
+		}
+		if a.MealType != b.MealType {
+			return domain.MealOrder(a.MealType) < domain.MealOrder(b.MealType)
+		}
+		return a.Name < b.Name
+	})
+
+	return &domain.PrepReport{Range: rng, Lines: lines}, nil
+}
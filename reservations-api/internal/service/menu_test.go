@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeMenuRepo keeps the menu in memory and returns canned prep lines
+type fakeMenuRepo struct {
+	items map[primitive.ObjectID]domain.MenuItem
+	prep  []domain.PrepLine
+}
+
+func (r *fakeMenuRepo) Create(_ context.Context, item *domain.MenuItem) error {
+	item.ID = primitive.NewObjectID()
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *fakeMenuRepo) GetByID(_ context.Context, id primitive.ObjectID) (*domain.MenuItem, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return nil, domain.NotFoundError("menu item not found")
+	}
+	return &item, nil
+}
+
+func (r *fakeMenuRepo) GetByIDs(_ context.Context, ids []primitive.ObjectID) (map[primitive.ObjectID]domain.MenuItem, error) {
+	return r.items, nil
+}
+
+func (r *fakeMenuRepo) List(_ context.Context, _ domain.MenuFilter) ([]domain.MenuItem, error) {
+	return nil, nil
+}
+
+func (r *fakeMenuRepo) Replace(_ context.Context, item *domain.MenuItem) error {
+	r.items[item.ID] = *item
+	return nil
+}
+
+func (r *fakeMenuRepo) PrepList(_ context.Context, _ domain.AnalyticsRange, _ *time.Location) ([]domain.PrepLine, error) {
+	return r.prep, nil
+}
+
+func TestPrepReportOrdersMealsByService(t *testing.T) {
+	repo := &fakeMenuRepo{prep: []domain.PrepLine{
+		{Date: "2030-03-02", MealType: domain.MealTypeBreakfast, Name: "Medialunas", Quantity: 12},
+		{Date: "2030-03-01", MealType: domain.MealTypeDinner, Name: "Provoleta", Quantity: 3},
+		{Date: "2030-03-01", MealType: domain.MealTypeLunch, Name: "Flan", Quantity: 2},
+		{Date: "2030-03-01", MealType: domain.MealTypeDinner, Name: "Bife de chorizo", Quantity: 4},
+	}}
+	svc := NewMenuService(repo, "ARS", time.UTC)
+
+	report, err := svc.PrepReport(context.Background(), domain.AnalyticsRange{})
+	if err != nil {
+		t.Fatalf("PrepReport() error = %v", err)
+	}
+
+	want := []string{"Flan", "Bife de chorizo", "Provoleta", "Medialunas"}
+	for i, line := range report.Lines {
+		if line.Name != want[i] {
+			t.Fatalf("line %d = %s, want the order %v", i, line.Name, want)
+		}
+	}
+
+	header, rows := report.Table()
+	if len(header) != 7 || len(rows) != 4 || rows[0][3] != "2" {
+		t.Errorf("table = %v %v", header, rows)
+	}
+}
+
+func TestDeactivateMenuItemKeepsIt(t *testing.T) {
+	repo := &fakeMenuRepo{items: map[primitive.ObjectID]domain.MenuItem{}}
+	svc := NewMenuService(repo, "ARS", time.UTC)
+
+	item, err := svc.CreateMenuItem(context.Background(), domain.MenuItemRequest{
+		Name: "Provoleta", Category: domain.MenuCategoryStarter, Price: 1200, MealTypes: []string{domain.MealTypeDinner},
+	})
+	if err != nil {
+		t.Fatalf("CreateMenuItem() error = %v", err)
+	}
+	if err := svc.DeactivateMenuItem(context.Background(), item.ID.Hex()); err != nil {
+		t.Fatalf("DeactivateMenuItem() error = %v", err)
+	}
+
+	stored, ok := repo.items[item.ID]
+	if !ok || stored.Active || stored.ServedAt(domain.MealTypeDinner) {
+		t.Errorf("stored item = %+v, want it kept but inactive", stored)
+	}
+	if err := svc.DeactivateMenuItem(context.Background(), "nope"); err == nil {
+		t.Error("deactivating a malformed ID succeeded")
+	}
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// UpdatePreOrder replaces the menu items pre-ordered with a reservation and reprices it.
+// Items are copied from the current menu; the booking itself is not re-checked.
+func (s *reservationService) UpdatePreOrder(ctx context.Context, reservation *domain.Reservation, req domain.PreOrderRequest) (*domain.Reservation, error) {
+	if err := reservation.CanEditPreOrder(s.preOrderCutoff, time.Now()); err != nil {
+		return nil, err
+	}
+
+	ids, err := req.MenuItemIDs()
+	if err != nil {
+		return nil, err
+	}
+	menu, err := s.menu.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	lines, err := domain.NewPreOrder(req, menu, reservation.MealType)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation.PreOrder = lines
+	calcResult, err := s.calculator.Run(ctx, reservation.CalculationInput())
+	if err != nil {
+		return nil, fmt.Errorf("calculation failed: %w", err)
+	}
+	reservation.ApplyPrice(calcResult, domain.PriceTriggerPreOrder)
+
+	// A cancellation racing with the edit wins
+	if err := s.repo.UpdateIfStatus(ctx, reservation.ID, reservation.Status, reservation); err != nil {
+		return nil, err
+	}
+
+	s.publishEvent("update", reservation)
+
+	return reservation, nil
+}
+
+// checkPreOrderServed refuses a meal type change that leaves pre-ordered items the
+// new meal does not serve
+func (s *reservationService) checkPreOrderServed(ctx context.Context, reservation *domain.Reservation) error {
+	if len(reservation.PreOrder) == 0 {
+		return nil
+	}
+
+	ids := make([]primitive.ObjectID, 0, len(reservation.PreOrder))
+	for _, line := range reservation.PreOrder {
+		ids = append(ids, line.MenuItemID)
+	}
+	menu, err := s.menu.GetByIDs(ctx, ids)
+	if err != nil {
+		return err
+	}
+
+	for _, line := range reservation.PreOrder {
+		if item, ok := menu[line.MenuItemID]; !ok || !item.ServedAt(reservation.MealType) {
+			return domain.ConflictError("%s is not served at %s; change the pre-order first", line.Name, reservation.MealType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// RecordPrepayment records a payment the guest made ahead of the reservation
+func (s *reservationService) RecordPrepayment(ctx context.Context, id string, req domain.RecordPrepaymentRequest) (*domain.Reservation, error) {
+	objectID, err := parseReservationID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	reservation, err := s.repo.GetByID(ctx, objectID)
+	if err != nil {
+		return nil, err
+	}
+	if err := reservation.RecordPrepayment(req, time.Now()); err != nil {
+		return nil, err
+	}
+
+	// A cancellation racing with the payment wins
+	if err := s.repo.UpdateIfStatus(ctx, reservation.ID, reservation.Status, reservation); err != nil {
+		return nil, err
+	}
+
+	s.publishEvent("update", reservation)
+
+	return reservation, nil
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// QuoteReservation prices a reservation request and checks its availability
+// without persisting anything or publishing events
+func (s *reservationService) QuoteReservation(ctx context.Context, req domain.CreateReservationRequest) (*domain.Quote, error) {
+	reservation := domain.NewReservation(req)
+	if err := reservation.Validate(); err != nil {
+		return nil, err
+	}
+
+	date := reservation.DateTime.Format("2006-01-02")
+	reservedTables, err := s.repo.GetReservedTableNumbers(ctx, date, reservation.MealType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check table availability: %w", err)
+	}
+	reserved := make(map[int]bool, len(reservedTables))
+	for _, tableNumber := range reservedTables {
+		reserved[tableNumber] = true
+	}
+
+	// Tables held during someone else's checkout are taken too; the guest's own
+	// hold, presented with its token, is not
+	day, err := s.serviceDay(date)
+	if err != nil {
+		return nil, err
+	}
+	holds, err := s.holds.ListActive(ctx, day, day.AddDate(0, 0, 1), reservation.MealType, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held tables: %w", err)
+	}
+	heldUntil := make(map[int]time.Time, len(holds))
+	for _, hold := range holds {
+		if !hold.Allows(reservation.OwnerID, req.HoldToken) {
+			heldUntil[hold.TableNumber] = hold.ExpiresAt
+		}
+	}
+
+	calcResult, err := s.calculator.Run(ctx, reservation.CalculationInput())
+	if err != nil {
+		return nil, fmt.Errorf("calculation failed: %w", err)
+	}
+
+	restrictions := []string{}
+	if reserved[reservation.TableNumber] {
+		restrictions = append(restrictions, fmt.Sprintf("table %d is already reserved for %s on %s", reservation.TableNumber, reservation.MealType, date))
+	} else if until, held := heldUntil[reservation.TableNumber]; held {
+		restrictions = append(restrictions, fmt.Sprintf("table %d is held until %s", reservation.TableNumber, until.Format(time.RFC3339)))
+	}
+	if err := reservation.CheckTable(); err != nil {
+		restrictions = append(restrictions, err.Error())
+	}
+	restrictions = append(restrictions, calcResult.Restrictions...)
+
+	policy := s.policies.For(reservation.MealType)
+	return &domain.Quote{
+		TableNumber:        reservation.TableNumber,
+		Guests:             reservation.Guests,
+		DateTime:           reservation.DateTime,
+		MealType:           reservation.MealType,
+		Available:          len(restrictions) == 0,
+		BasePrice:          calcResult.BasePrice,
+		Discounts:          calcResult.Discounts,
+		DiscountTotal:      calcResult.Discount,
+		Subtotal:           calcResult.Subtotal,
+		Charges:            calcResult.Charges,
+		FinalPrice:         calcResult.FinalPrice,
+		RulesVersion:       calcResult.RulesVersion,
+		Restrictions:       restrictions,
+		Alternatives:       alternativeTables(reservation, reserved, heldUntil),
+		CancellationPolicy: &policy,
+	}, nil
+}
+
+// alternativeTables returns the free tables other than the requested one that
+// seat the party and meet its accessibility needs, best fit first. Reserved and
+// held tables are not free.
+func alternativeTables(reservation domain.Reservation, reserved map[int]bool, heldUntil map[int]time.Time) []domain.TableConfig {
+	alternatives := []domain.TableConfig{}
+	for _, table := range domain.GetTablesForMealType(reservation.MealType) {
+		_, held := heldUntil[table.TableNumber]
+		if table.TableNumber == reservation.TableNumber || reserved[table.TableNumber] || held {
+			continue
+		}
+		if table.Capacity < reservation.Guests || !reservation.Accessibility.Allows(table) {
+			continue
+		}
+		alternatives = append(alternatives, table)
+	}
+
+	sort.SliceStable(alternatives, func(i, j int) bool {
+		return alternatives[i].Capacity < alternatives[j].Capacity
+	})
+	return alternatives
+}
@@ -7,7 +7,9 @@ import (
 	"log"
 	"time"
 
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
 	amqp "github.com/rabbitmq/amqp091-go"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 // RabbitMQPublisher handles publishing messages to RabbitMQ
@@ -20,10 +22,18 @@ type RabbitMQPublisher struct {
 
 // EventMessage represents the message format for RabbitMQ
 type EventMessage struct {
-	Operation  string    `json:"operation"`   // create, update, delete
-	EntityID   string    `json:"entity_id"`   // reservation ID
-	EntityType string    `json:"entity_type"` // always "reservation"
+	// ID is unique and increasing, so live streams can resume after it (Last-Event-ID)
+	ID         string    `json:"id"`
+	Operation  string    `json:"operation"`   // create, update, confirm, cancel, checkin, complete; hold, release for holds; deleted for users
+	EntityID   string    `json:"entity_id"`   // reservation or hold ID, or user ID for users-api events
+	EntityType string    `json:"entity_type"` // "reservation", "hold", or "user" for users-api events
 	Timestamp  time.Time `json:"timestamp"`
+	// Data is the reservation after the change
+	Data *domain.Reservation `json:"data,omitempty"`
+	// Previous is the slot an update moved the reservation off, which search-api frees
+	Previous *domain.TableSlot `json:"previous,omitempty"`
+	// Hold is the table hold taken or released
+	Hold *domain.Hold `json:"hold,omitempty"`
 }
 
 // NewRabbitMQPublisher creates a new RabbitMQ publisher
@@ -108,14 +118,46 @@ func NewRabbitMQPublisher(uri, exchange, queue string) (*RabbitMQPublisher, erro
 }
 
 // Publish sends a message to RabbitMQ
-func (p *RabbitMQPublisher) Publish(operation, entityID string) error {
-	msg := EventMessage{
+func (p *RabbitMQPublisher) Publish(operation string, reservation *domain.Reservation) error {
+	return p.publish(EventMessage{
+		ID:         primitive.NewObjectID().Hex(),
 		Operation:  operation,
-		EntityID:   entityID,
+		EntityID:   reservation.ID.Hex(),
 		EntityType: "reservation",
 		Timestamp:  time.Now(),
-	}
+		Data:       reservation,
+	})
+}
+
+// PublishMove announces an update that moved the reservation to another table, day or
+// meal; previous is the slot it left
+func (p *RabbitMQPublisher) PublishMove(reservation *domain.Reservation, previous domain.TableSlot) error {
+	return p.publish(EventMessage{
+		ID:         primitive.NewObjectID().Hex(),
+		Operation:  "update",
+		EntityID:   reservation.ID.Hex(),
+		EntityType: "reservation",
+		Timestamp:  time.Now(),
+		Data:       reservation,
+		Previous:   &previous,
+	})
+}
+
+// PublishHold announces a table hold being taken (hold) or expiring (release). They go
+// out with the reservation events, which search-api and the live streams follow.
+func (p *RabbitMQPublisher) PublishHold(operation string, hold *domain.Hold) error {
+	return p.publish(EventMessage{
+		ID:         primitive.NewObjectID().Hex(),
+		Operation:  operation,
+		EntityID:   hold.ID.Hex(),
+		EntityType: "hold",
+		Timestamp:  time.Now(),
+		Hold:       hold,
+	})
+}
 
+func (p *RabbitMQPublisher) publish(msg EventMessage) error {
+	operation, entityID := msg.Operation, msg.EntityID
 	body, err := json.Marshal(msg)
 	if err != nil {
 		return fmt.Errorf("failed to marshal message: %w", err)
@@ -146,6 +188,97 @@ func (p *RabbitMQPublisher) Publish(operation, entityID string) error {
 	return nil
 }
 
+// ConsumeEvents delivers every reservation event published by any replica to fn, through
+// an exclusive queue that RabbitMQ deletes when this consumer goes away. It blocks until
+// the context is cancelled or the channel is closed.
+func (p *RabbitMQPublisher) ConsumeEvents(ctx context.Context, fn func(EventMessage)) error {
+	channel, err := p.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer channel.Close()
+
+	// Server-named, exclusive and auto-deleted: one queue per replica
+	queue, err := channel.QueueDeclare("", false, true, true, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to declare stream queue: %w", err)
+	}
+	if err := channel.QueueBind(queue.Name, "reservation.*", p.exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind stream queue: %w", err)
+	}
+
+	deliveries, err := channel.Consume(queue.Name, "", true, true, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume stream queue: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("stream channel closed")
+			}
+			var msg EventMessage
+			if err := json.Unmarshal(d.Body, &msg); err != nil {
+				log.Printf("Warning: skipping malformed event: %v", err)
+				continue
+			}
+			fn(msg)
+		}
+	}
+}
+
+// ConsumeUserEvents delivers the user events published by users-api (user.deleted) to fn
+// through a durable queue shared by the replicas, so events published while this service
+// is down are kept. A message is acknowledged once fn succeeds; otherwise it is requeued
+// and the error returned, so the caller backs off before consuming again.
+func (p *RabbitMQPublisher) ConsumeUserEvents(ctx context.Context, queue string, fn func(context.Context, EventMessage) error) error {
+	channel, err := p.conn.Channel()
+	if err != nil {
+		return fmt.Errorf("failed to open channel: %w", err)
+	}
+	defer channel.Close()
+
+	if _, err := channel.QueueDeclare(queue, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare user events queue: %w", err)
+	}
+	if err := channel.QueueBind(queue, "user.deleted", p.exchange, false, nil); err != nil {
+		return fmt.Errorf("failed to bind user events queue: %w", err)
+	}
+	if err := channel.Qos(1, 0, false); err != nil {
+		return fmt.Errorf("failed to set user events prefetch: %w", err)
+	}
+
+	deliveries, err := channel.Consume(queue, "", false, false, false, false, nil)
+	if err != nil {
+		return fmt.Errorf("failed to consume user events queue: %w", err)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case d, ok := <-deliveries:
+			if !ok {
+				return fmt.Errorf("user events channel closed")
+			}
+			var msg EventMessage
+			if err := json.Unmarshal(d.Body, &msg); err != nil {
+				log.Printf("Warning: dropping malformed user event: %v", err)
+				_ = d.Nack(false, false)
+				continue
+			}
+			if err := fn(ctx, msg); err != nil {
+				_ = d.Nack(false, true)
+				return fmt.Errorf("failed to handle user %s %s: %w", msg.EntityID, msg.Operation, err)
+			}
+			_ = d.Ack(false)
+		}
+	}
+}
+
 // Close closes the RabbitMQ connection
 func (p *RabbitMQPublisher) Close() error {
 	if p.channel != nil {
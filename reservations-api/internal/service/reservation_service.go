@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"strings"
+	"time"
 
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
@@ -13,20 +16,64 @@ import (
 // ReservationService defines the business logic for reservations
 type ReservationService interface {
 	CreateReservation(ctx context.Context, req domain.CreateReservationRequest) (*domain.Reservation, error)
+	QuoteReservation(ctx context.Context, req domain.CreateReservationRequest) (*domain.Quote, error)
 	GetReservation(ctx context.Context, id string) (*domain.Reservation, error)
-	GetAllReservations(ctx context.Context, limit, offset int) ([]domain.Reservation, error)
-	GetUserReservations(ctx context.Context, userID string) ([]domain.Reservation, error)
+	GetAllReservations(ctx context.Context, filter domain.ReservationFilter, limit, offset int) ([]domain.Reservation, error)
+	GetUserReservations(ctx context.Context, userID string, includeArchived bool) ([]domain.Reservation, error)
 	UpdateReservation(ctx context.Context, id string, req domain.UpdateReservationRequest) (*domain.Reservation, error)
-	DeleteReservation(ctx context.Context, id string) error
+	CancelReservation(ctx context.Context, id string, req domain.CancelReservationRequest) (*domain.Reservation, error)
 	ConfirmReservation(ctx context.Context, id string, req domain.ConfirmReservationRequest) (*domain.Reservation, error)
 	GetAvailableTables(ctx context.Context, date string, mealType string) ([]domain.TableConfig, error)
+	GetAvailabilityCalendar(ctx context.Context, q domain.AvailabilityQuery) (*domain.AvailabilityCalendar, error)
+	GetCancellationPolicies() []domain.CancellationPolicy
+	ImportReservations(ctx context.Context, rows []domain.ImportRow, dryRun bool) domain.ImportReport
+	ExportReservations(ctx context.Context, filter domain.ReservationFilter, fn func(domain.Reservation) error) error
+	GetCalendarFeedLinks(userID string) CalendarFeedLinks
+	GetCalendarFeed(ctx context.Context, userID string, token string) ([]domain.Reservation, error)
+	GetCheckInToken(r *domain.Reservation) (*CheckInToken, error)
+	CheckIn(ctx context.Context, token string) (*domain.Reservation, error)
+	EraseUser(ctx context.Context, userID string, requestedAt time.Time) (*domain.ErasureReceipt, error)
+	GetErasureReceipt(ctx context.Context, userID string) (*domain.ErasureReceipt, error)
+	UpdatePreOrder(ctx context.Context, reservation *domain.Reservation, req domain.PreOrderRequest) (*domain.Reservation, error)
+	RecordPrepayment(ctx context.Context, id string, req domain.RecordPrepaymentRequest) (*domain.Reservation, error)
+	GetFloorStatus(ctx context.Context, mealType string) (*domain.FloorStatus, error)
+	SeatWalkIn(ctx context.Context, req domain.WalkInRequest) (*domain.Reservation, error)
+	CompleteReservation(ctx context.Context, id string) (*domain.Reservation, error)
+	HoldTable(ctx context.Context, ownerID string, req domain.HoldRequest) (*domain.HoldGrant, error)
+	StartBulkJob(ctx context.Context, createdBy string, req domain.BulkJobRequest) (*domain.BulkJob, error)
+	GetBulkJob(ctx context.Context, id string) (*domain.BulkJob, error)
+	ListBulkJobs(ctx context.Context, limit int) ([]domain.BulkJob, error)
+}
+
+// eventPublisher announces reservation changes and holds (RabbitMQPublisher in production)
+type eventPublisher interface {
+	holdPublisher
+	Publish(operation string, reservation *domain.Reservation) error
+	PublishMove(reservation *domain.Reservation, previous domain.TableSlot) error
 }
 
 // reservationService implements ReservationService
 type reservationService struct {
-	repo        repository.ReservationRepository
-	userClient  *UserClient
-	rmqPublisher *RabbitMQPublisher
+	repo         repository.ReservationRepository
+	userClient   *UserClient
+	rmqPublisher eventPublisher
+	policies     domain.CancellationPolicies
+	calendarFeed *CalendarFeed
+	calculator   *domain.CalculationPipeline
+	checkIn      *CheckInTokens
+	webhooks     WebhookService
+	archive      repository.ArchiveRepository
+	erasures     repository.ErasureRepository
+	menu         repository.MenuRepository
+	holds        repository.HoldRepository
+	bulkJobs     repository.BulkJobRepository
+	// preOrderCutoff is how long before the booked time pre-orders close
+	preOrderCutoff time.Duration
+	// floor decides the live table statuses of the host stand
+	floor domain.FloorPolicy
+	// holdTTL is how long a table is held during checkout
+	holdTTL time.Duration
+	now     func() time.Time
 }
 
 // NewReservationService creates a new reservation service
@@ -34,19 +81,66 @@ func NewReservationService(
 	repo repository.ReservationRepository,
 	userClient *UserClient,
 	rmqPublisher *RabbitMQPublisher,
+	policies domain.CancellationPolicies,
+	calendarFeed *CalendarFeed,
+	calculator *domain.CalculationPipeline,
+	checkIn *CheckInTokens,
+	webhooks WebhookService,
+	archive repository.ArchiveRepository,
+	erasures repository.ErasureRepository,
+	menu repository.MenuRepository,
+	holds repository.HoldRepository,
+	bulkJobs repository.BulkJobRepository,
+	preOrderCutoff time.Duration,
+	floor domain.FloorPolicy,
+	holdTTL time.Duration,
 ) ReservationService {
 	return &reservationService{
-		repo:        repo,
-		userClient:  userClient,
+		repo:         repo,
+		userClient:   userClient,
 		rmqPublisher: rmqPublisher,
+		policies:     policies,
+		calendarFeed: calendarFeed,
+		calculator:   calculator,
+		checkIn:      checkIn,
+		webhooks:     webhooks,
+		archive:      archive,
+		erasures:     erasures,
+		menu:         menu,
+		holds:        holds,
+		bulkJobs:     bulkJobs,
+
+		preOrderCutoff: preOrderCutoff,
+		floor:          floor,
+		holdTTL:        holdTTL,
+		now:            time.Now,
 	}
 }
 
 // CreateReservation creates a new reservation with validation and concurrent calculations
 func (s *reservationService) CreateReservation(ctx context.Context, req domain.CreateReservationRequest) (*domain.Reservation, error) {
+	reservation, err := s.buildReservation(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.saveNewReservation(ctx, reservation); err != nil {
+		return nil, err
+	}
+	s.consumeHold(ctx, reservation, req.HoldToken)
+
+	return reservation, nil
+}
+
+// buildReservation runs every rule a new reservation must pass (user, table
+// availability, calculations, validation) without persisting anything
+func (s *reservationService) buildReservation(ctx context.Context, req domain.CreateReservationRequest) (*domain.Reservation, error) {
 	// 1. Validate user exists via Users API
-	if err := s.userClient.ValidateUser(req.OwnerID); err != nil {
-		return nil, fmt.Errorf("user validation failed: %w", err)
+	if err := s.userClient.ValidateUser(ctx, req.OwnerID); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, domain.NewValidationError("owner_id", "user not found")
+		}
+		return nil, userLookupError(err)
 	}
 
 	// 2. Create reservation object
@@ -62,55 +156,92 @@ func (s *reservationService) CreateReservation(ctx context.Context, req domain.C
 	// Check if requested table is in the reserved list
 	for _, reservedNum := range reservedTables {
 		if reservedNum == reservation.TableNumber {
-			return nil, fmt.Errorf("table %d is already reserved for %s on %s", reservation.TableNumber, reservation.MealType, date)
+			return nil, domain.ConflictError("table %d is already reserved for %s on %s", reservation.TableNumber, reservation.MealType, date)
 		}
 	}
+	// Only the guest holding the table during checkout can book it
+	if err := s.checkHold(ctx, &reservation, req.HoldToken); err != nil {
+		return nil, err
+	}
+	if err := reservation.CheckTable(); err != nil {
+		return nil, err
+	}
 
-	// 4. Perform concurrent calculations (pricing, discounts)
-	calcResult, err := domain.CalculateReservationConcurrent(
-		reservation.TableNumber,
-		reservation.Guests,
-		reservation.DateTime,
-		reservation.MealType,
-		reservation.OwnerID,
-	)
+	// 4. Run the calculation pipeline (availability, pricing, discounts)
+	calcResult, err := s.calculator.Run(ctx, reservation.CalculationInput())
 	if err != nil {
 		return nil, fmt.Errorf("calculation failed: %w", err)
 	}
 
 	// 5. Check if table is available (additional validations like weekend hours)
 	if !calcResult.Available {
-		return nil, fmt.Errorf("reservation not available: %v", calcResult.Restrictions)
+		return nil, domain.ConflictError("reservation not available: %s", strings.Join(calcResult.Restrictions, "; "))
 	}
 
-	// 6. Set calculated price
-	reservation.TotalPrice = calcResult.FinalPrice
+	// 6. Set calculated price and the cancellation policy the customer agrees to
+	reservation.ApplyPrice(calcResult, domain.PriceTriggerCreate)
+	policy := s.policies.For(reservation.MealType)
+	reservation.CancellationPolicy = &policy
 
 	// 7. Validate reservation data
 	if err := reservation.Validate(); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+		return nil, err
 	}
 
+	return &reservation, nil
+}
+
+// saveNewReservation persists a built reservation and publishes its create event
+func (s *reservationService) saveNewReservation(ctx context.Context, reservation *domain.Reservation) error {
 	// 8. Save to database
-	if err := s.repo.Create(ctx, &reservation); err != nil {
-		return nil, fmt.Errorf("failed to create reservation: %w", err)
+	if err := s.repo.Create(ctx, reservation); err != nil {
+		return fmt.Errorf("failed to create reservation: %w", err)
 	}
 
-	// 9. Publish event to RabbitMQ (async notification)
+	// 9. Publish event to RabbitMQ and the webhook subscribers (async)
+	s.publishEvent("create", reservation)
+
+	return nil
+}
+
+// publishEvent announces a lifecycle change on RabbitMQ and queues it for the webhook
+// subscribers, without blocking the request
+func (s *reservationService) publishEvent(operation string, reservation *domain.Reservation) {
+	s.publishChange(operation, reservation, nil)
+}
+
+// publishMove publishes the update of a reservation moved off previous, so search-api
+// frees that slot
+func (s *reservationService) publishMove(reservation *domain.Reservation, previous domain.TableSlot) {
+	s.publishChange("update", reservation, &previous)
+}
+
+func (s *reservationService) publishChange(operation string, reservation *domain.Reservation, previous *domain.TableSlot) {
+	snapshot := *reservation
 	go func() {
-		if err := s.rmqPublisher.Publish("create", reservation.ID.Hex()); err != nil {
-			log.Printf("Warning: failed to publish create event: %v", err)
+		var err error
+		if previous != nil {
+			err = s.rmqPublisher.PublishMove(&snapshot, *previous)
+		} else {
+			err = s.rmqPublisher.Publish(operation, &snapshot)
+		}
+		if err != nil {
+			log.Printf("Warning: failed to publish %s event: %v", operation, err)
 		}
-	}()
 
-	return &reservation, nil
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := s.webhooks.Enqueue(ctx, "reservation."+operation, &snapshot); err != nil {
+			log.Printf("Warning: failed to queue %s webhooks: %v", operation, err)
+		}
+	}()
 }
 
 // GetReservation retrieves a reservation by ID
 func (s *reservationService) GetReservation(ctx context.Context, id string) (*domain.Reservation, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseReservationID(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid reservation ID: %w", err)
+		return nil, err
 	}
 
 	reservation, err := s.repo.GetByID(ctx, objectID)
@@ -121,26 +252,40 @@ func (s *reservationService) GetReservation(ctx context.Context, id string) (*do
 	return reservation, nil
 }
 
-// GetAllReservations retrieves all reservations with pagination
-func (s *reservationService) GetAllReservations(ctx context.Context, limit, offset int) ([]domain.Reservation, error) {
-	return s.repo.GetAll(ctx, limit, offset)
+// GetAllReservations retrieves the reservations matching the filter with pagination
+func (s *reservationService) GetAllReservations(ctx context.Context, filter domain.ReservationFilter, limit, offset int) ([]domain.Reservation, error) {
+	return s.repo.GetAll(ctx, filter, limit, offset)
 }
 
-// GetUserReservations retrieves all reservations for a specific user
-func (s *reservationService) GetUserReservations(ctx context.Context, userID string) ([]domain.Reservation, error) {
+// GetUserReservations retrieves all reservations for a specific user, newest first,
+// optionally followed by the ones the retention job archived
+func (s *reservationService) GetUserReservations(ctx context.Context, userID string, includeArchived bool) ([]domain.Reservation, error) {
 	// Validate user exists
-	if err := s.userClient.ValidateUser(userID); err != nil {
-		return nil, fmt.Errorf("user validation failed: %w", err)
+	if err := s.userClient.ValidateUser(ctx, userID); err != nil {
+		if errors.Is(err, ErrUserNotFound) {
+			return nil, domain.NotFoundError("user not found")
+		}
+		return nil, userLookupError(err)
+	}
+
+	reservations, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil || !includeArchived {
+		return reservations, err
 	}
 
-	return s.repo.GetByUserID(ctx, userID)
+	// Archived reservations are all older than the live ones
+	archived, err := s.archive.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	return append(reservations, archived...), nil
 }
 
 // UpdateReservation updates an existing reservation
 func (s *reservationService) UpdateReservation(ctx context.Context, id string, req domain.UpdateReservationRequest) (*domain.Reservation, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseReservationID(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid reservation ID: %w", err)
+		return nil, err
 	}
 
 	// Get existing reservation
@@ -148,6 +293,7 @@ func (s *reservationService) UpdateReservation(ctx context.Context, id string, r
 	if err != nil {
 		return nil, err
 	}
+	status, previous, guests := reservation.Status, reservation.Slot(), reservation.Guests
 
 	// Apply updates
 	if req.TableNumber != nil {
@@ -159,83 +305,115 @@ func (s *reservationService) UpdateReservation(ctx context.Context, id string, r
 	if req.DateTime != nil {
 		reservation.DateTime = *req.DateTime
 	}
-	if req.MealType != nil {
+	if req.MealType != nil && *req.MealType != reservation.MealType {
 		reservation.MealType = *req.MealType
+		if err := s.checkPreOrderServed(ctx, reservation); err != nil {
+			return nil, err
+		}
 	}
 	if req.SpecialRequests != nil {
 		reservation.SpecialRequests = *req.SpecialRequests
 	}
-	if req.Status != nil {
-		reservation.Status = *req.Status
+	req.ApplyRequests(reservation)
+	if req.ChangesTable() {
+		if err := reservation.CheckTable(); err != nil {
+			return nil, err
+		}
+	}
+	if req.Status != nil && *req.Status != reservation.Status {
+		if reservation.Status == domain.StatusCancelled {
+			// Its table may have been rebooked since; the guest books again instead
+			return nil, domain.ConflictError("cancelled reservations cannot be reopened")
+		}
+		if *req.Status == domain.StatusCancelled {
+			// Cancelling through an update still goes through the cancellation policy
+			if err := reservation.Cancel(s.policies.For(reservation.MealType), "", time.Now()); err != nil {
+				return nil, err
+			}
+		} else if err := reservation.SetStatus(*req.Status, time.Now()); err != nil {
+			return nil, err
+		}
 	}
 
 	// Recalculate price if relevant fields changed
-	if req.Guests != nil || req.DateTime != nil || req.MealType != nil {
-		calcResult, err := domain.CalculateReservationConcurrent(
-			reservation.TableNumber,
-			reservation.Guests,
-			reservation.DateTime,
-			reservation.MealType,
-			reservation.OwnerID,
-		)
+	rescheduled := !reservation.DateTime.Equal(previous.DateTime)
+	if reservation.Guests != guests || rescheduled || reservation.MealType != previous.MealType {
+		calcResult, err := s.calculator.Run(ctx, reservation.CalculationInput())
 		if err != nil {
 			return nil, fmt.Errorf("calculation failed: %w", err)
 		}
 
 		if !calcResult.Available {
-			return nil, fmt.Errorf("reservation not available: %v", calcResult.Restrictions)
+			return nil, domain.ConflictError("reservation not available: %s", strings.Join(calcResult.Restrictions, "; "))
 		}
 
-		reservation.TotalPrice = calcResult.FinalPrice
+		reservation.ApplyPrice(calcResult, domain.PriceTriggerUpdate)
 	}
 
-	// Validate
-	if err := reservation.Validate(); err != nil {
-		return nil, fmt.Errorf("validation failed: %w", err)
+	// Validate; the booked time only has to be in the future when it changes, so past
+	// reservations can still be marked as no-shows
+	if err := reservation.ValidateUpdate(rescheduled); err != nil {
+		return nil, err
 	}
 
-	// Update in database
-	if err := s.repo.Update(ctx, objectID, reservation); err != nil {
+	// Moving to another table, day or meal needs that slot to be free and not held
+	moved := reservation.Slot().Key() != previous.Key()
+	if moved && reservation.Status != domain.StatusCancelled {
+		if err := s.checkTableFree(ctx, reservation); err != nil {
+			return nil, err
+		}
+	}
+
+	// Update in database, unless a concurrent change moved it out of its status
+	if err := s.repo.UpdateIfStatus(ctx, objectID, status, reservation); err != nil {
 		return nil, err
 	}
 
-	// Publish event to RabbitMQ
-	go func() {
-		if err := s.rmqPublisher.Publish("update", reservation.ID.Hex()); err != nil {
-			log.Printf("Warning: failed to publish update event: %v", err)
-		}
-	}()
+	// Publish event to RabbitMQ and the webhook subscribers
+	if moved {
+		s.publishMove(reservation, previous)
+	} else {
+		s.publishEvent("update", reservation)
+	}
 
 	return reservation, nil
 }
 
-// DeleteReservation deletes a reservation
-func (s *reservationService) DeleteReservation(ctx context.Context, id string) error {
-	objectID, err := primitive.ObjectIDFromHex(id)
+// CancelReservation cancels a reservation, charging the fee due under its cancellation policy
+func (s *reservationService) CancelReservation(ctx context.Context, id string, req domain.CancelReservationRequest) (*domain.Reservation, error) {
+	objectID, err := parseReservationID(id)
 	if err != nil {
-		return fmt.Errorf("invalid reservation ID: %w", err)
+		return nil, err
 	}
 
-	// Delete from database
-	if err := s.repo.Delete(ctx, objectID); err != nil {
-		return err
+	// Get existing reservation
+	reservation, err := s.repo.GetByID(ctx, objectID)
+	if err != nil {
+		return nil, err
 	}
+	status := reservation.Status
 
-	// Publish event to RabbitMQ
-	go func() {
-		if err := s.rmqPublisher.Publish("delete", id); err != nil {
-			log.Printf("Warning: failed to publish delete event: %v", err)
-		}
-	}()
+	// Change status and compute the fee (older reservations fall back to the current policy)
+	if err := reservation.Cancel(s.policies.For(reservation.MealType), req.Reason, time.Now()); err != nil {
+		return nil, err
+	}
 
-	return nil
+	// Update in database, unless a concurrent change moved it out of its status
+	if err := s.repo.UpdateIfStatus(ctx, objectID, status, reservation); err != nil {
+		return nil, err
+	}
+
+	// Publish event to RabbitMQ and the webhook subscribers
+	s.publishEvent("cancel", reservation)
+
+	return reservation, nil
 }
 
 // ConfirmReservation confirms a reservation with concurrent recalculation
 func (s *reservationService) ConfirmReservation(ctx context.Context, id string, req domain.ConfirmReservationRequest) (*domain.Reservation, error) {
-	objectID, err := primitive.ObjectIDFromHex(id)
+	objectID, err := parseReservationID(id)
 	if err != nil {
-		return nil, fmt.Errorf("invalid reservation ID: %w", err)
+		return nil, err
 	}
 
 	// Get existing reservation
@@ -243,49 +421,57 @@ func (s *reservationService) ConfirmReservation(ctx context.Context, id string,
 	if err != nil {
 		return nil, err
 	}
+	status := reservation.Status
 
-	// Check if already confirmed
+	// Check if already confirmed or cancelled
 	if reservation.Status == domain.StatusConfirmed {
-		return nil, fmt.Errorf("reservation already confirmed")
+		return nil, domain.ConflictError("reservation already confirmed")
+	}
+	if !domain.CanTransition(reservation.Status, domain.StatusConfirmed) {
+		return nil, domain.ConflictError("%s reservations cannot be confirmed", reservation.Status)
 	}
 
-	// Perform concurrent calculations again (may apply confirmation discount)
-	calcResult, err := domain.CalculateReservationConcurrent(
-		reservation.TableNumber,
-		reservation.Guests,
-		reservation.DateTime,
-		reservation.MealType,
-		reservation.OwnerID,
-	)
+	// Recalculate with the pipeline (may apply confirmation discount)
+	calcResult, err := s.calculator.Run(ctx, reservation.CalculationInput())
 	if err != nil {
 		return nil, fmt.Errorf("calculation failed: %w", err)
 	}
 
 	if !calcResult.Available {
-		return nil, fmt.Errorf("reservation not available: %v", calcResult.Restrictions)
+		return nil, domain.ConflictError("reservation not available: %s", strings.Join(calcResult.Restrictions, "; "))
 	}
 
 	// Update status and price
 	reservation.Status = domain.StatusConfirmed
-	reservation.TotalPrice = calcResult.FinalPrice
+	reservation.ApplyPrice(calcResult, domain.PriceTriggerConfirm)
 
-	// Update in database
-	if err := s.repo.Update(ctx, objectID, reservation); err != nil {
+	// Update in database, unless a concurrent change moved it out of its status
+	if err := s.repo.UpdateIfStatus(ctx, objectID, status, reservation); err != nil {
 		return nil, err
 	}
 
-	// Publish event to RabbitMQ
-	go func() {
-		if err := s.rmqPublisher.Publish("confirm", reservation.ID.Hex()); err != nil {
-			log.Printf("Warning: failed to publish confirm event: %v", err)
-		}
-	}()
+	// Publish event to RabbitMQ and the webhook subscribers
+	s.publishEvent("confirm", reservation)
 
 	return reservation, nil
 }
 
+// serviceDay parses a YYYY-MM-DD date as the day on the restaurant's clock
+func (s *reservationService) serviceDay(date string) (time.Time, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, s.floor.Location)
+	if err != nil {
+		return time.Time{}, domain.NewValidationError("date", "must be YYYY-MM-DD")
+	}
+	return day, nil
+}
+
 // GetAvailableTables returns available tables for a given date and meal type
 func (s *reservationService) GetAvailableTables(ctx context.Context, date string, mealType string) ([]domain.TableConfig, error) {
+	day, err := s.serviceDay(date)
+	if err != nil {
+		return nil, err
+	}
+
 	// Get all predefined tables for the meal type
 	allTables := domain.GetTablesForMealType(mealType)
 
@@ -295,6 +481,15 @@ func (s *reservationService) GetAvailableTables(ctx context.Context, date string
 		return nil, fmt.Errorf("failed to get reserved tables: %w", err)
 	}
 
+	// Tables held during someone's checkout are not available either
+	holds, err := s.holds.ListActive(ctx, day, day.AddDate(0, 0, 1), mealType, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held tables: %w", err)
+	}
+	for _, hold := range holds {
+		reservedTables = append(reservedTables, hold.TableNumber)
+	}
+
 	// Filter out reserved tables
 	availableTables := []domain.TableConfig{}
 	for _, table := range allTables {
@@ -312,3 +507,73 @@ func (s *reservationService) GetAvailableTables(ctx context.Context, date string
 
 	return availableTables, nil
 }
+
+// GetAvailabilityCalendar returns how many tables fit the party on each day and meal of the range
+func (s *reservationService) GetAvailabilityCalendar(ctx context.Context, q domain.AvailabilityQuery) (*domain.AvailabilityCalendar, error) {
+	reserved, err := s.repo.GetReservedSlots(ctx, q.From, q.To, q.MealType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get reserved tables: %w", err)
+	}
+	holds, err := s.holds.ListActive(ctx, q.From, q.To, q.MealType, s.now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get held tables: %w", err)
+	}
+
+	return domain.NewAvailabilityCalendar(q, domain.WithHolds(reserved, holds)), nil
+}
+
+// GetCancellationPolicies returns the cancellation policy configured for each meal type
+func (s *reservationService) GetCancellationPolicies() []domain.CancellationPolicy {
+	mealTypes := []string{domain.MealTypeBreakfast, domain.MealTypeLunch, domain.MealTypeDinner, domain.MealTypeEvent}
+	policies := make([]domain.CancellationPolicy, 0, len(mealTypes))
+	for _, mealType := range mealTypes {
+		policies = append(policies, s.policies.For(mealType))
+	}
+	return policies
+}
+
+// GetCalendarFeedLinks returns the subscription URLs of a user's calendar feed
+func (s *reservationService) GetCalendarFeedLinks(userID string) CalendarFeedLinks {
+	return s.calendarFeed.Links(userID)
+}
+
+// GetCalendarFeed returns the upcoming reservations of a user for the calendar feed.
+// Cancelled reservations are kept so subscribed calendars remove the event.
+func (s *reservationService) GetCalendarFeed(ctx context.Context, userID string, token string) ([]domain.Reservation, error) {
+	if !s.calendarFeed.Verify(userID, token) {
+		return nil, domain.NotFoundError("calendar feed not found")
+	}
+
+	reservations, err := s.repo.GetByUserID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Keep events that have not finished yet
+	now := time.Now()
+	upcoming := []domain.Reservation{}
+	for _, reservation := range reservations {
+		if reservation.DateTime.Add(reservation.ExpectedDuration()).After(now) {
+			upcoming = append(upcoming, reservation)
+		}
+	}
+
+	return upcoming, nil
+}
+
+// parseReservationID converts a reservation ID; a malformed ID cannot match any reservation
+func parseReservationID(id string) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, domain.NotFoundError("reservation not found")
+	}
+	return objectID, nil
+}
+
+// userLookupError classifies a Users API failure other than a missing user
+func userLookupError(err error) error {
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	return domain.UpstreamError(err, "users API unavailable")
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeStatusRepo serves one reservation, reports a fixed set of booked tables and
+// counts the writes; the rest of the repository is not used
+type fakeStatusRepo struct {
+	repository.ReservationRepository
+	reservation domain.Reservation
+	reserved    []int
+	writes      int
+}
+
+func (r *fakeStatusRepo) GetReservedTableNumbers(context.Context, string, string) ([]int, error) {
+	return r.reserved, nil
+}
+
+func (r *fakeStatusRepo) GetByID(context.Context, primitive.ObjectID) (*domain.Reservation, error) {
+	reservation := r.reservation
+	return &reservation, nil
+}
+
+func (r *fakeStatusRepo) UpdateIfStatus(context.Context, primitive.ObjectID, string, *domain.Reservation) error {
+	r.writes++
+	return nil
+}
+
+// fakeEventPublisher records the published operations
+type fakeEventPublisher struct {
+	mu        sync.Mutex
+	published []string
+}
+
+func (p *fakeEventPublisher) Publish(operation string, _ *domain.Reservation) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.published = append(p.published, operation)
+	return nil
+}
+
+func (p *fakeEventPublisher) PublishMove(*domain.Reservation, domain.TableSlot) error {
+	return p.Publish("move", nil)
+}
+
+func (p *fakeEventPublisher) PublishHold(operation string, _ *domain.Hold) error {
+	return p.Publish(operation, nil)
+}
+
+// fakeWebhooks drops the deliveries; the rest of the service is not used
+type fakeWebhooks struct {
+	WebhookService
+}
+
+func (fakeWebhooks) Enqueue(context.Context, string, *domain.Reservation) error {
+	return nil
+}
+
+func TestStatusChangesFollowTheAllowedTransitions(t *testing.T) {
+	id := primitive.NewObjectID()
+	status := func(s string) *string { return &s }
+
+	tests := []struct {
+		name   string
+		status string
+		change func(svc *reservationService) error
+	}{
+		{"confirm a seated reservation", domain.StatusSeated, func(svc *reservationService) error {
+			_, err := svc.ConfirmReservation(context.Background(), id.Hex(), domain.ConfirmReservationRequest{})
+			return err
+		}},
+		{"confirm a no-show", domain.StatusNoShow, func(svc *reservationService) error {
+			_, err := svc.ConfirmReservation(context.Background(), id.Hex(), domain.ConfirmReservationRequest{})
+			return err
+		}},
+		{"reopen a cancelled reservation", domain.StatusCancelled, func(svc *reservationService) error {
+			_, err := svc.UpdateReservation(context.Background(), id.Hex(), domain.UpdateReservationRequest{Status: status(domain.StatusPending)})
+			return err
+		}},
+		{"mark a completed reservation as a no-show", domain.StatusCompleted, func(svc *reservationService) error {
+			_, err := svc.UpdateReservation(context.Background(), id.Hex(), domain.UpdateReservationRequest{Status: status(domain.StatusNoShow)})
+			return err
+		}},
+		{"cancel a seated reservation", domain.StatusSeated, func(svc *reservationService) error {
+			_, err := svc.CancelReservation(context.Background(), id.Hex(), domain.CancelReservationRequest{})
+			return err
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeStatusRepo{reservation: domain.Reservation{
+				ID: id, OwnerID: "7", TableNumber: 3, Guests: 2, MealType: domain.MealTypeDinner,
+				DateTime: time.Now().Add(time.Hour), Status: tt.status,
+			}}
+			svc := &reservationService{repo: repo, policies: domain.DefaultCancellationPolicies(), now: time.Now}
+
+			if err := tt.change(svc); !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("error = %v, want conflict", err)
+			}
+			if repo.writes != 0 {
+				t.Errorf("reservation written %d times, want untouched", repo.writes)
+			}
+		})
+	}
+}
+
+func TestUpdateReservationChecksTheNewSlot(t *testing.T) {
+	id := primitive.NewObjectID()
+	at := time.Now().AddDate(0, 0, 7).Truncate(24 * time.Hour).Add(21 * time.Hour)
+	table := func(n int) *int { return &n }
+
+	grant, err := domain.NewHold(domain.HoldRequest{TableNumber: 5, Guests: 2, DateTime: at, MealType: domain.MealTypeDinner}, "42", 10*time.Minute, time.Now())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		req  domain.UpdateReservationRequest
+	}{
+		{"to a reserved table", domain.UpdateReservationRequest{TableNumber: table(4)}},
+		{"to a held table", domain.UpdateReservationRequest{TableNumber: table(5)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeStatusRepo{reserved: []int{3, 4}, reservation: domain.Reservation{
+				ID: id, OwnerID: "7", TableNumber: 3, Guests: 2, MealType: domain.MealTypeDinner,
+				DateTime: at, Status: domain.StatusConfirmed,
+			}}
+			svc := &reservationService{
+				repo:     repo,
+				holds:    &fakeHoldRepo{holds: map[string]*domain.Hold{grant.Slot: grant.Hold}},
+				policies: domain.DefaultCancellationPolicies(),
+				now:      time.Now,
+			}
+
+			if _, err := svc.UpdateReservation(context.Background(), id.Hex(), tt.req); !errors.Is(err, domain.ErrConflict) {
+				t.Errorf("error = %v, want conflict", err)
+			}
+			if repo.writes != 0 {
+				t.Errorf("reservation written %d times, want untouched", repo.writes)
+			}
+		})
+	}
+}
+
+func TestUpdatePastReservation(t *testing.T) {
+	id := primitive.NewObjectID()
+	past := time.Now().Add(-2 * time.Hour)
+	newService := func() (*reservationService, *fakeStatusRepo) {
+		repo := &fakeStatusRepo{reservation: domain.Reservation{
+			ID: id, OwnerID: "7", TableNumber: 3, Guests: 2, MealType: domain.MealTypeDinner,
+			DateTime: past, Status: domain.StatusConfirmed,
+		}}
+		return &reservationService{
+			repo:         repo,
+			rmqPublisher: &fakeEventPublisher{},
+			webhooks:     fakeWebhooks{},
+			calculator:   domain.DefaultCalculationPipeline(nil, domain.DefaultPricing()),
+			policies:     domain.DefaultCancellationPolicies(),
+			now:          time.Now,
+		}, repo
+	}
+
+	svc, repo := newService()
+	noShow := domain.StatusNoShow
+	updated, err := svc.UpdateReservation(context.Background(), id.Hex(), domain.UpdateReservationRequest{Status: &noShow})
+	if err != nil {
+		t.Fatalf("marking a past reservation as a no-show: error = %v", err)
+	}
+	if updated.Status != domain.StatusNoShow || repo.writes != 1 {
+		t.Errorf("status = %s after %d writes, want no_show written once", updated.Status, repo.writes)
+	}
+
+	svc, repo = newService()
+	earlier := past.Add(-time.Hour)
+	_, err = svc.UpdateReservation(context.Background(), id.Hex(), domain.UpdateReservationRequest{DateTime: &earlier})
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) || verr.Fields["date_time"] == "" || repo.writes != 0 {
+		t.Errorf("rescheduling into the past: error = %v after %d writes, want a date_time validation error", err, repo.writes)
+	}
+}
@@ -0,0 +1,74 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// RetentionConfig decides which reservations are archived and how often
+type RetentionConfig struct {
+	// Months of reservations kept in the live collection; 0 disables archiving
+	Months    int
+	BatchSize int
+	Interval  time.Duration
+}
+
+// RetentionJob moves reservations older than the retention period to the archive
+type RetentionJob struct {
+	repo repository.ArchiveRepository
+	cfg  RetentionConfig
+	now  func() time.Time
+}
+
+// NewRetentionJob creates a new retention job
+func NewRetentionJob(repo repository.ArchiveRepository, cfg RetentionConfig) *RetentionJob {
+	return &RetentionJob{repo: repo, cfg: cfg, now: time.Now}
+}
+
+// Run archives on every interval until the context is cancelled
+func (j *RetentionJob) Run(ctx context.Context) {
+	if j.cfg.Months <= 0 {
+		log.Println("Reservation retention disabled")
+		return
+	}
+
+	ticker := time.NewTicker(j.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		report, err := j.RunOnce(ctx)
+		if err != nil {
+			log.Printf("retention: %v", err)
+		} else if report.Archived > 0 {
+			log.Printf("retention: archived %d reservations booked before %s", report.Archived, report.Cutoff.Format("2006-01-02"))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce archives, batch by batch, every reservation booked before the cutoff
+func (j *RetentionJob) RunOnce(ctx context.Context) (*domain.ArchiveReport, error) {
+	now := j.now()
+	report := &domain.ArchiveReport{Cutoff: now.AddDate(0, -j.cfg.Months, 0)}
+
+	for ctx.Err() == nil {
+		n, err := j.repo.ArchiveBefore(ctx, report.Cutoff, j.cfg.BatchSize, now)
+		report.Archived += n
+		if err != nil {
+			return report, err
+		}
+		if n < j.cfg.BatchSize {
+			break
+		}
+	}
+	return report, ctx.Err()
+}
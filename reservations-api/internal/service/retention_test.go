@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// fakeArchiveRepo archives from a pool of reservation dates
+type fakeArchiveRepo struct {
+	live    []time.Time
+	batches []int
+	failAt  int
+}
+
+func (r *fakeArchiveRepo) ArchiveBefore(_ context.Context, cutoff time.Time, limit int, _ time.Time) (int, error) {
+	if r.failAt > 0 && len(r.batches)+1 == r.failAt {
+		return 0, errors.New("bulk write failed")
+	}
+
+	kept := r.live[:0]
+	n := 0
+	for _, at := range r.live {
+		if at.Before(cutoff) && n < limit {
+			n++
+			continue
+		}
+		kept = append(kept, at)
+	}
+	r.live = kept
+	r.batches = append(r.batches, n)
+	return n, nil
+}
+
+func (r *fakeArchiveRepo) GetByUserID(context.Context, string) ([]domain.Reservation, error) {
+	return nil, nil
+}
+
+func TestRetentionJobArchivesInBatches(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	repo := &fakeArchiveRepo{}
+	for i := 0; i < 5; i++ {
+		repo.live = append(repo.live, time.Date(2024, 1, 1+i, 21, 0, 0, 0, time.UTC))
+	}
+	recent := time.Date(2026, 6, 1, 21, 0, 0, 0, time.UTC)
+	repo.live = append(repo.live, recent)
+
+	job := NewRetentionJob(repo, RetentionConfig{Months: 12, BatchSize: 2})
+	job.now = func() time.Time { return now }
+
+	report, err := job.RunOnce(context.Background())
+	if err != nil {
+		t.Fatalf("RunOnce: %v", err)
+	}
+	if want := time.Date(2025, 10, 1, 12, 0, 0, 0, time.UTC); !report.Cutoff.Equal(want) {
+		t.Errorf("cutoff = %v, want %v", report.Cutoff, want)
+	}
+	if report.Archived != 5 {
+		t.Errorf("archived = %d, want 5", report.Archived)
+	}
+	if len(repo.batches) != 3 {
+		t.Errorf("batches = %v, want 3 (2, 2, 1)", repo.batches)
+	}
+	if len(repo.live) != 1 || !repo.live[0].Equal(recent) {
+		t.Errorf("live = %v, want only the reservation inside the retention period", repo.live)
+	}
+}
+
+func TestRetentionJobStopsOnError(t *testing.T) {
+	repo := &fakeArchiveRepo{failAt: 2}
+	for i := 0; i < 4; i++ {
+		repo.live = append(repo.live, time.Date(2020, 1, 1+i, 21, 0, 0, 0, time.UTC))
+	}
+
+	job := NewRetentionJob(repo, RetentionConfig{Months: 12, BatchSize: 2})
+	report, err := job.RunOnce(context.Background())
+	if err == nil {
+		t.Fatal("expected the repository error")
+	}
+	if report.Archived != 2 {
+		t.Errorf("archived = %d, want the 2 of the first batch", report.Archived)
+	}
+}
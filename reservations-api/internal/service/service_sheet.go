@@ -0,0 +1,81 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// ServiceSheetService builds the daily service sheet of the host stand
+type ServiceSheetService interface {
+	// GetServiceSheet returns the service of the day starting at day (local midnight)
+	GetServiceSheet(ctx context.Context, day time.Time) (*domain.ServiceSheet, error)
+}
+
+// guestDirectory looks up many guests at once (UserClient in production)
+type guestDirectory interface {
+	GetUsers(ctx context.Context, userIDs []string) (map[string]UserResponse, error)
+}
+
+// serviceSheetService implements ServiceSheetService
+type serviceSheetService struct {
+	repo  repository.ReservationRepository
+	users guestDirectory
+	// location whose days and times the sheet shows
+	location *time.Location
+	now      func() time.Time
+}
+
+// NewServiceSheetService creates a new service sheet service
+func NewServiceSheetService(repo repository.ReservationRepository, userClient *UserClient, location *time.Location) ServiceSheetService {
+	return &serviceSheetService{repo: repo, users: userClient, location: location, now: time.Now}
+}
+
+// GetServiceSheet reads the day's reservations and looks their guests up in one batched
+// call. While the Users API is unavailable the sheet is still served, without guest
+// details, since the host stand cannot wait for it.
+func (s *serviceSheetService) GetServiceSheet(ctx context.Context, day time.Time) (*domain.ServiceSheet, error) {
+	var reservations []domain.Reservation
+	filter := domain.ReservationFilter{From: day, To: day.AddDate(0, 0, 1)}
+	if err := s.repo.Stream(ctx, filter, func(r domain.Reservation) error {
+		reservations = append(reservations, r)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	ownerIDs := make([]string, 0, len(reservations))
+	for _, r := range reservations {
+		if !r.WalkIn {
+			ownerIDs = append(ownerIDs, r.OwnerID)
+		}
+	}
+
+	unavailable := false
+	users, err := s.users.GetUsers(ctx, ownerIDs)
+	switch {
+	case errors.Is(err, ErrUsersAPIUnavailable):
+		log.Printf("service sheet for %s without guest details: %v", day.Format("2006-01-02"), err)
+		unavailable = true
+	case err != nil:
+		return nil, userLookupError(err)
+	}
+
+	guests := make(map[string]domain.SheetGuest, len(users))
+	for id, user := range users {
+		name := strings.TrimSpace(user.FirstName + " " + user.LastName)
+		if name == "" {
+			name = user.Username
+		}
+		guests[id] = domain.SheetGuest{Name: name, Phone: user.Phone}
+	}
+
+	sheet := domain.NewServiceSheet(day.Format("2006-01-02"), reservations, guests, s.location, s.now().In(s.location))
+	sheet.GuestsUnavailable = unavailable
+	return sheet, nil
+}
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+)
+
+// fakeDayRepo streams canned reservations; the rest of the repository is not used
+type fakeDayRepo struct {
+	repository.ReservationRepository
+	reservations []domain.Reservation
+	filter       domain.ReservationFilter
+}
+
+func (r *fakeDayRepo) Stream(_ context.Context, filter domain.ReservationFilter, fn func(domain.Reservation) error) error {
+	r.filter = filter
+	for _, reservation := range r.reservations {
+		if err := fn(reservation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fakeGuestDirectory counts the batched lookups
+type fakeGuestDirectory struct {
+	users map[string]UserResponse
+	err   error
+	calls [][]string
+}
+
+func (d *fakeGuestDirectory) GetUsers(_ context.Context, userIDs []string) (map[string]UserResponse, error) {
+	d.calls = append(d.calls, userIDs)
+	return d.users, d.err
+}
+
+func TestServiceSheetLooksGuestsUpInOneCall(t *testing.T) {
+	day := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeDayRepo{}
+	for i := 1; i <= 30; i++ {
+		repo.reservations = append(repo.reservations, domain.Reservation{
+			OwnerID: fmt.Sprint(i), MealType: domain.MealTypeDinner, DateTime: day.Add(21 * time.Hour),
+			TableNumber: i, Guests: 2, Status: domain.StatusConfirmed,
+		})
+	}
+	users := &fakeGuestDirectory{users: map[string]UserResponse{"1": {FirstName: "Ana", LastName: "Gómez", Phone: "+54 11 5555-0000"}}}
+	svc := &serviceSheetService{repo: repo, users: users, location: time.UTC, now: time.Now}
+
+	sheet, err := svc.GetServiceSheet(context.Background(), day)
+	if err != nil {
+		t.Fatalf("GetServiceSheet() error = %v", err)
+	}
+
+	if len(users.calls) != 1 || len(users.calls[0]) != 30 {
+		t.Errorf("lookups = %d, want one batch of 30", len(users.calls))
+	}
+	if !repo.filter.From.Equal(day) || !repo.filter.To.Equal(day.AddDate(0, 0, 1)) {
+		t.Errorf("filter = %+v, want the whole day", repo.filter)
+	}
+	if entry := sheet.Meals[0].Slots[0].Entries[0]; entry.GuestName != "Ana Gómez" || entry.Phone == "" {
+		t.Errorf("first entry = %+v, want Ana's name and phone", entry)
+	}
+}
+
+func TestServiceSheetWithoutUsersAPI(t *testing.T) {
+	day := time.Date(2030, time.March, 1, 0, 0, 0, 0, time.UTC)
+	repo := &fakeDayRepo{reservations: []domain.Reservation{
+		{OwnerID: "1", MealType: domain.MealTypeLunch, DateTime: day.Add(13 * time.Hour), TableNumber: 2, Guests: 2, Status: domain.StatusConfirmed},
+	}}
+	users := &fakeGuestDirectory{err: fmt.Errorf("%w: breaker open", ErrUsersAPIUnavailable)}
+	svc := &serviceSheetService{repo: repo, users: users, location: time.UTC, now: time.Now}
+
+	sheet, err := svc.GetServiceSheet(context.Background(), day)
+	if err != nil {
+		t.Fatalf("GetServiceSheet() error = %v, want the sheet without guest details", err)
+	}
+	if !sheet.GuestsUnavailable || sheet.Reservations != 1 {
+		t.Errorf("sheet = %+v, want one reservation flagged without guest details", sheet)
+	}
+}
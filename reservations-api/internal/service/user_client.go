@@ -1,18 +1,90 @@
 package service
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math/rand/v2"
 	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 )
 
+// Fallback policies applied by ValidateUser when the Users API is unavailable
+const (
+	FallbackFailClosed = "fail_closed" // reject the request
+	FallbackAllow      = "allow"       // assume the user exists
+)
+
+var (
+	// ErrUserNotFound is returned when the Users API does not know the user
+	ErrUserNotFound = errors.New("user not found")
+	// ErrUsersAPIUnavailable is returned when the Users API cannot be reached
+	// (after retries) or the circuit breaker is open
+	ErrUsersAPIUnavailable = errors.New("users API unavailable")
+)
+
+// ServiceTokenHeader carries the token the Users API expects from other services
+const ServiceTokenHeader = "X-Service-Token"
+
+// UserClientConfig tunes timeouts, retries, caching and circuit breaking
+type UserClientConfig struct {
+	Timeout          time.Duration // per attempt
+	MaxRetries       int           // retries after the first attempt
+	RetryBaseDelay   time.Duration // backoff base; each retry waits a random time up to base*2^retry
+	CacheTTL         time.Duration // 0 disables the cache
+	BreakerThreshold int           // consecutive failures that open the breaker
+	BreakerCooldown  time.Duration // how long the breaker stays open
+	Fallback         string        // FallbackFailClosed or FallbackAllow
+	ServiceToken     string        // sent so the Users API returns contact details
+}
+
+// DefaultUserClientConfig returns the settings used when none are configured
+func DefaultUserClientConfig() UserClientConfig {
+	return UserClientConfig{
+		Timeout:          2 * time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   100 * time.Millisecond,
+		CacheTTL:         30 * time.Second,
+		BreakerThreshold: 5,
+		BreakerCooldown:  30 * time.Second,
+		Fallback:         FallbackFailClosed,
+	}
+}
+
 // UserClient handles communication with the Users API
 type UserClient struct {
 	baseURL    string
 	httpClient *http.Client
+	cfg        UserClientConfig
+	breaker    *CircuitBreaker
+
+	cacheMu sync.Mutex
+	cache   map[string]cachedUser
+
+	cacheHits   atomic.Uint64
+	cacheMisses atomic.Uint64
+	retries     atomic.Uint64
+	fallbacks   atomic.Uint64
 }
 
+type cachedUser struct {
+	user      UserResponse
+	expiresAt time.Time
+}
+
+// maxCachedUsers bounds the cache; expired entries are purged when it fills up
+const maxCachedUsers = 10000
+
+// maxBatchUsers is how many users the Users API returns per batched lookup
+const maxBatchUsers = 100
+
 // UserResponse represents the response from Users API
 type UserResponse struct {
 	ID        uint64    `json:"id"`
@@ -20,64 +92,304 @@ type UserResponse struct {
 	Email     string    `json:"email"`
 	FirstName string    `json:"first_name"`
 	LastName  string    `json:"last_name"`
+	Phone     string    `json:"phone,omitempty"`
 	Role      string    `json:"role"`
 	CreatedAt time.Time `json:"created_at"`
 	UpdatedAt time.Time `json:"updated_at"`
 }
 
+// UserClientMetrics exposes the client's breaker state and counters
+type UserClientMetrics struct {
+	Breaker     BreakerMetrics `json:"breaker"`
+	Fallback    string         `json:"fallback"`
+	CacheSize   int            `json:"cache_size"`
+	CacheHits   uint64         `json:"cache_hits"`
+	CacheMisses uint64         `json:"cache_misses"`
+	Retries     uint64         `json:"retries"`
+	Fallbacks   uint64         `json:"fallbacks"`
+}
+
 // NewUserClient creates a new Users API client
-func NewUserClient(baseURL string) *UserClient {
+func NewUserClient(baseURL string, cfg UserClientConfig) *UserClient {
+	if cfg.Fallback != FallbackAllow {
+		cfg.Fallback = FallbackFailClosed
+	}
 	return &UserClient{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 5 * time.Second,
+			Timeout: cfg.Timeout,
 		},
+		cfg:     cfg,
+		breaker: NewCircuitBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown),
+		cache:   make(map[string]cachedUser),
 	}
 }
 
-// ValidateUser checks if a user exists by calling the Users API
-func (c *UserClient) ValidateUser(userID string) error {
-	url := fmt.Sprintf("%s/api/users/%s", c.baseURL, userID)
+// ValidateUser checks if a user exists by calling the Users API. When the
+// Users API is unavailable the configured fallback policy decides.
+func (c *UserClient) ValidateUser(ctx context.Context, userID string) error {
+	_, err := c.GetUser(ctx, userID)
+	if errors.Is(err, ErrUsersAPIUnavailable) && c.cfg.Fallback == FallbackAllow {
+		c.fallbacks.Add(1)
+		log.Printf("users API unavailable, allowing user %s by fallback policy: %v", userID, err)
+		return nil
+	}
+	return err
+}
 
-	resp, err := c.httpClient.Get(url)
+// GetUser retrieves user information from the Users API
+func (c *UserClient) GetUser(ctx context.Context, userID string) (*UserResponse, error) {
+	if user, ok := c.cached(userID); ok {
+		return user, nil
+	}
+
+	user, err := guarded(ctx, c, func() (*UserResponse, error) {
+		return withRetries(ctx, c, func() (*UserResponse, bool, error) { return c.fetch(ctx, userID) })
+	})
 	if err != nil {
-		return fmt.Errorf("failed to call users API: %w", err)
+		return nil, err
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("user not found")
+	c.store(userID, user)
+	return user, nil
+}
+
+// GetUsers retrieves several users with one Users API request per batch of
+// maxBatchUsers, serving what it can from the cache. Users the Users API does not
+// know are absent from the result.
+func (c *UserClient) GetUsers(ctx context.Context, userIDs []string) (map[string]UserResponse, error) {
+	users := make(map[string]UserResponse, len(userIDs))
+	var missing []string
+	seen := make(map[string]bool, len(userIDs))
+	for _, id := range userIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		if user, ok := c.cached(id); ok {
+			users[id] = *user
+		} else {
+			missing = append(missing, id)
+		}
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("users API returned status %d", resp.StatusCode)
+	for start := 0; start < len(missing); start += maxBatchUsers {
+		batch := missing[start:min(start+maxBatchUsers, len(missing))]
+		found, err := guarded(ctx, c, func() ([]UserResponse, error) {
+			return withRetries(ctx, c, func() ([]UserResponse, bool, error) { return c.fetchBatch(ctx, batch) })
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, user := range found {
+			id := strconv.FormatUint(user.ID, 10)
+			users[id] = user
+			c.store(id, &user)
+		}
 	}
+	return users, nil
+}
 
-	return nil
+// Metrics returns a snapshot of the client metrics
+func (c *UserClient) Metrics() UserClientMetrics {
+	c.cacheMu.Lock()
+	size := len(c.cache)
+	c.cacheMu.Unlock()
+
+	return UserClientMetrics{
+		Breaker:     c.breaker.Metrics(),
+		Fallback:    c.cfg.Fallback,
+		CacheSize:   size,
+		CacheHits:   c.cacheHits.Load(),
+		CacheMisses: c.cacheMisses.Load(),
+		Retries:     c.retries.Load(),
+		Fallbacks:   c.fallbacks.Load(),
+	}
 }
 
-// GetUser retrieves user information from the Users API
-func (c *UserClient) GetUser(userID string) (*UserResponse, error) {
-	url := fmt.Sprintf("%s/api/users/%s", c.baseURL, userID)
+// guarded runs call through the circuit breaker, recording its outcome
+func guarded[T any](ctx context.Context, c *UserClient, call func() (T, error)) (T, error) {
+	var zero T
+	if err := c.breaker.Allow(); err != nil {
+		return zero, fmt.Errorf("%w: %v", ErrUsersAPIUnavailable, err)
+	}
+
+	result, err := call()
+	switch {
+	case err == nil, errors.Is(err, ErrUserNotFound):
+		// A 404 is a healthy answer from the Users API
+		c.breaker.Success()
+	case ctx.Err() != nil:
+		// The caller gave up: says nothing about the Users API health
+		c.breaker.Release()
+		return zero, ctx.Err()
+	default:
+		c.breaker.Failure()
+	}
+	return result, err
+}
+
+// withRetries retries transient failures (network errors, 5xx, 429)
+// with exponential backoff and full jitter
+func withRetries[T any](ctx context.Context, c *UserClient, fetch func() (T, bool, error)) (T, error) {
+	var zero T
+	var lastErr error
+	for attempt := 0; attempt <= c.cfg.MaxRetries; attempt++ {
+		if attempt > 0 {
+			c.retries.Add(1)
+			if err := sleepContext(ctx, c.backoff(attempt)); err != nil {
+				return zero, err
+			}
+		}
+
+		result, retryable, err := fetch()
+		if err == nil {
+			return result, nil
+		}
+		if !retryable || ctx.Err() != nil {
+			return zero, err
+		}
+		lastErr = err
+	}
+	return zero, fmt.Errorf("%w: %v", ErrUsersAPIUnavailable, lastErr)
+}
 
-	resp, err := c.httpClient.Get(url)
+// fetch makes a single request and reports whether a failure is worth retrying
+func (c *UserClient) fetch(ctx context.Context, userID string) (*UserResponse, bool, error) {
+	endpoint := fmt.Sprintf("%s/api/users/%s", c.baseURL, url.PathEscape(userID))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call users API: %w", err)
+		return nil, false, fmt.Errorf("failed to build users API request: %w", err)
 	}
-	defer resp.Body.Close()
+	c.authorize(req)
 
-	if resp.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("user not found")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to call users API: %w", err)
 	}
+	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("users API returned status %d", resp.StatusCode)
+	switch {
+	case resp.StatusCode == http.StatusNotFound, resp.StatusCode == http.StatusBadRequest:
+		// 400 means the ID is not a valid user ID, so no such user exists either
+		return nil, false, ErrUserNotFound
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("users API returned status %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("users API returned status %d", resp.StatusCode)
 	}
 
 	var user UserResponse
 	if err := json.NewDecoder(resp.Body).Decode(&user); err != nil {
-		return nil, fmt.Errorf("failed to decode user response: %w", err)
+		return nil, true, fmt.Errorf("failed to decode user response: %w", err)
+	}
+
+	return &user, false, nil
+}
+
+// fetchBatch makes a single batched lookup and reports whether a failure is worth retrying
+func (c *UserClient) fetchBatch(ctx context.Context, userIDs []string) ([]UserResponse, bool, error) {
+	endpoint := fmt.Sprintf("%s/api/users?ids=%s", c.baseURL, url.QueryEscape(strings.Join(userIDs, ",")))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to build users API request: %w", err)
+	}
+	c.authorize(req)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to call users API: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return nil, true, fmt.Errorf("users API returned status %d", resp.StatusCode)
+	case resp.StatusCode != http.StatusOK:
+		return nil, false, fmt.Errorf("users API returned status %d", resp.StatusCode)
+	}
+
+	var users []UserResponse
+	if err := json.NewDecoder(resp.Body).Decode(&users); err != nil {
+		return nil, true, fmt.Errorf("failed to decode users response: %w", err)
 	}
 
-	return &user, nil
+	return users, false, nil
+}
+
+// authorize identifies this service to the Users API
+func (c *UserClient) authorize(req *http.Request) {
+	if c.cfg.ServiceToken != "" {
+		req.Header.Set(ServiceTokenHeader, c.cfg.ServiceToken)
+	}
+}
+
+// backoff returns a random delay in [0, base*2^(attempt-1)]
+func (c *UserClient) backoff(attempt int) time.Duration {
+	ceiling := c.cfg.RetryBaseDelay << (attempt - 1)
+	if ceiling <= 0 {
+		return 0
+	}
+	return rand.N(ceiling + 1)
+}
+
+func (c *UserClient) cached(userID string) (*UserResponse, bool) {
+	if c.cfg.CacheTTL <= 0 {
+		return nil, false
+	}
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	entry, ok := c.cache[userID]
+	if !ok || time.Now().After(entry.expiresAt) {
+		delete(c.cache, userID)
+		c.cacheMisses.Add(1)
+		return nil, false
+	}
+	c.cacheHits.Add(1)
+	user := entry.user
+	return &user, true
+}
+
+// Forget drops a cached user, e.g. once the account has been deleted
+func (c *UserClient) Forget(userID string) {
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+	delete(c.cache, userID)
+}
+
+// store caches a user that was found; missing users are never cached
+func (c *UserClient) store(userID string, user *UserResponse) {
+	if c.cfg.CacheTTL <= 0 {
+		return
+	}
+
+	c.cacheMu.Lock()
+	defer c.cacheMu.Unlock()
+
+	now := time.Now()
+	if len(c.cache) >= maxCachedUsers {
+		for id, entry := range c.cache {
+			if now.After(entry.expiresAt) {
+				delete(c.cache, id)
+			}
+		}
+		if len(c.cache) >= maxCachedUsers {
+			return
+		}
+	}
+	c.cache[userID] = cachedUser{user: *user, expiresAt: now.Add(c.cfg.CacheTTL)}
+}
+
+// sleepContext waits for d or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
 }
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// flakyUsersAPI serves /api/users/{id}, failing the first `failures` calls with `failStatus`
+type flakyUsersAPI struct {
+	server     *httptest.Server
+	calls      atomic.Int32
+	failures   atomic.Int32
+	failStatus int
+	delay      time.Duration
+}
+
+func newFlakyUsersAPI(t *testing.T, failures int, failStatus int) *flakyUsersAPI {
+	t.Helper()
+	api := &flakyUsersAPI{failStatus: failStatus}
+	api.failures.Store(int32(failures))
+	api.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		api.calls.Add(1)
+		if api.delay > 0 {
+			select {
+			case <-time.After(api.delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		if api.failures.Add(-1) >= 0 {
+			w.WriteHeader(api.failStatus)
+			return
+		}
+		id := strings.TrimPrefix(r.URL.Path, "/api/users/")
+		if id == "404" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":"user not found"}`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(UserResponse{ID: 7, Username: "ana", Email: "ana@example.com"})
+	}))
+	t.Cleanup(api.server.Close)
+	return api
+}
+
+func testUserClientConfig() UserClientConfig {
+	return UserClientConfig{
+		Timeout:          time.Second,
+		MaxRetries:       2,
+		RetryBaseDelay:   time.Millisecond,
+		CacheTTL:         0,
+		BreakerThreshold: 3,
+		BreakerCooldown:  50 * time.Millisecond,
+		Fallback:         FallbackFailClosed,
+	}
+}
+
+func TestUserClientRetriesTransientFailures(t *testing.T) {
+	api := newFlakyUsersAPI(t, 2, http.StatusServiceUnavailable)
+	client := NewUserClient(api.server.URL, testUserClientConfig())
+
+	user, err := client.GetUser(context.Background(), "7")
+	if err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if user.Username != "ana" {
+		t.Errorf("Username = %q, want ana", user.Username)
+	}
+	if got := api.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3", got)
+	}
+	if m := client.Metrics(); m.Retries != 2 || m.Breaker.State != BreakerClosed {
+		t.Errorf("metrics = %+v, want 2 retries and a closed breaker", m)
+	}
+}
+
+func TestUserClientGivesUpAfterMaxRetries(t *testing.T) {
+	api := newFlakyUsersAPI(t, 100, http.StatusBadGateway)
+	client := NewUserClient(api.server.URL, testUserClientConfig())
+
+	err := client.ValidateUser(context.Background(), "7")
+	if !errors.Is(err, ErrUsersAPIUnavailable) {
+		t.Fatalf("ValidateUser() error = %v, want ErrUsersAPIUnavailable", err)
+	}
+	if got := api.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 1 attempt + 2 retries", got)
+	}
+}
+
+func TestUserClientDoesNotRetryNotFound(t *testing.T) {
+	api := newFlakyUsersAPI(t, 0, 0)
+	cfg := testUserClientConfig()
+	cfg.CacheTTL = time.Minute
+	client := NewUserClient(api.server.URL, cfg)
+
+	for i := 0; i < 2; i++ {
+		if err := client.ValidateUser(context.Background(), "404"); !errors.Is(err, ErrUserNotFound) {
+			t.Fatalf("ValidateUser() error = %v, want ErrUserNotFound", err)
+		}
+	}
+	// Not retried, not cached, and a healthy answer for the breaker
+	if got := api.calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2", got)
+	}
+	if m := client.Metrics(); m.Breaker.State != BreakerClosed || m.Breaker.Failures != 0 {
+		t.Errorf("breaker = %+v, want closed without failures", m.Breaker)
+	}
+}
+
+func TestUserClientBreakerOpensAndRecovers(t *testing.T) {
+	api := newFlakyUsersAPI(t, 3, http.StatusInternalServerError)
+	cfg := testUserClientConfig()
+	cfg.MaxRetries = 0
+	client := NewUserClient(api.server.URL, cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := client.ValidateUser(ctx, "7"); !errors.Is(err, ErrUsersAPIUnavailable) {
+			t.Fatalf("call %d: error = %v, want ErrUsersAPIUnavailable", i, err)
+		}
+	}
+	if state := client.Metrics().Breaker.State; state != BreakerOpen {
+		t.Fatalf("state = %s, want open", state)
+	}
+
+	// Open: rejected without reaching the Users API
+	err := client.ValidateUser(ctx, "7")
+	if !errors.Is(err, ErrUsersAPIUnavailable) || !strings.Contains(err.Error(), ErrCircuitOpen.Error()) {
+		t.Fatalf("error = %v, want circuit open", err)
+	}
+	if got := api.calls.Load(); got != 3 {
+		t.Errorf("calls = %d, want 3 (open breaker must short-circuit)", got)
+	}
+
+	// After the cooldown a probe goes through, succeeds and closes the breaker
+	time.Sleep(cfg.BreakerCooldown + 10*time.Millisecond)
+	if err := client.ValidateUser(ctx, "7"); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+	m := client.Metrics()
+	if m.Breaker.State != BreakerClosed || m.Breaker.Opened != 1 || m.Breaker.Rejected != 1 {
+		t.Errorf("breaker = %+v, want closed after one opening and one rejection", m.Breaker)
+	}
+}
+
+func TestUserClientFailedProbeReopensBreaker(t *testing.T) {
+	api := newFlakyUsersAPI(t, 100, http.StatusServiceUnavailable)
+	cfg := testUserClientConfig()
+	cfg.MaxRetries = 0
+	cfg.BreakerThreshold = 1
+	client := NewUserClient(api.server.URL, cfg)
+
+	_ = client.ValidateUser(context.Background(), "7")
+	time.Sleep(cfg.BreakerCooldown + 10*time.Millisecond)
+	_ = client.ValidateUser(context.Background(), "7")
+
+	m := client.Metrics()
+	if m.Breaker.State != BreakerOpen || m.Breaker.Opened != 2 {
+		t.Errorf("breaker = %+v, want reopened by the failed probe", m.Breaker)
+	}
+}
+
+func TestUserClientFallbackPolicy(t *testing.T) {
+	api := newFlakyUsersAPI(t, 100, http.StatusServiceUnavailable)
+	cfg := testUserClientConfig()
+	cfg.Fallback = FallbackAllow
+	client := NewUserClient(api.server.URL, cfg)
+
+	if err := client.ValidateUser(context.Background(), "7"); err != nil {
+		t.Fatalf("ValidateUser() with allow fallback error = %v", err)
+	}
+	if _, err := client.GetUser(context.Background(), "7"); !errors.Is(err, ErrUsersAPIUnavailable) {
+		t.Errorf("GetUser() error = %v, the fallback only applies to validation", err)
+	}
+	if got := client.Metrics().Fallbacks; got != 1 {
+		t.Errorf("fallbacks = %d, want 1", got)
+	}
+
+	// A missing user is still rejected: the fallback only covers outages
+	healthy := newFlakyUsersAPI(t, 0, 0)
+	client = NewUserClient(healthy.server.URL, cfg)
+	if err := client.ValidateUser(context.Background(), "404"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("ValidateUser() error = %v, want ErrUserNotFound", err)
+	}
+}
+
+func TestUserClientCachesFoundUsers(t *testing.T) {
+	api := newFlakyUsersAPI(t, 0, 0)
+	cfg := testUserClientConfig()
+	cfg.CacheTTL = 50 * time.Millisecond
+	client := NewUserClient(api.server.URL, cfg)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if err := client.ValidateUser(ctx, "7"); err != nil {
+			t.Fatalf("ValidateUser() error = %v", err)
+		}
+	}
+	if got := api.calls.Load(); got != 1 {
+		t.Errorf("calls = %d, want 1 (cached)", got)
+	}
+
+	// Cached users keep validating while the Users API is down
+	api.failures.Store(100)
+	if err := client.ValidateUser(ctx, "7"); err != nil {
+		t.Errorf("ValidateUser() from cache error = %v", err)
+	}
+
+	time.Sleep(cfg.CacheTTL + 10*time.Millisecond)
+	if err := client.ValidateUser(ctx, "7"); !errors.Is(err, ErrUsersAPIUnavailable) {
+		t.Errorf("ValidateUser() after expiry error = %v, want ErrUsersAPIUnavailable", err)
+	}
+
+	m := client.Metrics()
+	if m.CacheHits != 3 || m.CacheMisses != 2 {
+		t.Errorf("cache hits/misses = %d/%d, want 3/2", m.CacheHits, m.CacheMisses)
+	}
+}
+
+func TestUserClientHonoursContext(t *testing.T) {
+	api := newFlakyUsersAPI(t, 0, 0)
+	api.delay = time.Second
+	cfg := testUserClientConfig()
+	cfg.BreakerThreshold = 1
+	client := NewUserClient(api.server.URL, cfg)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := client.ValidateUser(ctx, "7")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("ValidateUser() error = %v, want context.DeadlineExceeded", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("ValidateUser() took %s, want it to stop at the deadline", elapsed)
+	}
+	// The caller gave up: that is not a Users API failure
+	if m := client.Metrics(); m.Breaker.State != BreakerClosed || m.Breaker.Failures != 0 {
+		t.Errorf("breaker = %+v, want closed without failures", m.Breaker)
+	}
+}
+
+func TestUserClientGetUsersBatchesLookups(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		var users []UserResponse
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			n, err := strconv.ParseUint(id, 10, 64)
+			if err == nil && n%2 == 1 { // only odd IDs exist
+				users = append(users, UserResponse{ID: n, Username: "user" + id})
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(users)
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := testUserClientConfig()
+	cfg.CacheTTL = time.Minute
+	client := NewUserClient(server.URL, cfg)
+
+	ids := make([]string, 0, maxBatchUsers+21)
+	for i := 1; i <= maxBatchUsers+20; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+	ids = append(ids, "1") // listed twice
+
+	users, err := client.GetUsers(context.Background(), ids)
+	if err != nil {
+		t.Fatalf("GetUsers() error = %v", err)
+	}
+	if got := calls.Load(); got != 2 {
+		t.Errorf("calls = %d, want 2 batches", got)
+	}
+	if len(users) != (maxBatchUsers+20)/2 || users["3"].Username != "user3" {
+		t.Errorf("users = %d, want the %d odd IDs", len(users), (maxBatchUsers+20)/2)
+	}
+	if _, ok := users["2"]; ok {
+		t.Error("unknown user 2 returned")
+	}
+
+	// Found users are cached for single lookups
+	if _, err := client.GetUser(context.Background(), "5"); err != nil || calls.Load() != 2 {
+		t.Errorf("GetUser() error = %v after %d calls, want it cached", err, calls.Load())
+	}
+}
+
+func TestUserClientSendsServiceToken(t *testing.T) {
+	var tokens []string
+	var mu sync.Mutex
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		tokens = append(tokens, r.Header.Get(ServiceTokenHeader))
+		mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		if r.URL.Query().Has("ids") {
+			_ = json.NewEncoder(w).Encode([]UserResponse{{ID: 7, Username: "ana"}})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(UserResponse{ID: 8, Username: "beto"})
+	}))
+	t.Cleanup(server.Close)
+
+	cfg := testUserClientConfig()
+	cfg.ServiceToken = "secret"
+	client := NewUserClient(server.URL, cfg)
+
+	if _, err := client.GetUsers(context.Background(), []string{"7"}); err != nil {
+		t.Fatalf("GetUsers() error = %v", err)
+	}
+	if _, err := client.GetUser(context.Background(), "8"); err != nil {
+		t.Fatalf("GetUser() error = %v", err)
+	}
+	if len(tokens) != 2 || tokens[0] != "secret" || tokens[1] != "secret" {
+		t.Errorf("service tokens sent = %q, want it on both lookups", tokens)
+	}
+}
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/repository"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Headers sent with every webhook delivery
+const (
+	WebhookEventHeader     = "X-Webhook-Event"
+	WebhookDeliveryHeader  = "X-Webhook-Delivery"
+	WebhookTimestampHeader = "X-Webhook-Timestamp"
+	WebhookSignatureHeader = "X-Webhook-Signature"
+)
+
+// Number of deliveries returned by the delivery log endpoint
+const webhookDeliveryLogSize = 50
+
+// WebhookConfig tunes webhook deliveries
+type WebhookConfig struct {
+	MaxAttempts int
+	// Retry backoff: RetryBaseDelay, doubled after every failed attempt, capped at RetryMaxDelay
+	RetryBaseDelay time.Duration
+	RetryMaxDelay  time.Duration
+	// Timeout bounds a single POST to a subscriber
+	Timeout      time.Duration
+	PollInterval time.Duration
+}
+
+// CreatedWebhook is a new subscription together with its signing secret, which is only shown once
+type CreatedWebhook struct {
+	*domain.WebhookSubscription
+	Secret string `json:"secret"`
+}
+
+// WebhookService manages webhook subscriptions and queues their deliveries
+type WebhookService interface {
+	CreateSubscription(ctx context.Context, req domain.CreateWebhookRequest) (*CreatedWebhook, error)
+	ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error)
+	DeleteSubscription(ctx context.Context, id string) error
+	ListDeliveries(ctx context.Context, subscriptionID string) ([]domain.WebhookDelivery, error)
+	ReplayDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error)
+	// Enqueue queues a delivery of the event to every subscription that wants it
+	Enqueue(ctx context.Context, event string, reservation *domain.Reservation) error
+}
+
+// webhookService implements WebhookService
+type webhookService struct {
+	repo repository.WebhookRepository
+	cfg  WebhookConfig
+	now  func() time.Time
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(repo repository.WebhookRepository, cfg WebhookConfig) WebhookService {
+	return &webhookService{repo: repo, cfg: cfg, now: time.Now}
+}
+
+// CreateSubscription registers a subscriber, generating its secret when none is given
+func (s *webhookService) CreateSubscription(ctx context.Context, req domain.CreateWebhookRequest) (*CreatedWebhook, error) {
+	if err := req.Validate(); err != nil {
+		return nil, err
+	}
+
+	secret := req.Secret
+	if secret == "" {
+		var err error
+		if secret, err = newWebhookSecret(); err != nil {
+			return nil, err
+		}
+	}
+
+	sub := &domain.WebhookSubscription{
+		URL:         req.URL,
+		Events:      req.Events,
+		Description: req.Description,
+		Secret:      secret,
+		CreatedAt:   s.now(),
+	}
+	if err := s.repo.CreateSubscription(ctx, sub); err != nil {
+		return nil, err
+	}
+	return &CreatedWebhook{WebhookSubscription: sub, Secret: secret}, nil
+}
+
+// ListSubscriptions returns every subscription (without secrets)
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// DeleteSubscription removes a subscription; queued deliveries to it fail on their next attempt
+func (s *webhookService) DeleteSubscription(ctx context.Context, id string) error {
+	objectID, err := parseWebhookID(id, "webhook")
+	if err != nil {
+		return err
+	}
+	return s.repo.DeleteSubscription(ctx, objectID)
+}
+
+// ListDeliveries returns the latest deliveries of a subscription with their attempts
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID string) ([]domain.WebhookDelivery, error) {
+	objectID, err := parseWebhookID(subscriptionID, "webhook")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetSubscription(ctx, objectID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, objectID, webhookDeliveryLogSize)
+}
+
+// ReplayDelivery queues a new delivery with the exact payload of an earlier one
+func (s *webhookService) ReplayDelivery(ctx context.Context, subscriptionID, deliveryID string) (*domain.WebhookDelivery, error) {
+	subID, err := parseWebhookID(subscriptionID, "webhook")
+	if err != nil {
+		return nil, err
+	}
+	id, err := parseWebhookID(deliveryID, "webhook delivery")
+	if err != nil {
+		return nil, err
+	}
+
+	original, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if original.SubscriptionID != subID {
+		return nil, domain.NotFoundError("webhook delivery not found")
+	}
+	if _, err := s.repo.GetSubscription(ctx, subID); err != nil {
+		return nil, err
+	}
+
+	replay := s.newDelivery(subID, original.Event, original.EntityID)
+	replay.Payload = original.Payload
+	replay.ReplayOf = &original.ID
+	if err := s.repo.CreateDelivery(ctx, replay); err != nil {
+		return nil, err
+	}
+	return replay, nil
+}
+
+// Enqueue stores one pending delivery per interested subscription; the dispatcher sends them
+func (s *webhookService) Enqueue(ctx context.Context, event string, reservation *domain.Reservation) error {
+	subs, err := s.repo.SubscriptionsFor(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	for _, sub := range subs {
+		d := s.newDelivery(sub.ID, event, reservation.ID.Hex())
+		payload, err := json.Marshal(domain.WebhookPayload{
+			DeliveryID: d.ID.Hex(),
+			Event:      event,
+			OccurredAt: d.CreatedAt,
+			Data:       reservation,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal webhook payload: %w", err)
+		}
+		d.Payload = string(payload)
+
+		if err := s.repo.CreateDelivery(ctx, d); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *webhookService) newDelivery(subscriptionID primitive.ObjectID, event, entityID string) *domain.WebhookDelivery {
+	now := s.now()
+	return &domain.WebhookDelivery{
+		ID:             primitive.NewObjectID(),
+		SubscriptionID: subscriptionID,
+		Event:          event,
+		EntityID:       entityID,
+		Status:         domain.DeliveryPending,
+		MaxAttempts:    s.cfg.MaxAttempts,
+		Attempts:       []domain.WebhookAttempt{},
+		NextAttemptAt:  now,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+}
+
+// WebhookDispatcher POSTs due deliveries and records the outcome of every attempt
+type WebhookDispatcher struct {
+	repo   repository.WebhookRepository
+	client *http.Client
+	cfg    WebhookConfig
+	now    func() time.Time
+}
+
+// NewWebhookDispatcher creates a new webhook dispatcher
+func NewWebhookDispatcher(repo repository.WebhookRepository, cfg WebhookConfig) *WebhookDispatcher {
+	return &WebhookDispatcher{
+		repo:   repo,
+		client: &http.Client{Timeout: cfg.Timeout},
+		cfg:    cfg,
+		now:    time.Now,
+	}
+}
+
+// Run polls for due deliveries until the context is cancelled
+func (d *WebhookDispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.DispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// DispatchDue sends every delivery that is currently due
+func (d *WebhookDispatcher) DispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		// The lease outlives the request timeout so a slow attempt is never claimed twice
+		delivery, err := d.repo.ClaimDue(ctx, d.now(), 2*d.cfg.Timeout)
+		if err != nil {
+			log.Printf("webhooks: %v", err)
+			return
+		}
+		if delivery == nil {
+			return
+		}
+		d.deliver(ctx, delivery)
+	}
+}
+
+func (d *WebhookDispatcher) deliver(ctx context.Context, delivery *domain.WebhookDelivery) {
+	var attempt domain.WebhookAttempt
+	retryable := true
+	sub, err := d.repo.GetSubscription(ctx, delivery.SubscriptionID)
+	switch {
+	case errors.Is(err, domain.ErrNotFound):
+		attempt = domain.WebhookAttempt{At: d.now(), Error: "webhook subscription was deleted"}
+		retryable = false
+	case err != nil:
+		// Leave the lease to expire so the delivery is claimed again
+		log.Printf("webhooks: %v", err)
+		return
+	default:
+		attempt = d.post(ctx, sub, delivery)
+	}
+
+	attempts := len(delivery.Attempts) + 1
+	status, next := domain.DeliveryDelivered, time.Time{}
+	if !attempt.Succeeded() {
+		if retryable && attempts < delivery.MaxAttempts {
+			status, next = domain.DeliveryPending, attempt.At.Add(d.retryDelay(attempts))
+			log.Printf("webhook delivery %s attempt %d failed, retrying at %s: %s", delivery.ID.Hex(), attempts, next.Format(time.RFC3339), attemptError(attempt))
+		} else {
+			status = domain.DeliveryFailed
+			log.Printf("webhook delivery %s failed after %d attempts: %s", delivery.ID.Hex(), attempts, attemptError(attempt))
+		}
+	}
+
+	if err := d.repo.RecordAttempt(ctx, delivery.ID, attempt, status, next); err != nil {
+		log.Printf("webhooks: %v", err)
+	}
+}
+
+// post sends the signed payload to the subscriber
+func (d *WebhookDispatcher) post(ctx context.Context, sub *domain.WebhookSubscription, delivery *domain.WebhookDelivery) domain.WebhookAttempt {
+	start := d.now()
+	attempt := domain.WebhookAttempt{At: start}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, strings.NewReader(delivery.Payload))
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+
+	timestamp := strconv.FormatInt(start.Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "restaurant-reservas-webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, delivery.Event)
+	req.Header.Set(WebhookDeliveryHeader, delivery.ID.Hex())
+	req.Header.Set(WebhookTimestampHeader, timestamp)
+	req.Header.Set(WebhookSignatureHeader, SignWebhook(sub.Secret, timestamp, []byte(delivery.Payload)))
+
+	began := time.Now()
+	resp, err := d.client.Do(req)
+	attempt.DurationMs = time.Since(began).Milliseconds()
+	if err != nil {
+		attempt.Error = err.Error()
+		return attempt
+	}
+	defer resp.Body.Close()
+	// Drain (a bounded part of) the body so the connection can be reused
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	attempt.StatusCode = resp.StatusCode
+	return attempt
+}
+
+// retryDelay doubles the wait after every failed attempt
+func (d *WebhookDispatcher) retryDelay(attempts int) time.Duration {
+	delay := d.cfg.RetryBaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= d.cfg.RetryMaxDelay {
+			return d.cfg.RetryMaxDelay
+		}
+	}
+	return delay
+}
+
+// SignWebhook computes the X-Webhook-Signature header: "sha256=" followed by the hex
+// HMAC-SHA256, keyed with the subscription secret, of "<timestamp>.<body>".
+// Subscribers recompute it and should reject stale timestamps to prevent replays.
+func SignWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func attemptError(a domain.WebhookAttempt) string {
+	if a.Error != "" {
+		return a.Error
+	}
+	return fmt.Sprintf("status %d", a.StatusCode)
+}
+
+func newWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+func parseWebhookID(id, what string) (primitive.ObjectID, error) {
+	objectID, err := primitive.ObjectIDFromHex(id)
+	if err != nil {
+		return primitive.NilObjectID, domain.NotFoundError("%s not found", what)
+	}
+	return objectID, nil
+}
@@ -0,0 +1,334 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// memoryWebhookRepo is an in-memory repository.WebhookRepository
+type memoryWebhookRepo struct {
+	mu         sync.Mutex
+	subs       map[primitive.ObjectID]domain.WebhookSubscription
+	deliveries map[primitive.ObjectID]*domain.WebhookDelivery
+}
+
+func newMemoryWebhookRepo() *memoryWebhookRepo {
+	return &memoryWebhookRepo{
+		subs:       map[primitive.ObjectID]domain.WebhookSubscription{},
+		deliveries: map[primitive.ObjectID]*domain.WebhookDelivery{},
+	}
+}
+
+func (r *memoryWebhookRepo) CreateSubscription(ctx context.Context, sub *domain.WebhookSubscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub.ID = primitive.NewObjectID()
+	r.subs[sub.ID] = *sub
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetSubscription(ctx context.Context, id primitive.ObjectID) (*domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	sub, ok := r.subs[id]
+	if !ok {
+		return nil, domain.NotFoundError("webhook not found")
+	}
+	return &sub, nil
+}
+
+func (r *memoryWebhookRepo) ListSubscriptions(ctx context.Context) ([]domain.WebhookSubscription, error) {
+	return r.SubscriptionsFor(ctx, domain.WebhookEventAll)
+}
+
+func (r *memoryWebhookRepo) SubscriptionsFor(ctx context.Context, event string) ([]domain.WebhookSubscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := []domain.WebhookSubscription{}
+	for _, sub := range r.subs {
+		if event == domain.WebhookEventAll || sub.Subscribes(event) {
+			subs = append(subs, sub)
+		}
+	}
+	return subs, nil
+}
+
+func (r *memoryWebhookRepo) DeleteSubscription(ctx context.Context, id primitive.ObjectID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.subs, id)
+	return nil
+}
+
+func (r *memoryWebhookRepo) CreateDelivery(ctx context.Context, d *domain.WebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	stored := *d
+	r.deliveries[d.ID] = &stored
+	return nil
+}
+
+func (r *memoryWebhookRepo) GetDelivery(ctx context.Context, id primitive.ObjectID) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d, ok := r.deliveries[id]
+	if !ok {
+		return nil, domain.NotFoundError("webhook delivery not found")
+	}
+	copied := *d
+	return &copied, nil
+}
+
+func (r *memoryWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID primitive.ObjectID, limit int) ([]domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	deliveries := []domain.WebhookDelivery{}
+	for _, d := range r.deliveries {
+		if d.SubscriptionID == subscriptionID {
+			deliveries = append(deliveries, *d)
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *memoryWebhookRepo) ClaimDue(ctx context.Context, now time.Time, lease time.Duration) (*domain.WebhookDelivery, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range r.deliveries {
+		if d.Status == domain.DeliveryPending && !d.NextAttemptAt.After(now) {
+			d.Status = domain.DeliveryDelivering
+			copied := *d
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryWebhookRepo) RecordAttempt(ctx context.Context, id primitive.ObjectID, attempt domain.WebhookAttempt, status string, nextAttemptAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	d := r.deliveries[id]
+	d.Attempts = append(d.Attempts, attempt)
+	d.Status = status
+	if status == domain.DeliveryPending {
+		d.NextAttemptAt = nextAttemptAt
+	}
+	return nil
+}
+
+var testWebhookConfig = WebhookConfig{
+	MaxAttempts:    3,
+	RetryBaseDelay: time.Minute,
+	RetryMaxDelay:  time.Hour,
+	Timeout:        time.Second,
+	PollInterval:   time.Second,
+}
+
+// webhookFixture wires a service and dispatcher sharing a repository and a fake clock
+type webhookFixture struct {
+	repo       *memoryWebhookRepo
+	svc        *webhookService
+	dispatcher *WebhookDispatcher
+	now        time.Time
+}
+
+func newWebhookFixture(t *testing.T) *webhookFixture {
+	t.Helper()
+	f := &webhookFixture{repo: newMemoryWebhookRepo(), now: time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)}
+	clock := func() time.Time { return f.now }
+	f.svc = NewWebhookService(f.repo, testWebhookConfig).(*webhookService)
+	f.svc.now = clock
+	f.dispatcher = NewWebhookDispatcher(f.repo, testWebhookConfig)
+	f.dispatcher.now = clock
+	return f
+}
+
+func (f *webhookFixture) subscribe(t *testing.T, url string, events ...string) *CreatedWebhook {
+	t.Helper()
+	created, err := f.svc.CreateSubscription(context.Background(), domain.CreateWebhookRequest{URL: url, Events: events})
+	if err != nil {
+		t.Fatalf("CreateSubscription: %v", err)
+	}
+	return created
+}
+
+func (f *webhookFixture) onlyDelivery(t *testing.T, subID primitive.ObjectID) domain.WebhookDelivery {
+	t.Helper()
+	deliveries, _ := f.repo.ListDeliveries(context.Background(), subID, 10)
+	if len(deliveries) != 1 {
+		t.Fatalf("got %d deliveries, want 1", len(deliveries))
+	}
+	return deliveries[0]
+}
+
+func TestWebhookDeliveryIsSigned(t *testing.T) {
+	type received struct {
+		header http.Header
+		body   []byte
+	}
+	got := make(chan received, 1)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		got <- received{r.Header.Clone(), body}
+	}))
+	defer server.Close()
+
+	f := newWebhookFixture(t)
+	sub := f.subscribe(t, server.URL, domain.WebhookEventConfirm)
+	reservation := &domain.Reservation{ID: primitive.NewObjectID(), Status: domain.StatusConfirmed}
+
+	ctx := context.Background()
+	if err := f.svc.Enqueue(ctx, domain.WebhookEventConfirm, reservation); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := f.svc.Enqueue(ctx, domain.WebhookEventCancel, reservation); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	f.dispatcher.DispatchDue(ctx)
+
+	req := <-got
+	timestamp := req.header.Get(WebhookTimestampHeader)
+	if want := SignWebhook(sub.Secret, timestamp, req.body); req.header.Get(WebhookSignatureHeader) != want {
+		t.Errorf("signature = %q, want %q", req.header.Get(WebhookSignatureHeader), want)
+	}
+	if req.header.Get(WebhookEventHeader) != domain.WebhookEventConfirm {
+		t.Errorf("event header = %q", req.header.Get(WebhookEventHeader))
+	}
+
+	var payload domain.WebhookPayload
+	if err := json.Unmarshal(req.body, &payload); err != nil {
+		t.Fatalf("payload: %v", err)
+	}
+	if payload.Data == nil || payload.Data.ID != reservation.ID {
+		t.Errorf("payload data = %+v, want reservation %s", payload.Data, reservation.ID.Hex())
+	}
+
+	// Only the subscribed event type was queued
+	d := f.onlyDelivery(t, sub.ID)
+	if d.Status != domain.DeliveryDelivered || len(d.Attempts) != 1 || d.Attempts[0].StatusCode != http.StatusOK {
+		t.Errorf("delivery = %+v, want delivered after one 200 attempt", d)
+	}
+}
+
+func TestWebhookDeliveryRetriesWithBackoff(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	f := newWebhookFixture(t)
+	sub := f.subscribe(t, server.URL, domain.WebhookEventAll)
+	ctx := context.Background()
+	if err := f.svc.Enqueue(ctx, domain.WebhookEventCreate, &domain.Reservation{ID: primitive.NewObjectID()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+
+	start := f.now
+	f.dispatcher.DispatchDue(ctx)
+	d := f.onlyDelivery(t, sub.ID)
+	if d.Status != domain.DeliveryPending || !d.NextAttemptAt.Equal(start.Add(time.Minute)) {
+		t.Fatalf("after attempt 1: status %s next %v, want pending at +1m", d.Status, d.NextAttemptAt)
+	}
+
+	// Not due yet: nothing is sent
+	f.dispatcher.DispatchDue(ctx)
+	if calls.Load() != 1 {
+		t.Fatalf("calls = %d before the retry is due, want 1", calls.Load())
+	}
+
+	f.now = d.NextAttemptAt
+	f.dispatcher.DispatchDue(ctx)
+	d = f.onlyDelivery(t, sub.ID)
+	if !d.NextAttemptAt.Equal(f.now.Add(2 * time.Minute)) {
+		t.Fatalf("after attempt 2: next %v, want +2m", d.NextAttemptAt)
+	}
+
+	f.now = d.NextAttemptAt
+	f.dispatcher.DispatchDue(ctx)
+	d = f.onlyDelivery(t, sub.ID)
+	if d.Status != domain.DeliveryFailed || len(d.Attempts) != testWebhookConfig.MaxAttempts {
+		t.Fatalf("after max attempts: status %s with %d attempts, want failed with %d", d.Status, len(d.Attempts), testWebhookConfig.MaxAttempts)
+	}
+	for _, a := range d.Attempts {
+		if a.StatusCode != http.StatusServiceUnavailable {
+			t.Errorf("attempt status code = %d, want 503", a.StatusCode)
+		}
+	}
+}
+
+func TestWebhookReplayResendsPayload(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+	}))
+	defer server.Close()
+
+	f := newWebhookFixture(t)
+	sub := f.subscribe(t, server.URL, domain.WebhookEventAll)
+	other := f.subscribe(t, server.URL, domain.WebhookEventAll)
+	ctx := context.Background()
+	if err := f.svc.Enqueue(ctx, domain.WebhookEventUpdate, &domain.Reservation{ID: primitive.NewObjectID()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	f.dispatcher.DispatchDue(ctx)
+	original := f.onlyDelivery(t, sub.ID)
+
+	if _, err := f.svc.ReplayDelivery(ctx, other.ID.Hex(), original.ID.Hex()); !errors.Is(err, domain.ErrNotFound) {
+		t.Errorf("replay through another webhook: err = %v, want ErrNotFound", err)
+	}
+
+	replay, err := f.svc.ReplayDelivery(ctx, sub.ID.Hex(), original.ID.Hex())
+	if err != nil {
+		t.Fatalf("ReplayDelivery: %v", err)
+	}
+	if replay.Payload != original.Payload || replay.ReplayOf == nil || *replay.ReplayOf != original.ID {
+		t.Errorf("replay = %+v, want the original payload and replay_of", replay)
+	}
+
+	f.dispatcher.DispatchDue(ctx)
+	if calls.Load() != 3 {
+		t.Errorf("calls = %d, want 3 (two originals and one replay)", calls.Load())
+	}
+}
+
+func TestWebhookDeliveryToDeletedSubscriptionFails(t *testing.T) {
+	f := newWebhookFixture(t)
+	sub := f.subscribe(t, "http://127.0.0.1:1/hook", domain.WebhookEventAll)
+	ctx := context.Background()
+	if err := f.svc.Enqueue(ctx, domain.WebhookEventCancel, &domain.Reservation{ID: primitive.NewObjectID()}); err != nil {
+		t.Fatalf("Enqueue: %v", err)
+	}
+	if err := f.svc.DeleteSubscription(ctx, sub.ID.Hex()); err != nil {
+		t.Fatalf("DeleteSubscription: %v", err)
+	}
+
+	f.dispatcher.DispatchDue(ctx)
+	if d := f.onlyDelivery(t, sub.ID); d.Status != domain.DeliveryFailed {
+		t.Errorf("status = %s, want failed without retries", d.Status)
+	}
+}
+
+func TestCreateWebhookValidatesEvents(t *testing.T) {
+	f := newWebhookFixture(t)
+	_, err := f.svc.CreateSubscription(context.Background(), domain.CreateWebhookRequest{
+		URL:    "https://pos.example.com/hooks",
+		Events: []string{domain.WebhookEventCreate, "reservation.deleted"},
+	})
+	var verr *domain.ValidationError
+	if !errors.As(err, &verr) || verr.Fields["events"] == "" {
+		t.Fatalf("err = %v, want a validation error on events", err)
+	}
+}
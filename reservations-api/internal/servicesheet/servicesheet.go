@@ -0,0 +1,294 @@
+// Package servicesheet prints the daily service sheet of the host stand as HTML or PDF
+package servicesheet
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/pdf"
+)
+
+// Page layout in points
+const (
+	marginLeft   = 40
+	marginRight  = pdf.PageWidth - 40
+	marginTop    = pdf.PageHeight - 50
+	marginBottom = 50
+	lineHeight   = 13
+)
+
+// columns of the PDF table: left edge and width of each
+var columns = []struct {
+	title string
+	x     float64
+	width float64
+}{
+	{"Hora", marginLeft, 34},
+	{"Mesa", marginLeft + 34, 30},
+	{"Pers.", marginLeft + 64, 30},
+	{"Cliente", marginLeft + 94, 150},
+	{"Teléfono", marginLeft + 244, 100},
+	{"Estado", marginLeft + 344, 80},
+	{"Prepago", marginLeft + 424, 91},
+}
+
+// WritePDF renders the sheet as an A4 PDF, continuing on new pages as needed
+func WritePDF(w io.Writer, sheet *domain.ServiceSheet) error {
+	d := &document{sheet: sheet}
+	d.newPage()
+
+	if len(sheet.Meals) == 0 {
+		d.page.Text(marginLeft, d.y, 10, false, "No hay reservas para este día.")
+	}
+	for _, meal := range sheet.Meals {
+		d.ensure(3 * lineHeight)
+		d.y -= 6
+		d.page.Text(marginLeft, d.y, 12, true, fmt.Sprintf("%s · %s", capitalize(mealLabel(meal.MealType)), countLabel(meal.Reservations, meal.Guests)))
+		d.y -= lineHeight + 2
+		d.header()
+
+		for _, slot := range meal.Slots {
+			for _, e := range slot.Entries {
+				notes := requestLines(e)
+				d.ensure(float64(1+len(notes)) * lineHeight)
+				cells := []string{e.Time, fmt.Sprint(e.TableNumber), fmt.Sprint(e.Guests), e.GuestName, e.Phone, statusLabel(e.Status), prepaymentLabel(e)}
+				for i, col := range columns {
+					d.page.Text(col.x, d.y, 9, i == 0, fit(cells[i], col.width-4, 9, i == 0))
+				}
+				d.y -= lineHeight
+				for _, note := range notes {
+					d.page.Text(columns[3].x, d.y, 8, false, fit(note, marginRight-columns[3].x, 8, false))
+					d.y -= lineHeight - 2
+				}
+				d.page.Rule(marginLeft, marginRight, d.y+lineHeight-4, 0.2)
+			}
+		}
+		d.y -= lineHeight / 2
+	}
+
+	return pdf.Write(w, "Servicio "+sheet.Date, d.pages...)
+}
+
+// document tracks the page being filled and the baseline of the next line
+type document struct {
+	sheet *domain.ServiceSheet
+	pages []*pdf.Page
+	page  *pdf.Page
+	y     float64
+}
+
+func (d *document) newPage() {
+	sheet := d.sheet
+	d.page = &pdf.Page{}
+	d.pages = append(d.pages, d.page)
+	d.y = marginTop
+
+	title := "Servicio del " + sheet.Date
+	if len(d.pages) > 1 {
+		title += fmt.Sprintf(" (hoja %d)", len(d.pages))
+	}
+	d.page.Text(marginLeft, d.y, 14, true, title)
+	d.page.TextRight(marginRight, d.y, 9, false, countLabel(sheet.Reservations, sheet.Guests))
+	d.y -= lineHeight
+	d.page.Text(marginLeft, d.y, 8, false, "Generado "+sheet.GeneratedAt.Format("02/01/2006 15:04"))
+	if sheet.GuestsUnavailable {
+		d.page.TextRight(marginRight, d.y, 8, true, "Datos de clientes no disponibles")
+	}
+	d.y -= 2 * lineHeight
+}
+
+// ensure starts a new page, repeating the table header, unless height still fits
+func (d *document) ensure(height float64) {
+	if d.y-height >= marginBottom {
+		return
+	}
+	d.newPage()
+	d.header()
+}
+
+func (d *document) header() {
+	for _, col := range columns {
+		d.page.Text(col.x, d.y, 8, true, col.title)
+	}
+	d.page.Rule(marginLeft, marginRight, d.y-4, 0.6)
+	d.y -= lineHeight + 2
+}
+
+// fit shortens s with an ellipsis until it is at most width points wide
+func fit(s string, width, size float64, bold bool) string {
+	if pdf.TextWidth(s, size, bold) <= width {
+		return s
+	}
+	runes := []rune(s)
+	for len(runes) > 0 && pdf.TextWidth(string(runes)+"…", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return string(runes) + "…"
+}
+
+// WriteHTML renders the sheet as a standalone page meant for the browser's print dialog
+func WriteHTML(w io.Writer, sheet *domain.ServiceSheet) error {
+	return sheetTemplate.Execute(w, sheet)
+}
+
+var sheetTemplate = template.Must(template.New("sheet").Funcs(template.FuncMap{
+	"meal":       func(m string) string { return capitalize(mealLabel(m)) },
+	"count":      countLabel,
+	"status":     statusLabel,
+	"prepayment": prepaymentLabel,
+	"requests":   requestLines,
+}).Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Servicio del {{.Date}}</title>
+<style>
+  body { font-family: Helvetica, Arial, sans-serif; font-size: 12px; margin: 24px; }
+  h1 { font-size: 20px; margin: 0; }
+  h2 { font-size: 15px; margin: 24px 0 6px; }
+  table { width: 100%; border-collapse: collapse; }
+  th, td { text-align: left; padding: 4px 6px; border-bottom: 1px solid #ccc; vertical-align: top; }
+  th { border-bottom: 2px solid #333; font-size: 11px; }
+  .meta { color: #555; margin: 4px 0 0; }
+  .warning { color: #b00; font-weight: bold; }
+  .notes { font-size: 11px; color: #333; margin: 2px 0 0; padding: 0; list-style: none; }
+  @media print { body { margin: 0; } h2 { break-after: avoid; } tr { break-inside: avoid; } }
+</style>
+</head>
+<body>
+<h1>Servicio del {{.Date}}</h1>
+<p class="meta">{{count .Reservations .Guests}} · generado {{.GeneratedAt.Format "02/01/2006 15:04"}}</p>
+{{if .GuestsUnavailable}}<p class="warning">Los datos de los clientes no están disponibles en este momento.</p>{{end}}
+{{range .Meals}}
+<h2>{{meal .MealType}} · {{count .Reservations .Guests}}</h2>
+<table>
+<thead><tr><th>Hora</th><th>Mesa</th><th>Pers.</th><th>Cliente</th><th>Teléfono</th><th>Estado</th><th>Prepago</th></tr></thead>
+<tbody>
+{{range .Slots}}{{range .Entries}}<tr>
+<td><strong>{{.Time}}</strong></td><td>{{.TableNumber}}</td><td>{{.Guests}}</td>
+<td>{{.GuestName}}{{with requests .}}<ul class="notes">{{range .}}<li>{{.}}</li>{{end}}</ul>{{end}}</td>
+<td>{{.Phone}}</td><td>{{status .Status}}</td><td>{{prepayment .}}</td>
+</tr>
+{{end}}{{end}}</tbody>
+</table>
+{{else}}
+<p>No hay reservas para este día.</p>
+{{end}}
+</body>
+</html>
+`))
+
+// requestLines lists what the staff must know about a reservation beyond the table
+func requestLines(e domain.ServiceSheetEntry) []string {
+	var lines []string
+	if len(e.Allergies) > 0 {
+		lines = append(lines, "Alergias: "+labels(allergenLabels, e.Allergies))
+	}
+	if len(e.Dietary) > 0 {
+		lines = append(lines, "Dieta: "+labels(dietaryLabels, e.Dietary))
+	}
+	var access []string
+	if e.Accessibility.Wheelchair {
+		access = append(access, "silla de ruedas")
+	}
+	if e.Accessibility.HighChairs > 0 {
+		access = append(access, fmt.Sprintf("%d silla(s) para bebé", e.Accessibility.HighChairs))
+	}
+	if len(access) > 0 {
+		lines = append(lines, "Accesibilidad: "+strings.Join(access, ", "))
+	}
+	if e.Occasion != "" {
+		lines = append(lines, "Ocasión: "+label(occasionLabels, e.Occasion))
+	}
+	if e.PreOrderItems > 0 {
+		lines = append(lines, fmt.Sprintf("Pedido anticipado: %d plato(s)", e.PreOrderItems))
+	}
+	if e.SpecialRequests != "" {
+		lines = append(lines, "Notas: "+e.SpecialRequests)
+	}
+	return lines
+}
+
+func countLabel(reservations, guests int) string {
+	return fmt.Sprintf("%d reservas, %d personas", reservations, guests)
+}
+
+func prepaymentLabel(e domain.ServiceSheetEntry) string {
+	switch e.Prepayment {
+	case domain.PrepaymentPaid:
+		return "Pagado"
+	case domain.PrepaymentPartial:
+		return fmt.Sprintf("Seña %s de %s", e.Prepaid.Decimal(), e.TotalPrice.Decimal())
+	}
+	return "Sin prepago"
+}
+
+func statusLabel(status string) string {
+	return label(statusLabels, status)
+}
+
+func mealLabel(mealType string) string {
+	return label(mealLabels, mealType)
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
+
+func label(labels map[string]string, value string) string {
+	if l, ok := labels[value]; ok {
+		return l
+	}
+	return value
+}
+
+func labels(labels map[string]string, values []string) string {
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = label(labels, v)
+	}
+	return strings.Join(out, ", ")
+}
+
+var mealLabels = map[string]string{
+	domain.MealTypeBreakfast: "desayuno",
+	domain.MealTypeLunch:     "almuerzo",
+	domain.MealTypeDinner:    "cena",
+	domain.MealTypeEvent:     "evento",
+}
+
+var statusLabels = map[string]string{
+	domain.StatusPending:   "Pendiente",
+	domain.StatusConfirmed: "Confirmada",
+	domain.StatusSeated:    "Sentados",
+	domain.StatusCompleted: "Completada",
+	domain.StatusNoShow:    "No vino",
+	domain.StatusCancelled: "Cancelada",
+}
+
+var allergenLabels = map[string]string{
+	"gluten": "gluten", "crustaceans": "crustáceos", "eggs": "huevo", "fish": "pescado",
+	"peanuts": "maní", "soybeans": "soja", "milk": "lácteos", "nuts": "frutos secos",
+	"celery": "apio", "mustard": "mostaza", "sesame": "sésamo", "sulphites": "sulfitos",
+	"lupin": "altramuz", "molluscs": "moluscos",
+}
+
+var dietaryLabels = map[string]string{
+	"vegetarian": "vegetariano", "vegan": "vegano", "pescatarian": "pescetariano",
+	"gluten_free": "sin TACC", "lactose_free": "sin lactosa", "halal": "halal",
+	"kosher": "kosher", "low_sodium": "bajo en sodio", "diabetic": "apto diabéticos",
+}
+
+var occasionLabels = map[string]string{
+	domain.OccasionBirthday:    "cumpleaños",
+	domain.OccasionAnniversary: "aniversario",
+	domain.OccasionBusiness:    "negocios",
+	domain.OccasionDate:        "cita",
+	domain.OccasionCelebration: "celebración",
+}
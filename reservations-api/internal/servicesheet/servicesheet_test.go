@@ -0,0 +1,62 @@
+package servicesheet
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+func sampleSheet(entries int) *domain.ServiceSheet {
+	slot := domain.ServiceSheetSlot{Time: "21:00"}
+	for i := 1; i <= entries; i++ {
+		slot.Entries = append(slot.Entries, domain.ServiceSheetEntry{
+			Time: "21:00", TableNumber: i, Guests: 2, Status: domain.StatusConfirmed,
+			GuestName: fmt.Sprintf("Cliente <%d>", i), Phone: "+54 11 5555-0000",
+			Allergies: []string{"peanuts"}, Occasion: domain.OccasionBirthday,
+			TotalPrice: domain.NewMoney(10000, "ARS"), Prepayment: domain.PrepaymentNone,
+		})
+	}
+	return &domain.ServiceSheet{
+		Date: "2030-03-01", GeneratedAt: time.Date(2030, time.March, 1, 9, 0, 0, 0, time.UTC),
+		Reservations: entries, Guests: 2 * entries,
+		Meals: []domain.ServiceSheetMeal{{MealType: domain.MealTypeDinner, Reservations: entries, Guests: 2 * entries, Slots: []domain.ServiceSheetSlot{slot}}},
+	}
+}
+
+func TestWriteHTML(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteHTML(&buf, sampleSheet(1)); err != nil {
+		t.Fatalf("WriteHTML() error = %v", err)
+	}
+	doc := buf.String()
+
+	for _, want := range []string{"Cena · 1 reservas, 2 personas", "Cliente &lt;1&gt;", "Alergias: maní", "Ocasión: cumpleaños", "Sin prepago"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("HTML does not contain %q", want)
+		}
+	}
+}
+
+func TestWritePDFContinuesOnNewPages(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WritePDF(&buf, sampleSheet(40)); err != nil {
+		t.Fatalf("WritePDF() error = %v", err)
+	}
+	doc := buf.String()
+
+	if !strings.HasPrefix(doc, "%PDF-1.4\n") || !strings.HasSuffix(doc, "%%EOF\n") {
+		t.Fatal("not a PDF document")
+	}
+	if !strings.Contains(doc, "/Count 3 ") || !strings.Contains(doc, `\(hoja 3\)`) {
+		t.Error("40 reservations should continue on three pages")
+	}
+	for _, want := range []string{"(Cliente <40>)", "(Alergias: man\xed)"} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("PDF does not contain %q", want)
+		}
+	}
+}
@@ -0,0 +1,236 @@
+package transfer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+)
+
+// Supported file formats
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+// ExportColumns are the CSV columns written by the exporter. The importer reads
+// owner_id, table_number, guests, date_time, meal_type, special_requests and the
+// structured requests, so exported files can be imported back. Tags are space separated.
+var ExportColumns = []string{
+	"id", "owner_id", "table_number", "guests", "date_time", "meal_type", "status",
+	"total_price", "currency", "special_requests", "allergies", "dietary", "occasion",
+	"wheelchair", "high_chairs", "cancellation_fee", "created_at", "updated_at",
+}
+
+// maxNDJSONLine bounds a single NDJSON record
+const maxNDJSONLine = 1 << 20
+
+// ContentType returns the MIME type of a format
+func ContentType(format string) string {
+	if format == FormatNDJSON {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// ReadRows parses an import file. Row-level problems are reported on each row;
+// an error is returned only when the file itself cannot be read.
+func ReadRows(r io.Reader, format string) ([]domain.ImportRow, error) {
+	switch format {
+	case FormatCSV:
+		return readCSV(r)
+	case FormatNDJSON:
+		return readNDJSON(r)
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+func readCSV(r io.Reader) ([]domain.ImportRow, error) {
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))] = i
+	}
+	for _, required := range []string{"owner_id", "table_number", "guests", "date_time", "meal_type"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing CSV column %q", required)
+		}
+	}
+
+	rows := []domain.ImportRow{}
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rows = append(rows, domain.ImportRow{Line: parseErr.Line, Error: parseErr.Err.Error()})
+				continue
+			}
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		field := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		line, _ := reader.FieldPos(0)
+		row := domain.ImportRow{Line: line}
+		row.Request, err = parseCSVRequest(field)
+		if err != nil {
+			row.Error = err.Error()
+		}
+		rows = append(rows, row)
+	}
+
+	return rows, nil
+}
+
+func parseCSVRequest(field func(string) string) (domain.CreateReservationRequest, error) {
+	req := domain.CreateReservationRequest{
+		OwnerID:         field("owner_id"),
+		MealType:        field("meal_type"),
+		SpecialRequests: field("special_requests"),
+		Allergies:       strings.Fields(field("allergies")),
+		Dietary:         strings.Fields(field("dietary")),
+		Occasion:        field("occasion"),
+	}
+
+	var err error
+	if req.TableNumber, err = strconv.Atoi(field("table_number")); err != nil {
+		return req, fmt.Errorf("invalid table_number %q", field("table_number"))
+	}
+	if req.Guests, err = strconv.Atoi(field("guests")); err != nil {
+		return req, fmt.Errorf("invalid guests %q", field("guests"))
+	}
+	if req.DateTime, err = time.Parse(time.RFC3339, field("date_time")); err != nil {
+		return req, fmt.Errorf("invalid date_time %q (expected RFC 3339)", field("date_time"))
+	}
+	if wheelchair := field("wheelchair"); wheelchair != "" {
+		if req.Accessibility.Wheelchair, err = strconv.ParseBool(wheelchair); err != nil {
+			return req, fmt.Errorf("invalid wheelchair %q", wheelchair)
+		}
+	}
+	if highChairs := field("high_chairs"); highChairs != "" {
+		if req.Accessibility.HighChairs, err = strconv.Atoi(highChairs); err != nil {
+			return req, fmt.Errorf("invalid high_chairs %q", highChairs)
+		}
+	}
+
+	return req, nil
+}
+
+func readNDJSON(r io.Reader) ([]domain.ImportRow, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxNDJSONLine)
+
+	rows := []domain.ImportRow{}
+	line := 0
+	for scanner.Scan() {
+		line++
+		raw := bytes.TrimSpace(scanner.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+
+		row := domain.ImportRow{Line: line}
+		if err := json.Unmarshal(raw, &row.Request); err != nil {
+			row.Error = fmt.Sprintf("invalid JSON: %v", err)
+		}
+		rows = append(rows, row)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NDJSON: %w", err)
+	}
+
+	return rows, nil
+}
+
+// Writer streams reservations in CSV or NDJSON
+type Writer struct {
+	format string
+	csv    *csv.Writer
+	json   *json.Encoder
+}
+
+// NewWriter creates a writer for the given format and writes the CSV header if needed
+func NewWriter(w io.Writer, format string) (*Writer, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		if err := cw.Write(ExportColumns); err != nil {
+			return nil, err
+		}
+		return &Writer{format: format, csv: cw}, nil
+	case FormatNDJSON:
+		return &Writer{format: format, json: json.NewEncoder(w)}, nil
+	}
+	return nil, fmt.Errorf("unsupported format %q", format)
+}
+
+// Write encodes a single reservation
+func (w *Writer) Write(r domain.Reservation) error {
+	if w.format == FormatNDJSON {
+		// Encode appends the newline that separates NDJSON records
+		return w.json.Encode(r)
+	}
+
+	return w.csv.Write([]string{
+		r.ID.Hex(),
+		r.OwnerID,
+		strconv.Itoa(r.TableNumber),
+		strconv.Itoa(r.Guests),
+		r.DateTime.Format(time.RFC3339),
+		r.MealType,
+		r.Status,
+		r.TotalPrice.Decimal(),
+		r.TotalPrice.Currency,
+		r.SpecialRequests,
+		strings.Join(r.Allergies, " "),
+		strings.Join(r.Dietary, " "),
+		r.Occasion,
+		strconv.FormatBool(r.Accessibility.Wheelchair),
+		strconv.Itoa(r.Accessibility.HighChairs),
+		cancellationFee(r),
+		r.CreatedAt.Format(time.RFC3339),
+		r.UpdatedAt.Format(time.RFC3339),
+	})
+}
+
+// cancellationFee formats the fee in the reservation's currency, zero when none was charged
+func cancellationFee(r domain.Reservation) string {
+	if r.CancellationFee == nil {
+		return domain.NewMoney(0, r.TotalPrice.Currency).Decimal()
+	}
+	return r.CancellationFee.Decimal()
+}
+
+// Flush pushes buffered CSV rows to the underlying writer
+func (w *Writer) Flush() error {
+	if w.csv == nil {
+		return nil
+	}
+	w.csv.Flush()
+	return w.csv.Error()
+}
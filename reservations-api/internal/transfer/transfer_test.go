@@ -0,0 +1,222 @@
+package transfer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestReadCSV(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		// want is the error of each row, "" for a valid row
+		want []string
+	}{
+		{
+			name:  "columns in any order and case, with a BOM",
+			input: "\ufeffMeal_Type,guests,owner_id,table_number,date_time,wheelchair\ndinner,2,7,3,2030-03-01T21:00:00Z,true\n",
+			want:  []string{""},
+		},
+		{
+			name: "bad rows are reported per line",
+			input: "owner_id,table_number,guests,date_time,meal_type\n" +
+				"7,x,2,2030-03-01T21:00:00Z,dinner\n" +
+				"7,3,two,2030-03-01T21:00:00Z,dinner\n" +
+				"7,3,2,01/03/2030,dinner\n" +
+				"7,3,2,2030-03-01T21:00:00Z,dinner\n",
+			want: []string{`invalid table_number "x"`, `invalid guests "two"`, `invalid date_time "01/03/2030" (expected RFC 3339)`, ""},
+		},
+		{
+			name:  "unbalanced quotes",
+			input: "owner_id,table_number,guests,date_time,meal_type\n7,3,2,2030-03-01T21:00:00Z,\"dinner\n",
+			want:  []string{"extraneous or missing \" in quoted-field"},
+		},
+		{
+			name:    "missing required column",
+			input:   "owner_id,table_number,guests,date_time\n7,3,2,2030-03-01T21:00:00Z\n",
+			wantErr: true,
+		},
+		{
+			name:    "empty file",
+			input:   "",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows, err := ReadRows(strings.NewReader(tt.input), FormatCSV)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ReadRows() = %+v, want an error", rows)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ReadRows() error = %v", err)
+			}
+			if len(rows) != len(tt.want) {
+				t.Fatalf("got %d rows, want %d: %+v", len(rows), len(tt.want), rows)
+			}
+			for i, row := range rows {
+				if row.Error != tt.want[i] {
+					t.Errorf("row %d error = %q, want %q", i, row.Error, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestReadCSVLineNumbers(t *testing.T) {
+	input := "owner_id,table_number,guests,date_time,meal_type\n7,3,2,2030-03-01T21:00:00Z,dinner\n\n7,x,2,2030-03-01T21:00:00Z,dinner\n"
+	rows, err := ReadRows(strings.NewReader(input), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 || rows[0].Line != 2 || rows[1].Line != 4 {
+		t.Errorf("rows = %+v, want lines 2 and 4", rows)
+	}
+	if req := rows[0].Request; req.OwnerID != "7" || req.TableNumber != 3 || req.Guests != 2 || req.MealType != domain.MealTypeDinner {
+		t.Errorf("request = %+v", req)
+	}
+}
+
+func TestReadNDJSON(t *testing.T) {
+	input := `{"owner_id":"7","table_number":3,"guests":2,"date_time":"2030-03-01T21:00:00Z","meal_type":"dinner"}
+
+{"owner_id":"7","table_number":
+{"owner_id":"8","table_number":4,"guests":4,"date_time":"2030-03-01T21:00:00Z","meal_type":"dinner"}
+`
+	rows, err := ReadRows(strings.NewReader(input), FormatNDJSON)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 3 {
+		t.Fatalf("got %d rows, want 3 (blank lines skipped): %+v", len(rows), rows)
+	}
+	if rows[0].Error != "" || rows[2].Error != "" || rows[2].Line != 4 {
+		t.Errorf("valid rows = %+v, %+v", rows[0], rows[2])
+	}
+	if rows[1].Line != 3 || !strings.HasPrefix(rows[1].Error, "invalid JSON") {
+		t.Errorf("broken row = %+v, want an invalid JSON error on line 3", rows[1])
+	}
+}
+
+func TestReadRowsUnsupportedFormat(t *testing.T) {
+	if _, err := ReadRows(strings.NewReader(""), "xml"); err == nil {
+		t.Error("ReadRows() succeeded, want an unsupported format error")
+	}
+	if _, err := NewWriter(&bytes.Buffer{}, "xml"); err == nil {
+		t.Error("NewWriter() succeeded, want an unsupported format error")
+	}
+}
+
+func testReservations() []domain.Reservation {
+	at := time.Date(2030, time.March, 1, 21, 0, 0, 0, time.UTC)
+	fee := domain.NewMoney(2500, domain.DefaultCurrency)
+	return []domain.Reservation{
+		{
+			ID: primitive.NewObjectID(), OwnerID: "7", TableNumber: 3, Guests: 2, DateTime: at, MealType: domain.MealTypeDinner,
+			Status: domain.StatusConfirmed, TotalPrice: domain.NewMoney(5000, domain.DefaultCurrency), SpecialRequests: "window, please",
+			Allergies: []string{"gluten", "nuts"}, Occasion: "birthday", Accessibility: domain.AccessibilityNeeds{Wheelchair: true, HighChairs: 1},
+			CreatedAt: at.Add(-48 * time.Hour), UpdatedAt: at.Add(-24 * time.Hour),
+		},
+		{
+			ID: primitive.NewObjectID(), OwnerID: "8", TableNumber: 4, Guests: 4, DateTime: at, MealType: domain.MealTypeDinner,
+			Status: domain.StatusCancelled, TotalPrice: domain.NewMoney(10000, domain.DefaultCurrency), CancellationFee: &fee,
+			CreatedAt: at.Add(-48 * time.Hour), UpdatedAt: at.Add(-time.Hour),
+		},
+	}
+}
+
+// Exported files can be imported back, in both formats
+func TestRoundTrip(t *testing.T) {
+	for _, format := range []string{FormatCSV, FormatNDJSON} {
+		t.Run(format, func(t *testing.T) {
+			var buf bytes.Buffer
+			w, err := NewWriter(&buf, format)
+			if err != nil {
+				t.Fatal(err)
+			}
+			reservations := testReservations()
+			for _, r := range reservations {
+				if err := w.Write(r); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := w.Flush(); err != nil {
+				t.Fatal(err)
+			}
+
+			rows, err := ReadRows(&buf, format)
+			if err != nil {
+				t.Fatalf("ReadRows() error = %v", err)
+			}
+			if len(rows) != len(reservations) {
+				t.Fatalf("got %d rows, want %d", len(rows), len(reservations))
+			}
+			for i, row := range rows {
+				want := reservations[i]
+				req := row.Request
+				if row.Error != "" {
+					t.Errorf("row %d error = %s", i, row.Error)
+				}
+				if req.OwnerID != want.OwnerID || req.TableNumber != want.TableNumber || req.Guests != want.Guests ||
+					!req.DateTime.Equal(want.DateTime) || req.MealType != want.MealType || req.SpecialRequests != want.SpecialRequests ||
+					strings.Join(req.Allergies, " ") != strings.Join(want.Allergies, " ") || req.Occasion != want.Occasion ||
+					req.Accessibility != want.Accessibility {
+					t.Errorf("row %d = %+v, want the fields of %+v", i, req, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteCSVColumns(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range testReservations() {
+		if err := w.Write(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Flush(); err != nil {
+		t.Fatal(err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != strings.Join(ExportColumns, ",") {
+		t.Fatalf("records = %v, want the header and two rows", records)
+	}
+	column := func(record []string, name string) string {
+		for i, c := range ExportColumns {
+			if c == name {
+				return record[i]
+			}
+		}
+		t.Fatalf("no column %q", name)
+		return ""
+	}
+	if got := column(records[1], "cancellation_fee"); got != "0.00" {
+		t.Errorf("cancellation_fee without a fee = %q, want 0.00", got)
+	}
+	if got := column(records[2], "cancellation_fee"); got != "25.00" {
+		t.Errorf("cancellation_fee = %q, want 25.00", got)
+	}
+	if got := column(records[1], "total_price"); got != "50.00" {
+		t.Errorf("total_price = %q, want 50.00", got)
+	}
+}
@@ -0,0 +1,102 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"log"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/domain"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+)
+
+// Machine-readable error codes
+const (
+	CodeNotFound            = "not_found"
+	CodeConflict            = "conflict"
+	CodeValidationFailed    = "validation_failed"
+	CodeForbidden           = "forbidden"
+	CodeUpstreamUnavailable = "upstream_unavailable"
+	CodeTimeout             = "timeout"
+	CodeInternal            = "internal_error"
+)
+
+// ErrorResponse is the body of every error response
+type ErrorResponse struct {
+	Code   string            `json:"code"`
+	Error  string            `json:"error"`
+	Fields map[string]string `json:"fields,omitempty"`
+}
+
+// ErrorHandler writes the last error recorded with ctx.Error as an ErrorResponse
+func ErrorHandler() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 {
+			return
+		}
+		err := c.Errors.Last().Err
+		if c.Writer.Written() {
+			// Streaming responses fail after the headers are sent
+			log.Printf("%s %s failed after responding: %v", c.Request.Method, c.Request.URL.Path, err)
+			return
+		}
+
+		status, body := mapError(err)
+		if status >= http.StatusInternalServerError {
+			log.Printf("%s %s: %v", c.Request.Method, c.Request.URL.Path, err)
+		}
+		c.JSON(status, body)
+	}
+}
+
+// mapError picks the status code and body for an error by its category
+func mapError(err error) (int, ErrorResponse) {
+	var verr *domain.ValidationError
+	switch {
+	case errors.As(err, &verr):
+		return http.StatusBadRequest, ErrorResponse{Code: CodeValidationFailed, Error: verr.Error(), Fields: verr.Fields}
+	case errors.Is(err, domain.ErrValidation):
+		return http.StatusBadRequest, ErrorResponse{Code: CodeValidationFailed, Error: err.Error()}
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound, ErrorResponse{Code: CodeNotFound, Error: err.Error()}
+	case errors.Is(err, domain.ErrConflict):
+		return http.StatusConflict, ErrorResponse{Code: CodeConflict, Error: err.Error()}
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden, ErrorResponse{Code: CodeForbidden, Error: err.Error()}
+	case errors.Is(err, domain.ErrUpstream):
+		return http.StatusServiceUnavailable, ErrorResponse{Code: CodeUpstreamUnavailable, Error: err.Error()}
+	case errors.Is(err, context.DeadlineExceeded):
+		return http.StatusGatewayTimeout, ErrorResponse{Code: CodeTimeout, Error: "request timed out"}
+	}
+	// Never leak driver or infrastructure details
+	return http.StatusInternalServerError, ErrorResponse{Code: CodeInternal, Error: "internal server error"}
+}
+
+// abortWithError stops the chain with an ErrorResponse (used by middlewares that run before handlers)
+func abortWithError(c *gin.Context, status int, code, message string) {
+	c.AbortWithStatusJSON(status, ErrorResponse{Code: code, Error: message})
+}
+
+// useJSONFieldNames makes binding errors report fields by their JSON name
+func useJSONFieldNames() {
+	v, ok := binding.Validator.Engine().(*validator.Validate)
+	if !ok {
+		return
+	}
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		if name == "" {
+			return field.Name
+		}
+		return name
+	})
+}
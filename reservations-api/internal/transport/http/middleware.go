@@ -0,0 +1,77 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/auth"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/controller"
+	"github.com/gin-gonic/gin"
+)
+
+// AuthMiddleware validates users-api access tokens on protected routes
+type AuthMiddleware struct {
+	verifier *auth.Verifier
+}
+
+func NewAuthMiddleware(verifier *auth.Verifier) *AuthMiddleware {
+	return &AuthMiddleware{verifier: verifier}
+}
+
+// Authenticate requires a valid "Bearer <token>" header and stores the claims in the context
+func (m *AuthMiddleware) Authenticate() gin.HandlerFunc {
+	return m.authenticate(false)
+}
+
+// AuthenticateStream is Authenticate for Server-Sent Events: browsers' EventSource cannot
+// set headers, so the token may also come in the access_token query parameter
+func (m *AuthMiddleware) AuthenticateStream() gin.HandlerFunc {
+	return m.authenticate(true)
+}
+
+func (m *AuthMiddleware) authenticate(allowQueryToken bool) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		authHeader := c.GetHeader("Authorization")
+		if authHeader == "" && allowQueryToken && c.Query("access_token") != "" {
+			authHeader = "Bearer " + c.Query("access_token")
+		}
+		if authHeader == "" {
+			abortWithError(c, http.StatusUnauthorized, "missing_authorization_header", "authorization header is required")
+			return
+		}
+
+		// Expected format: "Bearer <token>"
+		parts := strings.Split(authHeader, " ")
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			abortWithError(c, http.StatusUnauthorized, "invalid_authorization_format", "authorization header must be \"Bearer <token>\"")
+			return
+		}
+
+		claims, err := m.verifier.Verify(parts[1])
+		if err != nil {
+			abortWithError(c, http.StatusUnauthorized, "invalid_token", "invalid or expired token")
+			return
+		}
+
+		controller.SetClaims(c, claims)
+		c.Next()
+	}
+}
+
+// RequireAdmin rejects authenticated callers that are not administrators
+func (m *AuthMiddleware) RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		claims, ok := controller.ClaimsFromContext(c)
+		if !ok {
+			abortWithError(c, http.StatusForbidden, "missing_role_in_context", "authentication is required")
+			return
+		}
+
+		if !claims.IsAdmin() {
+			abortWithError(c, http.StatusForbidden, "admin_access_required", "admin access required")
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -1,18 +1,23 @@
 package http
 
 import (
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/auth"
 	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/controller"
+	"github.com/blassardoy/restaurant-reservas/reservations-api/internal/service"
 	"github.com/gin-gonic/gin"
 )
 
-func NewRouter(ctrl *controller.ReservationController) *gin.Engine {
+func NewRouter(ctrl *controller.ReservationController, analyticsCtrl *controller.AnalyticsController, webhookCtrl *controller.WebhookController, streamCtrl *controller.StreamController, invoiceCtrl *controller.InvoiceController, menuCtrl *controller.MenuController, sheetCtrl *controller.ServiceSheetController, userClient *service.UserClient, jwtSecret string) *gin.Engine {
 	r := gin.Default()
+	r.Use(ErrorHandler())
+	useJSONFieldNames()
+	authMiddleware := NewAuthMiddleware(auth.NewVerifier(jwtSecret))
 
 	// CORS middleware
 	r.Use(func(c *gin.Context) {
 		c.Writer.Header().Set("Access-Control-Allow-Origin", "*")
 		c.Writer.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		c.Writer.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, Last-Event-ID")
 
 		if c.Request.Method == "OPTIONS" {
 			c.AbortWithStatus(204)
@@ -27,23 +32,99 @@ func NewRouter(ctrl *controller.ReservationController) *gin.Engine {
 		c.JSON(200, gin.H{"status": "healthy"})
 	})
 
+	// Dependency metrics (Users API circuit breaker, cache and retries)
+	r.GET("/metrics", func(c *gin.Context) {
+		c.JSON(200, gin.H{"users_api": userClient.Metrics()})
+	})
+
 	// API routes
 	api := r.Group("/api")
 	{
 		reservations := api.Group("/reservations")
 		{
 			reservations.POST("", ctrl.CreateReservation)
+			reservations.POST("/quote", ctrl.QuoteReservation)
 			reservations.GET("", ctrl.GetAllReservations)
+			reservations.GET("/stream", authMiddleware.AuthenticateStream(), authMiddleware.RequireAdmin(), streamCtrl.StreamReservations)
 			reservations.GET("/:id", ctrl.GetReservation)
 			reservations.GET("/user/:user_id", ctrl.GetUserReservations)
+			reservations.GET("/user/:user_id/calendar", authMiddleware.Authenticate(), ctrl.GetCalendarFeedLinks)
+			reservations.GET("/user/:user_id/calendar.ics", ctrl.GetCalendarFeed)
+			reservations.GET("/:id/ics", ctrl.GetReservationICS)
+			reservations.GET("/:id/checkin", authMiddleware.Authenticate(), ctrl.GetCheckInToken)
+			reservations.GET("/:id/checkin.png", authMiddleware.Authenticate(), ctrl.GetCheckInQR)
+			reservations.GET("/:id/invoice.pdf", authMiddleware.Authenticate(), invoiceCtrl.GetInvoicePDF)
 			reservations.PUT("/:id", ctrl.UpdateReservation)
+			reservations.PUT("/:id/pre-order", authMiddleware.Authenticate(), menuCtrl.UpdatePreOrder)
 			reservations.DELETE("/:id", ctrl.DeleteReservation)
 			reservations.POST("/:id/confirm", ctrl.ConfirmReservation)
+			reservations.POST("/:id/cancel", ctrl.CancelReservation)
+			reservations.POST("/:id/complete", authMiddleware.Authenticate(), authMiddleware.RequireAdmin(), ctrl.CompleteReservation)
 		}
 
+		// Tables held while the guest completes the booking form
+		api.POST("/holds", authMiddleware.Authenticate(), ctrl.HoldTable)
+
+		api.GET("/cancellation-policies", ctrl.GetCancellationPolicies)
+		api.GET("/menu", menuCtrl.ListMenu)
+
+		admin := api.Group("/admin", authMiddleware.Authenticate(), authMiddleware.RequireAdmin())
+		{
+			admin.POST("/reservations/import", ctrl.ImportReservations)
+			admin.GET("/reservations/export", ctrl.ExportReservations)
+			admin.POST("/reservations/:id/prepayment", ctrl.RecordPrepayment)
+			admin.GET("/erasures/:user_id", ctrl.GetErasureReceipt)
+
+			// Cancel or move the reservations of a service, e.g. when it has to close
+			bulkJobs := admin.Group("/bulk-jobs")
+			{
+				bulkJobs.GET("", ctrl.ListBulkJobs)
+				bulkJobs.POST("", ctrl.StartBulkJob)
+				bulkJobs.GET("/:id", ctrl.GetBulkJob)
+			}
+
+			analytics := admin.Group("/analytics")
+			{
+				analytics.GET("/covers", analyticsCtrl.GetCovers)
+				analytics.GET("/utilisation", analyticsCtrl.GetUtilisation)
+				analytics.GET("/revenue", analyticsCtrl.GetRevenue)
+				analytics.GET("/rates", analyticsCtrl.GetRates)
+				analytics.GET("/lead-time", analyticsCtrl.GetLeadTime)
+			}
+
+			menu := admin.Group("/menu")
+			{
+				menu.GET("", menuCtrl.ListMenuAdmin)
+				menu.POST("", menuCtrl.CreateMenuItem)
+				menu.PUT("/:id", menuCtrl.UpdateMenuItem)
+				menu.DELETE("/:id", menuCtrl.DeleteMenuItem)
+			}
+
+			// Pre-ordered portions per day and meal, for the kitchen
+			admin.GET("/kitchen/prep", menuCtrl.GetKitchenPrep)
+
+			webhooks := admin.Group("/webhooks")
+			{
+				webhooks.GET("", webhookCtrl.ListWebhooks)
+				webhooks.POST("", webhookCtrl.CreateWebhook)
+				webhooks.DELETE("/:id", webhookCtrl.DeleteWebhook)
+				webhooks.GET("/:id/deliveries", webhookCtrl.ListDeliveries)
+				webhooks.POST("/:id/deliveries/:delivery_id/replay", webhookCtrl.ReplayDelivery)
+			}
+		}
+
+		// Staff redeem the guest's QR code at the host stand
+		api.POST("/checkin", authMiddleware.Authenticate(), authMiddleware.RequireAdmin(), ctrl.CheckIn)
+		// The day's service for the host stand, as JSON or printable HTML/PDF
+		api.GET("/service-sheet", authMiddleware.Authenticate(), authMiddleware.RequireAdmin(), sheetCtrl.GetServiceSheet)
+		// Guests seated by the host without a reservation
+		api.POST("/walk-ins", authMiddleware.Authenticate(), authMiddleware.RequireAdmin(), ctrl.SeatWalkIn)
+
 		tables := api.Group("/tables")
 		{
 			tables.GET("/available", ctrl.GetAvailableTables)
+			tables.GET("/calendar", ctrl.GetAvailabilityCalendar)
+			tables.GET("/status", authMiddleware.Authenticate(), authMiddleware.RequireAdmin(), ctrl.GetFloorStatus)
 		}
 	}
 
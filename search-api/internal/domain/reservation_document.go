@@ -11,7 +11,23 @@ type ReservationDocument struct {
 	DateTime    time.Time `json:"date_time"`
 	MealType    string    `json:"meal_type"`
 	Status      string    `json:"status"`
-	TotalPrice  float64   `json:"total_price"`
+	WalkIn      bool      `json:"walk_in,omitempty"`
+	TotalPrice  Money     `json:"total_price"`
 	CreatedAt   time.Time `json:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at"`
 }
+
+// HoldsTable reports whether the reservation keeps its table for the day and meal:
+// cancelled reservations and walk-ins that have left release it
+func (d ReservationDocument) HoldsTable() bool {
+	if d.Status == "cancelled" {
+		return false
+	}
+	return !(d.WalkIn && d.Status == "completed")
+}
+
+// Money is an amount in the minor unit of its ISO 4217 currency, as sent by reservations-api
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
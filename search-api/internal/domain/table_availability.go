@@ -16,10 +16,28 @@ type TableAvailability struct {
 	Date          string    `json:"date"`           // Format: "2006-01-02"
 	IsAvailable   bool      `json:"is_available"`   // true if available, false if reserved
 	ReservationID string    `json:"reservation_id,omitempty"` // ID of reservation if taken
+	// HeldUntil is set while a guest holds the table during checkout
+	HeldUntil *time.Time `json:"held_until,omitempty"`
 	CreatedAt     time.Time `json:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// HoldEvent is a table hold taken or released in reservations-api
+type HoldEvent struct {
+	ID          string    `json:"id"`
+	TableNumber int       `json:"table_number"`
+	MealType    string    `json:"meal_type"`
+	DateTime    time.Time `json:"date_time"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// TableSlot is the table, meal type and day an update moved a reservation off
+type TableSlot struct {
+	TableNumber int       `json:"table_number"`
+	MealType    string    `json:"meal_type"`
+	DateTime    time.Time `json:"date_time"`
+}
+
 // GenerateTableAvailabilityID creates a unique ID for a table availability
 // Format: table-{meal_type}-{table_number}-{YYYY-MM-DD}
 func GenerateTableAvailabilityID(mealType string, tableNumber int, date string) string {
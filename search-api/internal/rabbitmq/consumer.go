@@ -8,6 +8,7 @@ import (
 
 	"context"
 
+	"github.com/blassardoy/restaurant-reservas/search-api/internal/domain"
 	"github.com/blassardoy/restaurant-reservas/search-api/internal/service"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
@@ -17,6 +18,10 @@ type EventMessage struct {
 	EntityID   string    `json:"entity_id"`
 	EntityType string    `json:"entity_type"`
 	Timestamp  time.Time `json:"timestamp"`
+	// Previous is set on the updates that moved a reservation to another slot
+	Previous *domain.TableSlot `json:"previous,omitempty"`
+	// Hold is set on the hold and release events of table holds
+	Hold *domain.HoldEvent `json:"hold,omitempty"`
 }
 
 type Consumer struct {
@@ -79,11 +84,20 @@ func (c *Consumer) Run(ctx context.Context) error {
 				_ = m.Nack(false, false)
 				continue
 			}
+			if evt.EntityType == "hold" && evt.Hold != nil {
+				if err := c.sync.HandleHold(ctx, evt.Operation, *evt.Hold); err != nil {
+					log.Printf("sync error: %v", err)
+					_ = m.Nack(false, true)
+					continue
+				}
+				_ = m.Ack(false)
+				continue
+			}
 			if evt.EntityType != "reservation" || evt.EntityID == "" || evt.Operation == "" {
 				_ = m.Nack(false, false)
 				continue
 			}
-			if err := c.sync.HandleEvent(ctx, evt.Operation, evt.EntityID); err != nil {
+			if err := c.sync.HandleEvent(ctx, evt.Operation, evt.EntityID, evt.Previous); err != nil {
 				log.Printf("sync error: %v", err)
 				_ = m.Nack(false, true)
 				continue
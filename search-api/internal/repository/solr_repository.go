@@ -141,6 +141,21 @@ func mapToTableAvailability(m map[string]any) domain.TableAvailability {
 		}
 	}
 
+	// HeldUntil - can be string or array
+	var heldUntilStr string
+	if v, ok := m[solr.FieldHeldUntil].(string); ok {
+		heldUntilStr = v
+	} else if arr, ok := m[solr.FieldHeldUntil].([]interface{}); ok && len(arr) > 0 {
+		if str, ok := arr[0].(string); ok {
+			heldUntilStr = str
+		}
+	}
+	if heldUntilStr != "" {
+		if t, err := time.Parse(time.RFC3339, heldUntilStr); err == nil {
+			doc.HeldUntil = &t
+		}
+	}
+
 	// CreatedAt - can be string or array
 	var createdAtStr string
 	if v, ok := m[solr.FieldCreatedAt].(string); ok {
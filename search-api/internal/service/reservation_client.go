@@ -4,6 +4,7 @@ import (
     "encoding/json"
     "fmt"
     "net/http"
+    "net/url"
     "time"
 
     "github.com/blassardoy/restaurant-reservas/search-api/internal/domain"
@@ -44,3 +45,17 @@ func (c *ReservationClient) GetAllReservations() ([]domain.ReservationDocument,
     if err := json.NewDecoder(resp.Body).Decode(&docs); err != nil { return nil, err }
     return docs, nil
 }
+
+// GetSlotReservations lists the reservations of a day ("2006-01-02") and meal
+func (c *ReservationClient) GetSlotReservations(date, mealType string) ([]domain.ReservationDocument, error) {
+    query := url.Values{"meal_type": {mealType}, "from": {date}, "to": {date}, "limit": {"1000"}}
+    resp, err := c.httpc.Get(fmt.Sprintf("%s/api/reservations?%s", c.baseURL, query.Encode()))
+    if err != nil { return nil, err }
+    defer resp.Body.Close()
+    if resp.StatusCode != http.StatusOK {
+        return nil, fmt.Errorf("reservations api status %d", resp.StatusCode)
+    }
+    var docs []domain.ReservationDocument
+    if err := json.NewDecoder(resp.Body).Decode(&docs); err != nil { return nil, err }
+    return docs, nil
+}
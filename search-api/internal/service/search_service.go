@@ -123,7 +123,7 @@ func (s *searchService) Reindex(ctx context.Context) error {
 	// Create a map of reserved tables: "date-mealtype-tablenumber" -> reservationID
 	reservedTables := make(map[string]string)
 	for _, res := range reservations {
-		if res.Status != "cancelled" {
+		if res.HoldsTable() {
 			date := res.DateTime.Format("2006-01-02")
 			key := fmt.Sprintf("%s-%s-%d", date, res.MealType, res.TableNumber)
 			reservedTables[key] = res.ID
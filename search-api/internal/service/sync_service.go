@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"time"
 
 	"github.com/blassardoy/restaurant-reservas/search-api/internal/cache"
 	"github.com/blassardoy/restaurant-reservas/search-api/internal/domain"
@@ -20,8 +21,9 @@ func NewSyncService(repo repository.SearchRepository, resClient *ReservationClie
 	return &SyncService{repo: repo, resClient: resClient, cache: cacheLayer}
 }
 
-// HandleEvent processes reservation events and updates table availability in Solr
-func (s *SyncService) HandleEvent(ctx context.Context, op string, reservationID string) error {
+// HandleEvent processes reservation events and updates table availability in Solr.
+// previous is the slot an update moved the reservation off, nil otherwise.
+func (s *SyncService) HandleEvent(ctx context.Context, op string, reservationID string, previous *domain.TableSlot) error {
 	log.Printf("HandleEvent: op=%s, reservationID=%s", op, reservationID)
 
 	// Get reservation details from Reservations API
@@ -37,11 +39,7 @@ func (s *SyncService) HandleEvent(ctx context.Context, op string, reservationID
 	date := reservation.DateTime.Format("2006-01-02")
 
 	// Get table capacity from predefined tables
-	capacity, found := getTableCapacity(tableNumber, mealType)
-	if !found {
-		log.Printf("WARNING: Unknown table config for table %d, meal_type %s. Using default capacity 4", tableNumber, mealType)
-		capacity = 4
-	}
+	capacity := tableCapacityOrDefault(tableNumber, mealType)
 
 	// Generate TableAvailability ID
 	tableAvailID := domain.GenerateTableAvailabilityID(mealType, tableNumber, date)
@@ -58,27 +56,23 @@ func (s *SyncService) HandleEvent(ctx context.Context, op string, reservationID
 		updateErr = s.repo.Index(ctx, *tableAvail)
 
 	case "delete", "cancel":
-		// Mark table as available again (reservation cancelled/deleted)
-		tableAvail := domain.NewTableAvailability(tableNumber, capacity, mealType, date)
-		tableAvail.IsAvailable = true
-		tableAvail.ReservationID = ""
+		// Release the table (reservation cancelled/deleted), unless another
+		// reservation still holds it
+		updateErr = s.releaseTable(ctx, reservationID, tableNumber, capacity, mealType, date)
 
-		log.Printf("Marking table as AVAILABLE: %s", tableAvailID)
-		updateErr = s.repo.Update(ctx, *tableAvail)
-
-	case "update":
+	case "update", "complete":
 		// For updates, check the reservation status
-		// If status is cancelled, mark as available; otherwise keep as unavailable
-		tableAvail := domain.NewTableAvailability(tableNumber, capacity, mealType, date)
-		if reservation.Status == "cancelled" {
-			tableAvail.IsAvailable = true
-			tableAvail.ReservationID = ""
-			log.Printf("Update: Marking table as AVAILABLE (cancelled): %s", tableAvailID)
-		} else {
-			tableAvail.IsAvailable = false
-			tableAvail.ReservationID = reservationID
-			log.Printf("Update: Keeping table as UNAVAILABLE: %s", tableAvailID)
+		// If it no longer holds the table (cancelled, or a walk-in that left), release
+		// it; otherwise keep as unavailable
+		if !reservation.HoldsTable() {
+			log.Printf("Update: Releasing table (%s): %s", reservation.Status, tableAvailID)
+			updateErr = s.releaseTable(ctx, reservationID, tableNumber, capacity, mealType, date)
+			break
 		}
+		tableAvail := domain.NewTableAvailability(tableNumber, capacity, mealType, date)
+		tableAvail.IsAvailable = false
+		tableAvail.ReservationID = reservationID
+		log.Printf("Update: Keeping table as UNAVAILABLE: %s", tableAvailID)
 		updateErr = s.repo.Update(ctx, *tableAvail)
 
 	default:
@@ -91,6 +85,20 @@ func (s *SyncService) HandleEvent(ctx context.Context, op string, reservationID
 		return updateErr
 	}
 
+	// A moved reservation no longer holds the slot it left
+	if previous != nil {
+		prevDate := previous.DateTime.Format("2006-01-02")
+		prevID := domain.GenerateTableAvailabilityID(previous.MealType, previous.TableNumber, prevDate)
+		if prevID != tableAvailID {
+			log.Printf("Moved from %s: releasing it", prevID)
+			prevCapacity := tableCapacityOrDefault(previous.TableNumber, previous.MealType)
+			if err := s.releaseTable(ctx, reservationID, previous.TableNumber, prevCapacity, previous.MealType, prevDate); err != nil {
+				log.Printf("ERROR: Failed to update Solr for table %s: %v", prevID, err)
+				return err
+			}
+		}
+	}
+
 	// Clear cache on success
 	if s.cache != nil {
 		s.cache.Clear()
@@ -101,6 +109,82 @@ func (s *SyncService) HandleEvent(ctx context.Context, op string, reservationID
 	return nil
 }
 
+// releaseTable marks the table as available for the day and meal once the given
+// reservation no longer holds it. Like Reindex, the slot is derived from the
+// reservations: if another one still holds the table (e.g. a booking after a
+// walk-in that left), the table stays reserved for it.
+func (s *SyncService) releaseTable(ctx context.Context, reservationID string, tableNumber, capacity int, mealType, date string) error {
+	reservations, err := s.resClient.GetSlotReservations(date, mealType)
+	if err != nil {
+		return fmt.Errorf("failed to get reservations for %s %s: %w", date, mealType, err)
+	}
+
+	tableAvail := domain.NewTableAvailability(tableNumber, capacity, mealType, date)
+	tableAvail.IsAvailable = true
+	for _, res := range reservations {
+		if res.ID != reservationID && res.TableNumber == tableNumber && res.HoldsTable() &&
+			res.DateTime.Format("2006-01-02") == date {
+			tableAvail.IsAvailable = false
+			tableAvail.ReservationID = res.ID
+			break
+		}
+	}
+
+	if tableAvail.IsAvailable {
+		log.Printf("Marking table as AVAILABLE: %s", tableAvail.ID)
+	} else {
+		log.Printf("Table still held by reservation %s, keeping it UNAVAILABLE: %s", tableAvail.ReservationID, tableAvail.ID)
+	}
+	return s.repo.Update(ctx, *tableAvail)
+}
+
+// HandleHold marks a table as held while a guest completes the booking (hold), and
+// available again once the hold expires (release). A table booked meanwhile stays taken.
+func (s *SyncService) HandleHold(ctx context.Context, op string, hold domain.HoldEvent) error {
+	date := hold.DateTime.Format("2006-01-02")
+	capacity := tableCapacityOrDefault(hold.TableNumber, hold.MealType)
+	tableAvail := domain.NewTableAvailability(hold.TableNumber, capacity, hold.MealType, date)
+
+	switch op {
+	case "hold":
+		expiresAt := hold.ExpiresAt
+		tableAvail.IsAvailable = false
+		tableAvail.HeldUntil = &expiresAt
+		log.Printf("Marking table as HELD until %s: %s", expiresAt.Format(time.RFC3339), tableAvail.ID)
+
+	case "release":
+		if current, err := s.repo.GetByID(ctx, tableAvail.ID); err == nil && current != nil && current.ReservationID != "" {
+			log.Printf("Release: table already reserved, keeping it UNAVAILABLE: %s", tableAvail.ID)
+			return nil
+		}
+		tableAvail.IsAvailable = true
+		log.Printf("Marking table as AVAILABLE (hold expired): %s", tableAvail.ID)
+
+	default:
+		log.Printf("Unknown hold operation: %s", op)
+		return nil
+	}
+
+	if err := s.repo.Update(ctx, *tableAvail); err != nil {
+		log.Printf("ERROR: Failed to update Solr for table %s: %v", tableAvail.ID, err)
+		return err
+	}
+	if s.cache != nil {
+		s.cache.Clear()
+	}
+	return nil
+}
+
+// tableCapacityOrDefault returns the capacity of a table, or 4 for an unknown one
+func tableCapacityOrDefault(tableNumber int, mealType string) int {
+	capacity, found := getTableCapacity(tableNumber, mealType)
+	if !found {
+		log.Printf("WARNING: Unknown table config for table %d, meal_type %s. Using default capacity 4", tableNumber, mealType)
+		return 4
+	}
+	return capacity
+}
+
 // getTableCapacity returns the capacity for a given table number and meal type
 func getTableCapacity(tableNumber int, mealType string) (int, bool) {
 	// Predefined table capacities (same as in reservations-api)
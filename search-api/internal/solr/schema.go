@@ -9,6 +9,7 @@ const (
 	FieldDate          = "date"
 	FieldIsAvailable   = "is_available"
 	FieldReservationID = "reservation_id"
+	FieldHeldUntil     = "held_until"
 	FieldCreatedAt     = "created_at"
 	FieldUpdatedAt     = "updated_at"
 )
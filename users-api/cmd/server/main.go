@@ -9,6 +9,7 @@ import (
 	"github.com/blassardoy/restaurant-reservas/users-api/internal/crypto"
 	"github.com/blassardoy/restaurant-reservas/users-api/internal/db"
 	"github.com/blassardoy/restaurant-reservas/users-api/internal/domain"
+	"github.com/blassardoy/restaurant-reservas/users-api/internal/events"
 	"github.com/blassardoy/restaurant-reservas/users-api/internal/repository"
 	servicepkg "github.com/blassardoy/restaurant-reservas/users-api/internal/service"
 	httptransport "github.com/blassardoy/restaurant-reservas/users-api/internal/transport/http"
@@ -43,10 +44,23 @@ func main() {
 	userRepo := repository.NewUserRepository(gdb)
 	hasher := crypto.NewBcryptHasher(0)
 	issuer := auth.NewJWTIssuer(cfg.JWTSecret, cfg.JWTAccessTTL, cfg.JWTRefreshTTL)
-	service := servicepkg.NewUserService(userRepo, hasher, issuer)
 
-	// HTTP router with JWT secret for middleware
-	r := httptransport.NewRouterWithConfig(service, cfg.JWTSecret)
+	// user.deleted events let the other services erase the user's data
+	var service *servicepkg.UserService
+	if cfg.RabbitMQURI != "" {
+		publisher, err := events.NewRabbitMQPublisher(cfg.RabbitMQURI, cfg.RabbitMQExchange)
+		if err != nil {
+			log.Fatalf("rabbitmq connection error: %v", err)
+		}
+		defer publisher.Close()
+		service = servicepkg.NewUserServiceWithEvents(userRepo, hasher, issuer, publisher)
+	} else {
+		log.Println("RABBITMQ_URI not set: user.deleted events will only be logged")
+		service = servicepkg.NewUserService(userRepo, hasher, issuer)
+	}
+
+	// HTTP router with JWT secret and service token for middleware
+	r := httptransport.NewRouterWithConfig(service, cfg.JWTSecret, cfg.ServiceToken)
 
 	log.Println("users-api escuchando en :8080")
 	log.Fatal(http.ListenAndServe(":8080", r))
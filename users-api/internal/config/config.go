@@ -19,6 +19,13 @@ type AppConfig struct {
 	JWTSecret     string
 	JWTAccessTTL  time.Duration
 	JWTRefreshTTL time.Duration
+	// ServiceToken is shared with the other microservices to read user contact
+	// details; empty leaves them to admins and the users themselves
+	ServiceToken string
+
+	// RabbitMQ (user.deleted events); empty URI only logs the events
+	RabbitMQURI      string
+	RabbitMQExchange string
 }
 
 func getenv(key, def string) string {
@@ -56,5 +63,9 @@ func FromEnv() AppConfig {
 		JWTSecret:     getenv("JWT_SECRET", "dev-secret"),
 		JWTAccessTTL:  accessTTL,
 		JWTRefreshTTL: refreshTTL,
+		ServiceToken:  getenv("SERVICE_TOKEN", ""),
+
+		RabbitMQURI:      getenv("RABBITMQ_URI", ""),
+		RabbitMQExchange: getenv("RABBITMQ_EXCHANGE", "restaurant_events"),
 	}
 }
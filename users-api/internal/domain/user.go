@@ -31,6 +31,7 @@ type User struct {
 	Email        string    `json:"email" gorm:"size:191;uniqueIndex:ux_users_email;not null"`
 	FirstName    string    `json:"first_name" gorm:"size:100;not null"`
 	LastName     string    `json:"last_name" gorm:"size:100;not null"`
+	Phone        string    `json:"phone,omitempty" gorm:"size:32"` // opcional, lo usa el restaurante para contactar
 	PasswordHash string    `json:"-" gorm:"size:255;not null"` // nunca exponer
 	Role         Role      `json:"role" gorm:"type:varchar(20);default:user;not null"`
 	CreatedAt    time.Time `json:"created_at" gorm:"autoCreateTime"`
@@ -50,6 +51,7 @@ type RegisterInput struct {
 	Email     string `json:"email"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Phone     string `json:"phone"`
 	Password  string `json:"password"`
 	// Rol fijo a user en registro público; admin sólo por backoffice/seeding.
 }
@@ -80,13 +82,18 @@ var (
 var (
 	emailRx    = regexp.MustCompile(`^[^\s@]+@[^\s@]+\.[^\s@]+$`)
 	usernameRx = regexp.MustCompile(`^[a-zA-Z0-9._-]{3,32}$`)
+	phoneRx    = regexp.MustCompile(`^\+?[0-9][0-9 ()-]{5,30}$`)
 )
 
+// MaxBatchUsers acota cuántos usuarios se pueden pedir en una sola consulta por IDs
+const MaxBatchUsers = 100
+
 func (in *RegisterInput) Normalize() {
 	in.Email = strings.ToLower(strings.TrimSpace(in.Email))
 	in.Username = strings.TrimSpace(in.Username)
 	in.FirstName = strings.TrimSpace(in.FirstName)
 	in.LastName = strings.TrimSpace(in.LastName)
+	in.Phone = strings.TrimSpace(in.Phone)
 }
 
 func (in RegisterInput) Validate() error {
@@ -99,6 +106,9 @@ func (in RegisterInput) Validate() error {
 	if len(in.Password) < 8 { // mínimo razonable
 		return ErrInvalidInput
 	}
+	if in.Phone != "" && !phoneRx.MatchString(in.Phone) {
+		return ErrInvalidInput
+	}
 	return nil
 }
 
@@ -123,11 +133,16 @@ type UserRepository interface {
 	GetByID(ctx context.Context, id uint64) (User, error)
 	GetByEmail(ctx context.Context, email string) (User, error)
 	GetByUsername(ctx context.Context, username string) (User, error)
+	// GetByIDs devuelve los usuarios que existen entre ids (los que no, se omiten)
+	GetByIDs(ctx context.Context, ids []uint64) ([]User, error)
 
 	// Altas/updates
 	Create(ctx context.Context, u *User) error
 	UpdatePasswordHash(ctx context.Context, id uint64, newHash string) error
 
+	// Baja definitiva (borrado físico, sin soft delete)
+	Delete(ctx context.Context, id uint64) error
+
 	// Conflictos
 	ExistsByEmailOrUsername(ctx context.Context, email, username string) (bool, error)
 }
@@ -144,6 +159,12 @@ type TokenIssuer interface {
 	IssueRefreshToken(u User) (token string, err error) // opcional según enunciado
 }
 
+// Eventos hacia otros microservicios (infra los publica en RabbitMQ)
+type EventPublisher interface {
+	// PublishUserDeleted avisa que la cuenta fue borrada para que cada servicio borre sus datos
+	PublishUserDeleted(ctx context.Context, id uint64, deletedAt time.Time) error
+}
+
 // Caso de uso (aplicación)
 type UserService interface {
 	// Registro público: crea usuario RoleUser y devuelve entidad (sin tokens).
@@ -155,8 +176,14 @@ type UserService interface {
 	// GetByID obtiene un usuario por ID (usado por otros microservicios para validación).
 	GetByID(ctx context.Context, id uint64) (User, error)
 
+	// GetByIDs obtiene varios usuarios en una sola consulta (hasta MaxBatchUsers).
+	GetByIDs(ctx context.Context, ids []uint64) ([]User, error)
+
 	// CreateAdmin crea un usuario admin (solo para admin).
 	CreateAdmin(ctx context.Context, in RegisterInput) (User, error)
+
+	// Delete borra la cuenta (derecho al olvido) y publica user.deleted.
+	Delete(ctx context.Context, id uint64) error
 }
 
 // =========================
@@ -170,6 +197,7 @@ func NewUserFromRegister(in RegisterInput, passwordHash string) User {
 		Email:        strings.ToLower(in.Email),
 		FirstName:    in.FirstName,
 		LastName:     in.LastName,
+		Phone:        in.Phone,
 		PasswordHash: passwordHash,
 		Role:         RoleUser,
 		CreatedAt:    now,
@@ -0,0 +1,108 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/blassardoy/restaurant-reservas/users-api/internal/domain"
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// Message is the event envelope shared with the other services' RabbitMQ consumers
+type Message struct {
+	Operation  string    `json:"operation"`
+	EntityID   string    `json:"entity_id"`
+	EntityType string    `json:"entity_type"`
+	Timestamp  time.Time `json:"timestamp"`
+}
+
+// RabbitMQPublisher publishes user events to the topic exchange shared with the other services.
+type RabbitMQPublisher struct {
+	conn     *amqp.Connection
+	mu       sync.Mutex // one confirm at a time on the channel
+	channel  *amqp.Channel
+	exchange string
+}
+
+func NewRabbitMQPublisher(uri, exchange string) (*RabbitMQPublisher, error) {
+	// Retry dial with simple backoff to tolerate container startup time
+	var (
+		conn *amqp.Connection
+		err  error
+	)
+	for attempt := 1; attempt <= 10; attempt++ {
+		conn, err = amqp.Dial(uri)
+		if err == nil {
+			break
+		}
+		wait := time.Duration(attempt) * 500 * time.Millisecond
+		log.Printf("RabbitMQ not ready (attempt %d): %v; retrying in %s", attempt, err, wait)
+		time.Sleep(wait)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ after retries: %w", err)
+	}
+
+	channel, err := conn.Channel()
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to open channel: %w", err)
+	}
+	if err := channel.ExchangeDeclare(exchange, "topic", true, false, false, false, nil); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to declare exchange: %w", err)
+	}
+	// Publisher confirms: a deletion only proceeds once the broker has the event
+	if err := channel.Confirm(false); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
+	}
+
+	return &RabbitMQPublisher{conn: conn, channel: channel, exchange: exchange}, nil
+}
+
+// PublishUserDeleted publishes a persistent user.deleted event and waits for the broker to confirm it.
+func (p *RabbitMQPublisher) PublishUserDeleted(ctx context.Context, id uint64, deletedAt time.Time) error {
+	body, err := json.Marshal(Message{
+		Operation:  "deleted",
+		EntityID:   strconv.FormatUint(id, 10),
+		EntityType: "user",
+		Timestamp:  deletedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	confirm, err := p.channel.PublishWithDeferredConfirmWithContext(ctx, p.exchange, "user.deleted", false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Body:         body,
+		Timestamp:    deletedAt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish user.deleted: %w", err)
+	}
+	acked, err := confirm.WaitContext(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to confirm user.deleted: %w", err)
+	}
+	if !acked {
+		return fmt.Errorf("broker rejected user.deleted for user %d", id)
+	}
+	return nil
+}
+
+func (p *RabbitMQPublisher) Close() error {
+	return p.conn.Close()
+}
+
+// Ensure interface compliance at compile time.
+var _ domain.EventPublisher = (*RabbitMQPublisher)(nil)
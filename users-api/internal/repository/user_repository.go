@@ -30,6 +30,17 @@ func (r *GormUserRepository) GetByID(ctx context.Context, id uint64) (domain.Use
 	return u, nil
 }
 
+func (r *GormUserRepository) GetByIDs(ctx context.Context, ids []uint64) ([]domain.User, error) {
+	users := []domain.User{}
+	if len(ids) == 0 {
+		return users, nil
+	}
+	if err := r.db.WithContext(ctx).Where("id IN ?", ids).Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
 func (r *GormUserRepository) GetByEmail(ctx context.Context, email string) (domain.User, error) {
 	var u domain.User
 	tx := r.db.WithContext(ctx).
@@ -77,6 +88,17 @@ func (r *GormUserRepository) UpdatePasswordHash(ctx context.Context, id uint64,
 	return tx.Error
 }
 
+func (r *GormUserRepository) Delete(ctx context.Context, id uint64) error {
+	tx := r.db.WithContext(ctx).Delete(&domain.User{}, id)
+	if tx.Error != nil {
+		return tx.Error
+	}
+	if tx.RowsAffected == 0 {
+		return domain.ErrUserNotFound
+	}
+	return nil
+}
+
 func (r *GormUserRepository) ExistsByEmailOrUsername(ctx context.Context, email, username string) (bool, error) {
 	var count int64
 	tx := r.db.WithContext(ctx).
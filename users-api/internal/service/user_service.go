@@ -3,7 +3,9 @@ package service
 
 import (
 	"context"
+	"log"
 	"strings"
+	"time"
 
 	"github.com/blassardoy/restaurant-reservas/users-api/internal/domain"
 )
@@ -13,13 +15,21 @@ type UserService struct {
 	userRepository domain.UserRepository
 	passwordHasher domain.PasswordHasher
 	tokenIssuer    domain.TokenIssuer
+	events         domain.EventPublisher
 }
 
+// NewUserService creates a service whose events are only logged (tests, local runs without RabbitMQ).
 func NewUserService(repo domain.UserRepository, hasher domain.PasswordHasher, issuer domain.TokenIssuer) *UserService {
+	return NewUserServiceWithEvents(repo, hasher, issuer, logEventPublisher{})
+}
+
+// NewUserServiceWithEvents creates a service that announces account changes to other services.
+func NewUserServiceWithEvents(repo domain.UserRepository, hasher domain.PasswordHasher, issuer domain.TokenIssuer, events domain.EventPublisher) *UserService {
 	return &UserService{
 		userRepository: repo,
 		passwordHasher: hasher,
 		tokenIssuer:    issuer,
+		events:         events,
 	}
 }
 
@@ -101,6 +111,15 @@ func (s *UserService) GetByID(ctx context.Context, id uint64) (domain.User, erro
 	return user, nil
 }
 
+// GetByIDs retrieves several users at once (used by other microservices to avoid
+// one request per user). Unknown IDs are left out of the result.
+func (s *UserService) GetByIDs(ctx context.Context, ids []uint64) ([]domain.User, error) {
+	if len(ids) > domain.MaxBatchUsers {
+		return nil, domain.ErrInvalidInput
+	}
+	return s.userRepository.GetByIDs(ctx, ids)
+}
+
 // CreateAdmin creates a new admin user (only for admins).
 func (s *UserService) CreateAdmin(ctx context.Context, in domain.RegisterInput) (domain.User, error) {
 	in.Normalize()
@@ -130,5 +149,27 @@ func (s *UserService) CreateAdmin(ctx context.Context, in domain.RegisterInput)
 	return user, nil
 }
 
+// Delete erases a user account. The user.deleted event is published before the row is
+// removed: if the delete fails the caller retries and the event is published again, which
+// consumers must tolerate, whereas a lost event would leave the user's data behind.
+func (s *UserService) Delete(ctx context.Context, id uint64) error {
+	if _, err := s.userRepository.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	if err := s.events.PublishUserDeleted(ctx, id, time.Now().UTC()); err != nil {
+		return err
+	}
+	return s.userRepository.Delete(ctx, id)
+}
+
+// logEventPublisher stands in for RabbitMQ when no broker is configured.
+type logEventPublisher struct{}
+
+func (logEventPublisher) PublishUserDeleted(_ context.Context, id uint64, _ time.Time) error {
+	log.Printf("user.deleted %d not published: no event publisher configured", id)
+	return nil
+}
+
 // Ensure interface compliance at compile time.
 var _ domain.UserService = (*UserService)(nil)
@@ -12,11 +12,13 @@ import (
 // Mock implementations
 
 type mockUserRepository struct {
-	getUserByIDFunc              func(ctx context.Context, id uint64) (domain.User, error)
-	getUserByEmailFunc           func(ctx context.Context, email string) (domain.User, error)
-	getUserByUsernameFunc        func(ctx context.Context, username string) (domain.User, error)
-	createFunc                   func(ctx context.Context, u *domain.User) error
-	existsByEmailOrUsernameFunc  func(ctx context.Context, email, username string) (bool, error)
+	getUserByIDFunc             func(ctx context.Context, id uint64) (domain.User, error)
+	getUserByEmailFunc          func(ctx context.Context, email string) (domain.User, error)
+	getUserByUsernameFunc       func(ctx context.Context, username string) (domain.User, error)
+	getByIDsFunc                func(ctx context.Context, ids []uint64) ([]domain.User, error)
+	createFunc                  func(ctx context.Context, u *domain.User) error
+	existsByEmailOrUsernameFunc func(ctx context.Context, email, username string) (bool, error)
+	deleteFunc                  func(ctx context.Context, id uint64) error
 }
 
 func (m *mockUserRepository) GetByID(ctx context.Context, id uint64) (domain.User, error) {
@@ -40,6 +42,13 @@ func (m *mockUserRepository) GetByUsername(ctx context.Context, username string)
 	return domain.User{}, errors.New("not implemented")
 }
 
+func (m *mockUserRepository) GetByIDs(ctx context.Context, ids []uint64) ([]domain.User, error) {
+	if m.getByIDsFunc != nil {
+		return m.getByIDsFunc(ctx, ids)
+	}
+	return nil, errors.New("not implemented")
+}
+
 func (m *mockUserRepository) Create(ctx context.Context, u *domain.User) error {
 	if m.createFunc != nil {
 		return m.createFunc(ctx, u)
@@ -51,6 +60,13 @@ func (m *mockUserRepository) UpdatePasswordHash(ctx context.Context, id uint64,
 	return nil
 }
 
+func (m *mockUserRepository) Delete(ctx context.Context, id uint64) error {
+	if m.deleteFunc != nil {
+		return m.deleteFunc(ctx, id)
+	}
+	return nil
+}
+
 func (m *mockUserRepository) ExistsByEmailOrUsername(ctx context.Context, email, username string) (bool, error) {
 	if m.existsByEmailOrUsernameFunc != nil {
 		return m.existsByEmailOrUsernameFunc(ctx, email, username)
@@ -97,6 +113,17 @@ func (m *mockTokenIssuer) IssueRefreshToken(u domain.User) (string, error) {
 	return "refresh_token_" + u.Username, nil
 }
 
+type mockEventPublisher struct {
+	publishUserDeletedFunc func(ctx context.Context, id uint64, deletedAt time.Time) error
+}
+
+func (m *mockEventPublisher) PublishUserDeleted(ctx context.Context, id uint64, deletedAt time.Time) error {
+	if m.publishUserDeletedFunc != nil {
+		return m.publishUserDeletedFunc(ctx, id, deletedAt)
+	}
+	return nil
+}
+
 // Tests
 
 func TestUserService_Register_Success(t *testing.T) {
@@ -218,6 +245,17 @@ func TestUserService_Register_InvalidInput(t *testing.T) {
 				Password:  "password123",
 			},
 		},
+		{
+			name: "invalid phone",
+			input: domain.RegisterInput{
+				Username:  "testuser",
+				Email:     "test@example.com",
+				FirstName: "Test",
+				LastName:  "User",
+				Phone:     "call me",
+				Password:  "password123",
+			},
+		},
 	}
 
 	for _, tc := range testCases {
@@ -441,6 +479,22 @@ func TestUserService_GetByID_NotFound(t *testing.T) {
 	}
 }
 
+func TestUserService_GetByIDs_TooMany(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		getByIDsFunc: func(ctx context.Context, ids []uint64) ([]domain.User, error) {
+			t.Fatal("repository called for an oversized batch")
+			return nil, nil
+		},
+	}
+
+	service := NewUserService(mockRepo, &mockPasswordHasher{}, &mockTokenIssuer{})
+
+	ids := make([]uint64, domain.MaxBatchUsers+1)
+	if _, err := service.GetByIDs(context.Background(), ids); err != domain.ErrInvalidInput {
+		t.Fatalf("expected ErrInvalidInput, got %v", err)
+	}
+}
+
 func TestUserService_CreateAdmin_Success(t *testing.T) {
 	mockRepo := &mockUserRepository{
 		existsByEmailOrUsernameFunc: func(ctx context.Context, email, username string) (bool, error) {
@@ -479,3 +533,83 @@ func TestUserService_CreateAdmin_Success(t *testing.T) {
 		t.Errorf("expected username 'adminuser', got %s", user.Username)
 	}
 }
+
+func TestUserService_Delete_PublishesBeforeDeleting(t *testing.T) {
+	var calls []string
+	mockRepo := &mockUserRepository{
+		getUserByIDFunc: func(ctx context.Context, id uint64) (domain.User, error) {
+			return domain.User{ID: id}, nil
+		},
+		deleteFunc: func(ctx context.Context, id uint64) error {
+			calls = append(calls, "delete")
+			return nil
+		},
+	}
+	mockEvents := &mockEventPublisher{
+		publishUserDeletedFunc: func(ctx context.Context, id uint64, deletedAt time.Time) error {
+			if id != 7 {
+				t.Errorf("expected user 7 in event, got %d", id)
+			}
+			calls = append(calls, "publish")
+			return nil
+		},
+	}
+
+	service := NewUserServiceWithEvents(mockRepo, &mockPasswordHasher{}, &mockTokenIssuer{}, mockEvents)
+
+	if err := service.Delete(context.Background(), 7); err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	if len(calls) != 2 || calls[0] != "publish" || calls[1] != "delete" {
+		t.Errorf("expected publish then delete, got %v", calls)
+	}
+}
+
+func TestUserService_Delete_KeepsUserWhenPublishFails(t *testing.T) {
+	deleted := false
+	mockRepo := &mockUserRepository{
+		getUserByIDFunc: func(ctx context.Context, id uint64) (domain.User, error) {
+			return domain.User{ID: id}, nil
+		},
+		deleteFunc: func(ctx context.Context, id uint64) error {
+			deleted = true
+			return nil
+		},
+	}
+	mockEvents := &mockEventPublisher{
+		publishUserDeletedFunc: func(ctx context.Context, id uint64, deletedAt time.Time) error {
+			return errors.New("broker unavailable")
+		},
+	}
+
+	service := NewUserServiceWithEvents(mockRepo, &mockPasswordHasher{}, &mockTokenIssuer{}, mockEvents)
+
+	if err := service.Delete(context.Background(), 7); err == nil {
+		t.Fatal("expected error, got nil")
+	}
+
+	if deleted {
+		t.Error("expected user to be kept when the event could not be published")
+	}
+}
+
+func TestUserService_Delete_NotFound(t *testing.T) {
+	mockRepo := &mockUserRepository{
+		getUserByIDFunc: func(ctx context.Context, id uint64) (domain.User, error) {
+			return domain.User{}, domain.ErrUserNotFound
+		},
+	}
+	mockEvents := &mockEventPublisher{
+		publishUserDeletedFunc: func(ctx context.Context, id uint64, deletedAt time.Time) error {
+			t.Error("expected no event for a missing user")
+			return nil
+		},
+	}
+
+	service := NewUserServiceWithEvents(mockRepo, &mockPasswordHasher{}, &mockTokenIssuer{}, mockEvents)
+
+	if err := service.Delete(context.Background(), 99); err != domain.ErrUserNotFound {
+		t.Errorf("expected ErrUserNotFound, got %v", err)
+	}
+}
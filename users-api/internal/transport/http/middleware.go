@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
 	"net/http"
 	"strings"
 
@@ -16,75 +17,132 @@ const (
 	userIDKey   contextKey = "user_id"
 	usernameKey contextKey = "username"
 	roleKey     contextKey = "role"
+	serviceKey  contextKey = "service"
 )
 
+// ServiceTokenHeader carries the token other services present to read user data
+const ServiceTokenHeader = "X-Service-Token"
+
 // JWTMiddleware validates the JWT token and adds user info to context
 type JWTMiddleware struct {
 	jwtSecret []byte
+	// serviceToken is shared with the other microservices; empty disables service access
+	serviceToken []byte
 }
 
-func NewJWTMiddleware(secret string) *JWTMiddleware {
-	return &JWTMiddleware{jwtSecret: []byte(secret)}
+func NewJWTMiddleware(secret, serviceToken string) *JWTMiddleware {
+	return &JWTMiddleware{jwtSecret: []byte(secret), serviceToken: []byte(serviceToken)}
 }
 
 // Authenticate is the middleware function that validates JWT
 func (m *JWTMiddleware) Authenticate(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		// Get token from Authorization header
-		authHeader := r.Header.Get("Authorization")
-		if authHeader == "" {
-			http.Error(w, `{"error":"missing_authorization_header"}`, http.StatusUnauthorized)
+		ctx, errCode := m.authenticate(r)
+		if errCode != "" {
+			http.Error(w, `{"error":"`+errCode+`"}`, http.StatusUnauthorized)
 			return
 		}
 
-		// Expected format: "Bearer <token>"
-		parts := strings.Split(authHeader, " ")
-		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
-			http.Error(w, `{"error":"invalid_authorization_format"}`, http.StatusUnauthorized)
+		// Call next handler with updated context
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// Identify adds the caller to the context when it is another service or a user
+// with a valid token, and lets anonymous requests through
+func (m *JWTMiddleware) Identify(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if m.isService(r) {
+			next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), serviceKey, true)))
 			return
 		}
+		if ctx, errCode := m.authenticate(r); errCode == "" {
+			r = r.WithContext(ctx)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
 
-		tokenString := parts[1]
-
-		// Parse and validate token
-		token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-			// Validate signing method
-			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-				return nil, jwt.ErrSignatureInvalid
-			}
-			return m.jwtSecret, nil
-		})
-
-		if err != nil || !token.Valid {
-			http.Error(w, `{"error":"invalid_token"}`, http.StatusUnauthorized)
+// RequireServiceOrAdmin lets through other services and admins; it runs after Identify
+func (m *JWTMiddleware) RequireServiceOrAdmin(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if IsServiceRequest(r.Context()) {
+			next.ServeHTTP(w, r)
 			return
 		}
-
-		// Extract claims
-		claims, ok := token.Claims.(jwt.MapClaims)
+		role, ok := GetRoleFromContext(r.Context())
 		if !ok {
-			http.Error(w, `{"error":"invalid_token_claims"}`, http.StatusUnauthorized)
+			http.Error(w, `{"error":"authentication_required"}`, http.StatusUnauthorized)
 			return
 		}
-
-		// Extract user info from claims
-		userID, ok := claims["sub"].(float64) // JWT numbers are float64
-		if !ok {
-			http.Error(w, `{"error":"invalid_user_id_in_token"}`, http.StatusUnauthorized)
+		if role != domain.RoleAdmin {
+			http.Error(w, `{"error":"admin_access_required"}`, http.StatusForbidden)
 			return
 		}
 
-		username, _ := claims["username"].(string)
-		role, _ := claims["role"].(string)
+		next.ServeHTTP(w, r)
+	})
+}
 
-		// Add user info to context
-		ctx := context.WithValue(r.Context(), userIDKey, uint64(userID))
-		ctx = context.WithValue(ctx, usernameKey, username)
-		ctx = context.WithValue(ctx, roleKey, domain.Role(role))
+// isService reports whether the request carries the service token
+func (m *JWTMiddleware) isService(r *http.Request) bool {
+	token := r.Header.Get(ServiceTokenHeader)
+	if len(m.serviceToken) == 0 || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(token), m.serviceToken) == 1
+}
 
-		// Call next handler with updated context
-		next.ServeHTTP(w, r.WithContext(ctx))
+// authenticate validates the bearer token and returns the context with the user
+// info, or the error code when the token is missing or invalid
+func (m *JWTMiddleware) authenticate(r *http.Request) (context.Context, string) {
+	// Get token from Authorization header
+	authHeader := r.Header.Get("Authorization")
+	if authHeader == "" {
+		return nil, "missing_authorization_header"
+	}
+
+	// Expected format: "Bearer <token>"
+	parts := strings.Split(authHeader, " ")
+	if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+		return nil, "invalid_authorization_format"
+	}
+
+	tokenString := parts[1]
+
+	// Parse and validate token
+	token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
+		// Validate signing method
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, jwt.ErrSignatureInvalid
+		}
+		return m.jwtSecret, nil
 	})
+
+	if err != nil || !token.Valid {
+		return nil, "invalid_token"
+	}
+
+	// Extract claims
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return nil, "invalid_token_claims"
+	}
+
+	// Extract user info from claims
+	userID, ok := claims["sub"].(float64) // JWT numbers are float64
+	if !ok {
+		return nil, "invalid_user_id_in_token"
+	}
+
+	username, _ := claims["username"].(string)
+	role, _ := claims["role"].(string)
+
+	// Add user info to context
+	ctx := context.WithValue(r.Context(), userIDKey, uint64(userID))
+	ctx = context.WithValue(ctx, usernameKey, username)
+	ctx = context.WithValue(ctx, roleKey, domain.Role(role))
+	return ctx, ""
 }
 
 // RequireAdmin is a middleware that checks if the user is an admin
@@ -124,3 +182,9 @@ func GetRoleFromContext(ctx context.Context) (domain.Role, bool) {
 	role, ok := ctx.Value(roleKey).(domain.Role)
 	return role, ok
 }
+
+// IsServiceRequest reports whether the request came from another service
+func IsServiceRequest(ctx context.Context) bool {
+	service, _ := ctx.Value(serviceKey).(bool)
+	return service
+}
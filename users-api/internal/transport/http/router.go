@@ -1,9 +1,11 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
@@ -18,14 +20,14 @@ type Router struct {
 }
 
 func NewRouter(svc domain.UserService) http.Handler {
-	return NewRouterWithConfig(svc, "dev-secret")
+	return NewRouterWithConfig(svc, "dev-secret", "")
 }
 
-func NewRouterWithConfig(svc domain.UserService, jwtSecret string) http.Handler {
+func NewRouterWithConfig(svc domain.UserService, jwtSecret, serviceToken string) http.Handler {
 	rt := &Router{
 		svc:            svc,
 		jwtSecret:      jwtSecret,
-		authMiddleware: NewJWTMiddleware(jwtSecret),
+		authMiddleware: NewJWTMiddleware(jwtSecret, serviceToken),
 	}
 
 	r := chi.NewRouter()
@@ -45,7 +47,13 @@ func NewRouterWithConfig(svc domain.UserService, jwtSecret string) http.Handler
 	r.Route("/api", func(r chi.Router) {
 		r.Post("/users/register", rt.register)
 		r.Post("/users/login", rt.login)
-		r.Get("/users/{id}", rt.getUserByID) // Public for inter-service communication
+		// Lookups: contact details only for other services, admins and the user
+		// themselves; the batched lookup (?ids=1,2,3) only for services and admins
+		r.With(rt.authMiddleware.Identify).Get("/users/{id}", rt.getUserByID)
+		r.With(rt.authMiddleware.Identify, rt.authMiddleware.RequireServiceOrAdmin).Get("/users", rt.getUsersByIDs)
+
+		// Account erasure by the account owner
+		r.With(rt.authMiddleware.Authenticate).Delete("/users/me", rt.deleteMe)
 	})
 
 	// Protected routes (authentication required)
@@ -53,7 +61,8 @@ func NewRouterWithConfig(svc domain.UserService, jwtSecret string) http.Handler
 		r.Use(rt.authMiddleware.Authenticate)
 		r.Use(rt.authMiddleware.RequireAdmin)
 
-		r.Post("/users", rt.createAdmin) // Create admin user
+		r.Post("/users", rt.createAdmin)       // Create admin user
+		r.Delete("/users/{id}", rt.deleteUser) // Erase any account
 	})
 
 	// Legacy routes (backward compatibility)
@@ -94,6 +103,7 @@ func (rt *Router) register(w http.ResponseWriter, r *http.Request) {
 		Email:     in.Email,
 		FirstName: in.FirstName,
 		LastName:  in.LastName,
+		Phone:     in.Phone,
 		Password:  in.Password,
 	}
 	u, err := rt.svc.Register(r.Context(), req)
@@ -163,13 +173,59 @@ func (rt *Router) getUserByID(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Never expose password hash
+	// Never expose password hash, nor contact details to other users
 	user.PasswordHash = ""
+	if !canSeeContact(r.Context(), user.ID) {
+		user.Email = ""
+		user.Phone = ""
+	}
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(http.StatusOK)
 	_ = json.NewEncoder(w).Encode(user)
 }
 
+// getUsersByIDs returns the users listed in ?ids= (comma separated, at most
+// domain.MaxBatchUsers). Unknown IDs, and values that are not user IDs, are left out.
+func (rt *Router) getUsersByIDs(w http.ResponseWriter, r *http.Request) {
+	var ids []uint64
+	for _, s := range strings.Split(r.URL.Query().Get("ids"), ",") {
+		if id, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64); err == nil {
+			ids = append(ids, id)
+		}
+	}
+
+	users, err := rt.svc.GetByIDs(r.Context(), ids)
+	if err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrInvalidInput {
+			status = http.StatusBadRequest
+		}
+		respondError(w, status, err.Error())
+		return
+	}
+
+	// Never expose password hashes
+	for i := range users {
+		users[i].PasswordHash = ""
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	_ = json.NewEncoder(w).Encode(users)
+}
+
+// canSeeContact reports whether the caller may read the email and phone of the
+// user: other services, admins and the user themselves
+func canSeeContact(ctx context.Context, userID uint64) bool {
+	if IsServiceRequest(ctx) {
+		return true
+	}
+	if role, ok := GetRoleFromContext(ctx); ok && role == domain.RoleAdmin {
+		return true
+	}
+	callerID, ok := GetUserIDFromContext(ctx)
+	return ok && callerID == userID
+}
+
 // createAdmin creates a new admin user (requires admin authentication)
 func (rt *Router) createAdmin(w http.ResponseWriter, r *http.Request) {
 	var in registerRequest
@@ -183,6 +239,7 @@ func (rt *Router) createAdmin(w http.ResponseWriter, r *http.Request) {
 		Email:     in.Email,
 		FirstName: in.FirstName,
 		LastName:  in.LastName,
+		Phone:     in.Phone,
 		Password:  in.Password,
 	}
 
@@ -208,6 +265,38 @@ func (rt *Router) createAdmin(w http.ResponseWriter, r *http.Request) {
 	_ = json.NewEncoder(w).Encode(user)
 }
 
+// deleteMe erases the authenticated user's own account
+func (rt *Router) deleteMe(w http.ResponseWriter, r *http.Request) {
+	id, ok := GetUserIDFromContext(r.Context())
+	if !ok {
+		respondError(w, http.StatusUnauthorized, "invalid_user_id_in_token")
+		return
+	}
+	rt.deleteAccount(w, r, id)
+}
+
+// deleteUser erases an account by ID (requires admin authentication)
+func (rt *Router) deleteUser(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseUint(chi.URLParam(r, "id"), 10, 64)
+	if err != nil {
+		respondError(w, http.StatusBadRequest, "invalid_user_id")
+		return
+	}
+	rt.deleteAccount(w, r, id)
+}
+
+func (rt *Router) deleteAccount(w http.ResponseWriter, r *http.Request, id uint64) {
+	if err := rt.svc.Delete(r.Context(), id); err != nil {
+		status := http.StatusInternalServerError
+		if err == domain.ErrUserNotFound {
+			status = http.StatusNotFound
+		}
+		respondError(w, status, err.Error())
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func respondError(w http.ResponseWriter, status int, message string) {
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(status)
@@ -0,0 +1,128 @@
+package http
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"github.com/blassardoy/restaurant-reservas/users-api/internal/domain"
+)
+
+const (
+	testJWTSecret    = "test-secret"
+	testServiceToken = "test-service-token"
+)
+
+// fakeUserService serves user 7; the rest of the service is not used
+type fakeUserService struct {
+	domain.UserService
+}
+
+func (fakeUserService) GetByID(_ context.Context, id uint64) (domain.User, error) {
+	if id != 7 {
+		return domain.User{}, domain.ErrUserNotFound
+	}
+	return domain.User{ID: 7, Username: "ana", Email: "ana@example.com", Phone: "+54 11 5555 0000", PasswordHash: "hash"}, nil
+}
+
+func (s fakeUserService) GetByIDs(ctx context.Context, ids []uint64) ([]domain.User, error) {
+	var users []domain.User
+	for _, id := range ids {
+		if user, err := s.GetByID(ctx, id); err == nil {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func bearer(t *testing.T, userID uint64, role domain.Role) string {
+	t.Helper()
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{
+		"sub":  userID,
+		"role": string(role),
+		"exp":  time.Now().Add(time.Hour).Unix(),
+	})
+	signed, err := token.SignedString([]byte(testJWTSecret))
+	if err != nil {
+		t.Fatalf("sign token: %v", err)
+	}
+	return "Bearer " + signed
+}
+
+func TestUserLookupsHideContactDetails(t *testing.T) {
+	router := NewRouterWithConfig(fakeUserService{}, testJWTSecret, testServiceToken)
+
+	tests := []struct {
+		name        string
+		target      string
+		header      string
+		value       string
+		wantStatus  int
+		wantContact bool
+	}{
+		{"anonymous single lookup", "/api/users/7", "", "", http.StatusOK, false},
+		{"invalid token on single lookup", "/api/users/7", "Authorization", "Bearer nope", http.StatusOK, false},
+		{"another user", "/api/users/7", "Authorization", bearer(t, 8, domain.RoleUser), http.StatusOK, false},
+		{"the user themselves", "/api/users/7", "Authorization", bearer(t, 7, domain.RoleUser), http.StatusOK, true},
+		{"admin", "/api/users/7", "Authorization", bearer(t, 1, domain.RoleAdmin), http.StatusOK, true},
+		{"service", "/api/users/7", ServiceTokenHeader, testServiceToken, http.StatusOK, true},
+		{"wrong service token", "/api/users/7", ServiceTokenHeader, "guess", http.StatusOK, false},
+		{"anonymous batch lookup", "/api/users?ids=7", "", "", http.StatusUnauthorized, false},
+		{"user batch lookup", "/api/users?ids=7", "Authorization", bearer(t, 7, domain.RoleUser), http.StatusForbidden, false},
+		{"wrong service token on batch lookup", "/api/users?ids=7", ServiceTokenHeader, "guess", http.StatusUnauthorized, false},
+		{"admin batch lookup", "/api/users?ids=7", "Authorization", bearer(t, 1, domain.RoleAdmin), http.StatusOK, true},
+		{"service batch lookup", "/api/users?ids=7", ServiceTokenHeader, testServiceToken, http.StatusOK, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			router.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d (%s)", rec.Code, tt.wantStatus, rec.Body.String())
+			}
+			if rec.Code != http.StatusOK {
+				return
+			}
+
+			var users []domain.User
+			if tt.target == "/api/users/7" {
+				var user domain.User
+				if err := json.NewDecoder(rec.Body).Decode(&user); err != nil {
+					t.Fatalf("decode: %v", err)
+				}
+				users = append(users, user)
+			} else if err := json.NewDecoder(rec.Body).Decode(&users); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if len(users) != 1 || users[0].Username != "ana" {
+				t.Fatalf("users = %+v, want ana", users)
+			}
+			if hasContact := users[0].Email != "" && users[0].Phone != ""; hasContact != tt.wantContact {
+				t.Errorf("email %q, phone %q, want contact details: %v", users[0].Email, users[0].Phone, tt.wantContact)
+			}
+		})
+	}
+}
+
+func TestServiceTokenDisabledWhenEmpty(t *testing.T) {
+	router := NewRouterWithConfig(fakeUserService{}, testJWTSecret, "")
+
+	req := httptest.NewRequest(http.MethodGet, "/api/users?ids=7", nil)
+	req.Header.Set(ServiceTokenHeader, "")
+	rec := httptest.NewRecorder()
+	router.ServeHTTP(rec, req)
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status = %d, want 401 without a configured service token", rec.Code)
+	}
+}
@@ -29,6 +29,7 @@ type registerRequest struct {
 	Email     string `json:"email"`
 	FirstName string `json:"first_name"`
 	LastName  string `json:"last_name"`
+	Phone     string `json:"phone"`
 	Password  string `json:"password"`
 }
 
@@ -44,6 +45,7 @@ func (h *UsersHandler) Register(c *gin.Context) {
 		Email:     req.Email,
 		FirstName: req.FirstName,
 		LastName:  req.LastName,
+		Phone:     req.Phone,
 		Password:  req.Password,
 	}
 
@@ -70,6 +72,7 @@ func (h *UsersHandler) Register(c *gin.Context) {
 			"email":      user.Email,
 			"first_name": user.FirstName,
 			"last_name":  user.LastName,
+			"phone":      user.Phone,
 			"role":       user.Role,
 			"created_at": user.CreatedAt,
 			"updated_at": user.UpdatedAt,
@@ -118,6 +121,7 @@ func (h *UsersHandler) Login(c *gin.Context) {
 			"email":      user.Email,
 			"first_name": user.FirstName,
 			"last_name":  user.LastName,
+			"phone":      user.Phone,
 			"role":       user.Role,
 			"created_at": user.CreatedAt,
 			"updated_at": user.UpdatedAt,
@@ -12,6 +12,7 @@ USE users_db;
 --     email VARCHAR(191) UNIQUE NOT NULL,
 --     first_name VARCHAR(100) NOT NULL,
 --     last_name VARCHAR(100) NOT NULL,
+--     phone VARCHAR(32),
 --     password_hash VARCHAR(255) NOT NULL,
 --     role VARCHAR(20) DEFAULT 'user' NOT NULL,
 --     created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,